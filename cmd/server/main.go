@@ -1,31 +1,218 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
 
+	"github.com/golang2go/demo-app/weather-service-api/internal/accesslog"
+	"github.com/golang2go/demo-app/weather-service-api/internal/admin"
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
 	"github.com/golang2go/demo-app/weather-service-api/internal/config"
 	"github.com/golang2go/demo-app/weather-service-api/internal/handler"
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
 	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
 	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/store"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util"
 	"github.com/gorilla/mux"
 )
 
 func main() {
-	cfg := config.DefaultConfig()
-	weatherAPI := repo.NewWeatherAPI()
+	cfg := config.FromEnv()
+
+	// Every component logger, and the standard logger through slog's default, scrubs API keys
+	redactor := util.NewRedactor(cfg.SensitiveParams, cfg.SensitiveHeaders)
+	logging.Init(os.Stderr, logging.Options{Format: cfg.LogFormat, Redactor: redactor})
+	if err := logging.ApplyLevels(cfg.LogLevel); err != nil {
+		log.Fatalf("Invalid log level configuration: %v\n", err)
+	}
+	logging.SetSampling("access", cfg.AccessLogSampling, cfg.AccessLogSampleN)
+	slog.SetDefault(logging.Logger("main"))
+
+	// Every current observation fetched from upstream is recorded for the history endpoint. The recorders
+	// sit below the caches, so cached responses are not recorded again.
+	var historyStore history.Store = history.NewMemoryStore(cfg.HistoryRetention)
+	if cfg.HistoryDir != "" {
+		db, err := store.Open(cfg.HistoryDir, store.Options{
+			Retention:       cfg.HistoryRetention,
+			RawRetention:    cfg.HistoryHourlyAfter,
+			HourlyRetention: cfg.HistoryDailyAfter,
+			CompactEvery:    cfg.HistoryCompactEvery,
+		})
+		if err != nil {
+			log.Fatalf("Failed to open history store: %v\n", err)
+		}
+		defer db.Close()
+		historyStore = db
+	}
+
+	weatherAPI := repo.NewRecordingWeatherAPI(repo.NewWeatherAPI(repo.WithRedactor(redactor)), historyStore)
+	if cfg.CacheTTL > 0 {
+		weatherAPI = repo.NewCachedWeatherAPI(weatherAPI, cfg.CacheTTL, cfg.CacheMaxEntries)
+	}
 	weatherHandler := handler.NewWeatherHandler(weatherAPI, cfg)
 
+	forecastAPI := repo.NewForecastAPI(repo.WithRedactor(redactor))
+	if cfg.CacheTTL > 0 {
+		forecastAPI = repo.NewCachedForecastAPI(forecastAPI, cfg.CacheTTL, cfg.CacheMaxEntries)
+	}
+	forecastHandler := handler.NewForecastHandler(forecastAPI, cfg)
+
+	// The One Call API needs a separate subscription. Only the alerts endpoint depends on it; the other
+	// endpoints use it when enabled.
+	oneCallAPI := repo.NewRecordingOneCallAPI(repo.NewOneCallAPI(repo.WithRedactor(redactor)), historyStore)
+	if cfg.CacheTTL > 0 {
+		oneCallAPI = repo.NewCachedOneCallAPI(oneCallAPI, cfg.CacheTTL, cfg.CacheMaxEntries)
+	}
+	var minutelyAPI, uvAPI repo.OneCallAPI
+	if cfg.NowcastMinutely {
+		minutelyAPI = oneCallAPI
+	}
+	if cfg.UVOneCall {
+		uvAPI = oneCallAPI
+	}
+	weatherHandler.OneCall = oneCallAPI // Only used when cfg.CurrentFromOneCall is set
+	nowcastHandler := handler.NewNowcastHandler(forecastAPI, minutelyAPI, cfg)
+	frostRiskHandler := handler.NewFrostRiskHandler(forecastAPI, oneCallAPI, cfg) // Uses the 3-hour forecast alone without One Call
+
+	var nwsAPI repo.NWSAlertsAPI
+	if cfg.NWSAlerts {
+		nwsAPI = repo.NewNWSAlertsAPI(cfg.NWSAlertsURL, cfg.NWSUserAgent)
+	}
+	alertsHandler := handler.NewAlertsHandler(oneCallAPI, nwsAPI, cfg)
+	uvHandler := handler.NewUVHandler(uvAPI, cfg) // Estimates clear-sky UV without One Call
+
+	airPollutionAPI := repo.NewAirPollutionAPI(repo.WithRedactor(redactor))
+	if cfg.CacheTTL > 0 {
+		airPollutionAPI = repo.NewCachedAirPollutionAPI(airPollutionAPI, cfg.CacheTTL, cfg.CacheMaxEntries)
+	}
+	airQualityHandler := handler.NewAirQualityHandler(airPollutionAPI, cfg)
+
+	// OpenWeatherMap's history API needs a separate subscription and fills gaps in recorded history
+	var historyAPI repo.HistoryAPI
+	if cfg.HistoryURL != "" {
+		historyAPI = repo.NewRecordingHistoryAPI(repo.NewHistoryAPI(repo.WithRedactor(redactor)), historyStore)
+	}
+	historyHandler := handler.NewHistoryHandler(historyStore, historyAPI, cfg)
+	degreeDaysHandler := handler.NewDegreeDaysHandler(historyStore, historyAPI, cfg)
+
+	var customSchemes []category.Scheme
+	if cfg.CategorySchemesFile != "" {
+		schemes, err := category.LoadFile(cfg.CategorySchemesFile)
+		if err != nil {
+			log.Fatalf("Failed to load category schemes: %v\n", err)
+		}
+		customSchemes = schemes
+	}
+	categories, err := category.NewRegistry(cfg.CategoryScheme, customSchemes...)
+	if err != nil {
+		log.Fatalf("Invalid category scheme configuration: %v\n", err)
+	}
+	frostSchemes, err := category.NewFrostRegistry(cfg.FrostScheme, customSchemes...)
+	if err != nil {
+		log.Fatalf("Invalid frost risk scheme configuration: %v\n", err)
+	}
+	weatherHandler.Categories = categories
+	forecastHandler.Categories = categories
+	frostRiskHandler.Schemes = frostSchemes
+
+	// Optional Combined Log Format access log, kept apart from the application log stream
+	var accessLog io.Writer
+	if cfg.AccessLogFile != "" {
+		accessLogFile, err := accesslog.NewRotatingFile(cfg.AccessLogFile, accesslog.RotationConfig{
+			MaxSize:     int64(cfg.AccessLogMaxSizeMB) * 1024 * 1024,
+			RotateEvery: cfg.AccessLogRotateEvery,
+			MaxBackups:  cfg.AccessLogBackups,
+			Compress:    cfg.AccessLogCompress,
+		})
+		if err != nil {
+			log.Fatalf("Failed to open access log: %v\n", err)
+		}
+		defer accessLogFile.Close()
+
+		// logrotate moves the file and sends SIGUSR1 from its postrotate script
+		stop := accesslog.ReopenOnSignal(accessLogFile, func(err error) {
+			log.Printf("Failed to reopen access log: %v\n", err)
+		})
+		defer stop()
+
+		accessLog = redactor.Writer(accessLogFile)
+	}
+
 	router := mux.NewRouter()
 	router.Use(middleware.RateLimitMiddleware(cfg.RateLimitPerSecond))
-	router.Use(middleware.OpenWeatherMapAuthMiddleware)
-	router.Use(middleware.LoggingMiddleware)
+
+	// Only endpoints that call Open Weather Map need an API key
+	withAPIKey := func(handle http.HandlerFunc) http.Handler { return middleware.OpenWeatherMapAuthMiddleware(handle) }
 
 	api := router.PathPrefix("/api/v1").Subrouter()
-	api.HandleFunc("/weather", weatherHandler.GetWeatherConditionByCoordinates).Methods("GET")
+	api.Handle("/weather", withAPIKey(weatherHandler.GetWeatherConditionByCoordinates)).Methods("GET")
+	api.Handle("/forecast", withAPIKey(forecastHandler.GetForecastByCoordinates)).Methods("GET")
+	api.Handle("/nowcast", withAPIKey(nowcastHandler.GetNowcastByCoordinates)).Methods("GET")
+	api.Handle("/frost-risk", withAPIKey(frostRiskHandler.GetFrostRiskByCoordinates)).Methods("GET")
+	api.Handle("/alerts", withAPIKey(alertsHandler.GetAlertsByCoordinates)).Methods("GET")
+	api.Handle("/air", withAPIKey(airQualityHandler.GetAirQualityByCoordinates)).Methods("GET")
+	api.Handle("/uv", withAPIKey(uvHandler.GetUVByCoordinates)).Methods("GET")
+	api.Handle("/history", withAPIKey(historyHandler.GetHistoryByCoordinates)).Methods("GET")
+	api.Handle("/degree-days", withAPIKey(degreeDaysHandler.GetDegreeDaysByCoordinates)).Methods("GET")
+	api.HandleFunc("/categories", weatherHandler.ListCategorySchemes).Methods("GET")
+	api.HandleFunc("/astronomy", handler.NewAstronomyHandler().GetAstronomy).Methods("GET")
+
+	// mux only runs router.Use middleware for matched routes, so the access log wraps the whole router to
+	// record 404 and 405 responses as well as requests rejected by the limits. Recovery sits inside it, so the
+	// 500 responses of panicking handlers are logged too.
+	var server http.Handler = router
+	server = middleware.RecoveryMiddleware(server)
+	server = middleware.LoggingMiddlewareWithAccessLog(accessLog)(server)
+	server = middleware.RequestIDMiddleware(server)
+
+	// The admin listener is separate from the public router so profiling routes are never exposed with /api/v1
+	if cfg.AdminAddr != "" {
+		runtime.SetMutexProfileFraction(cfg.MutexProfileRate)
+		runtime.SetBlockProfileRate(cfg.BlockProfileRate)
+
+		go func() {
+			log.Printf("Starting admin server on %s\n", cfg.AdminAddr)
+			if err := http.ListenAndServe(cfg.AdminAddr, admin.NewRouter()); err != nil {
+				log.Printf("Admin server stopped: %v\n", err)
+			}
+		}()
+	}
+
+	// On SIGINT or SIGTERM, in-flight requests finish before main returns and the deferred closes release the
+	// history store and the access log
+	httpServer := &http.Server{Addr: ":" + cfg.Port, Handler: server}
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-signals.Done()
+
+		log.Printf("Shutting down server\n")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.Printf("Failed to shut down server gracefully: %v\n", err)
+		}
+	}()
 
 	log.Printf("Starting server on port %s\n", cfg.Port)
-	if err := http.ListenAndServe(":"+cfg.Port, router); err != nil {
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to start server: %v\n", err)
 	}
+	<-stopped
 }
+
+// shutdownTimeout is how long in-flight requests may take to finish on shutdown.
+const shutdownTimeout = 10 * time.Second
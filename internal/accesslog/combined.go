@@ -0,0 +1,68 @@
+package accesslog
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// combinedTimeFormat is the Apache %t timestamp layout.
+const combinedTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// FormatCombined formats a request as an NCSA Combined Log Format line, terminated by a newline:
+//
+//	host ident authuser [date] "request" status bytes "referer" "user-agent"
+func FormatCombined(r *http.Request, status, size int, received time.Time) string {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		host = h
+	}
+
+	user := "-"
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		user = username
+	}
+
+	bytesSent := "-"
+	if size > 0 {
+		bytesSent = strconv.Itoa(size)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		orDash(host),
+		escape(user),
+		received.Format(combinedTimeFormat),
+		escape(r.Method), escape(r.URL.RequestURI()), escape(r.Proto),
+		status,
+		bytesSent,
+		escape(orDash(r.Referer())),
+		escape(orDash(r.UserAgent())))
+}
+
+// orDash returns "-" for empty fields as Apache does.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// escape quotes characters that would break the line format, like Apache's escaping of %r and headers.
+func escape(value string) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
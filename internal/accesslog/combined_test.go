@@ -0,0 +1,38 @@
+package accesslog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFormatCombined(t *testing.T) {
+	received := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.FixedZone("", -5*60*60))
+
+	req, _ := http.NewRequest("GET", "/api/v1/weather?lat=36.9198&lon=93.9276", nil)
+	req.RemoteAddr = "192.0.2.10:53211"
+	req.Proto = "HTTP/1.1"
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set("User-Agent", `curl/8.4.0 "quoted"`)
+
+	expected := `192.0.2.10 - - [05/Mar/2024:14:07:09 -0500] "GET /api/v1/weather?lat=36.9198&lon=93.9276 HTTP/1.1" 200 52 "https://example.com/" "curl/8.4.0 \"quoted\""` + "\n"
+
+	if line := FormatCombined(req, http.StatusOK, 52, received); line != expected {
+		t.Errorf("FormatCombined() =\n%s\nwant\n%s", line, expected)
+	}
+}
+
+func TestFormatCombined_EmptyFields(t *testing.T) {
+	received := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "192.0.2.10"
+	req.Proto = "HTTP/1.1"
+	req.SetBasicAuth("alice", "password")
+
+	expected := `192.0.2.10 - alice [05/Mar/2024:14:07:09 +0000] "GET / HTTP/1.1" 429 - "-" "-"` + "\n"
+
+	if line := FormatCombined(req, http.StatusTooManyRequests, 0, received); line != expected {
+		t.Errorf("FormatCombined() =\n%s\nwant\n%s", line, expected)
+	}
+}
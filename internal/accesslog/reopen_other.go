@@ -0,0 +1,8 @@
+//go:build !unix
+
+package accesslog
+
+// ReopenOnSignal is a no-op on platforms without SIGUSR1; size- and time-based rotation still apply.
+func ReopenOnSignal(f *RotatingFile, onError func(error)) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package accesslog
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReopenOnSignal reopens f whenever the process receives SIGUSR1, which is what logrotate's postrotate
+// script sends after moving the file. The returned function stops listening for the signal.
+func ReopenOnSignal(f *RotatingFile, onError func(error)) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := f.Reopen(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
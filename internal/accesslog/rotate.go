@@ -0,0 +1,226 @@
+package accesslog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files so that they sort chronologically, e.g. access.log.20240102T150405.000.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotationConfig controls when a RotatingFile is rotated and how many rotated files are kept.
+type RotationConfig struct {
+	MaxSize     int64         // Rotate before a write would grow the file beyond this many bytes; 0 disables
+	RotateEvery time.Duration // Rotate when the file is older than this; 0 disables
+	MaxBackups  int           // Rotated files to keep, oldest are removed first; 0 keeps all
+	Compress    bool          // Gzip rotated files
+}
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it by size and age.
+// Reopen supports external rotation such as logrotate, which moves the file and signals the process.
+type RotatingFile struct {
+	path   string
+	config RotationConfig
+	now    func() time.Time // Clock, replaceable in tests
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	compressing sync.WaitGroup // Background gzip jobs, awaited by Close
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory if needed.
+func NewRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, config: config, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating access log directory: %v", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if the size or age limit has been reached.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Reopen closes and reopens the file at its path, picking up a new file after it was moved away externally.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file != nil {
+		f.file.Close()
+	}
+	return f.open()
+}
+
+// Rotate rotates the file immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Close closes the file and waits for any pending compression of rotated files.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.compressing.Wait()
+	return err
+}
+
+// open opens the file at path for appending. The caller must hold f.mu.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening access log: %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening access log: %v", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// shouldRotate reports whether writing n more bytes requires a rotation first. The caller must hold f.mu.
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+n > f.config.MaxSize {
+		return true
+	}
+	return f.config.RotateEvery > 0 && f.now().Sub(f.openedAt) >= f.config.RotateEvery
+}
+
+// rotate moves the current file aside, opens a fresh one and enforces compression and retention.
+// The caller must hold f.mu.
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+
+	backup := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error rotating access log: %v", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.compressing.Add(1)
+	go func() {
+		defer f.compressing.Done()
+		if f.config.Compress {
+			compressFile(backup)
+		}
+		f.removeOldBackups()
+	}()
+
+	return nil
+}
+
+// removeOldBackups deletes the oldest rotated files beyond MaxBackups.
+func (f *RotatingFile) removeOldBackups() {
+	if f.config.MaxBackups <= 0 {
+		return
+	}
+
+	backups := f.backups()
+	if len(backups) <= f.config.MaxBackups {
+		return
+	}
+	for _, backup := range backups[:len(backups)-f.config.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// backups lists the rotated files, compressed or not, oldest first.
+func (f *RotatingFile) backups() []string {
+	matches, _ := filepath.Glob(f.path + ".*")
+
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, f.path+"."), ".gz")
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+
+	// The timestamp format sorts chronologically; compare without .gz so it does not affect the order
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], ".gz") < strings.TrimSuffix(backups[j], ".gz")
+	})
+	return backups
+}
+
+// compressFile gzips path to path.gz and removes the original once the compressed copy is complete.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close() // Close before removing, which Windows requires
+	return os.Remove(path)
+}
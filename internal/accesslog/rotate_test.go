@@ -0,0 +1,135 @@
+package accesslog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRotatingFile_SizeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+
+	f, err := NewRotatingFile(path, RotationConfig{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned an unexpected error: %v", err)
+	}
+
+	// Give every rotation a distinct backup name
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	f.Write([]byte("line-one\n"))
+	f.Write([]byte("line-two\n")) // Would exceed 10 bytes, so the first line is rotated away
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close returned an unexpected error: %v", err)
+	}
+
+	if content := readFile(t, path); content != "line-two\n" {
+		t.Errorf("current file = %q, want %q", content, "line-two\n")
+	}
+
+	backups := f.backups()
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %v", backups)
+	}
+	if content := readFile(t, backups[0]); content != "line-one\n" {
+		t.Errorf("backup = %q, want %q", content, "line-one\n")
+	}
+}
+
+func TestRotatingFile_TimeRotationAndRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f, err := NewRotatingFile(path, RotationConfig{RotateEvery: time.Hour, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned an unexpected error: %v", err)
+	}
+	f.now = func() time.Time { return clock }
+	f.openedAt = clock
+
+	for i := 0; i < 5; i++ {
+		f.Write([]byte("entry\n"))
+		f.compressing.Wait() // Let retention of the previous rotation finish before the next one
+		clock = clock.Add(time.Hour)
+	}
+	f.Close()
+
+	if backups := f.backups(); len(backups) != 2 {
+		t.Errorf("expected retention to keep 2 backups, got %v", backups)
+	}
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+
+	f, err := NewRotatingFile(path, RotationConfig{Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned an unexpected error: %v", err)
+	}
+
+	f.Write([]byte("compressed line\n"))
+	if err := f.Rotate(); err != nil {
+		t.Fatalf("Rotate returned an unexpected error: %v", err)
+	}
+	f.Close()
+
+	backups := f.backups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("expected a single gzipped backup, got %v", backups)
+	}
+
+	file, _ := os.Open(backups[0])
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("backup is not gzip: %v", err)
+	}
+	content, _ := io.ReadAll(gz)
+	if string(content) != "compressed line\n" {
+		t.Errorf("decompressed backup = %q", content)
+	}
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	f, err := NewRotatingFile(path, RotationConfig{})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned an unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	f.Write([]byte("before\n"))
+
+	// Simulate logrotate moving the file away before signalling the process
+	moved := filepath.Join(dir, "access.log.1")
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatalf("Reopen returned an unexpected error: %v", err)
+	}
+	f.Write([]byte("after\n"))
+
+	if content := readFile(t, moved); content != "before\n" {
+		t.Errorf("moved file = %q, want %q", content, "before\n")
+	}
+	if content := readFile(t, path); content != "after\n" {
+		t.Errorf("reopened file = %q, want %q", content, "after\n")
+	}
+}
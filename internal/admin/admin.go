@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
+)
+
+// BuildTime is the time the binary was built, injected at link time with
+// -ldflags "-X github.com/golang2go/demo-app/weather-service-api/internal/admin.BuildTime=...".
+var BuildTime string
+
+// VersionInfo describes the running binary as reported by the /version endpoint.
+type VersionInfo struct {
+	Module      string `json:"module"`                // Main module path
+	Version     string `json:"version"`               // Main module version, "(devel)" for local builds
+	GoVersion   string `json:"goVersion"`             // Go toolchain used for the build
+	VCSRevision string `json:"vcsRevision,omitempty"` // Commit the binary was built from
+	VCSTime     string `json:"vcsTime,omitempty"`     // Commit time of VCSRevision
+	VCSModified bool   `json:"vcsModified"`           // Whether the working tree had local modifications
+	BuildTime   string `json:"buildTime,omitempty"`   // Link-time BuildTime, or the commit time if not set
+}
+
+// NewRouter creates the handler for the admin listener serving pprof, expvar and version information.
+// It uses its own ServeMux so that none of these routes can leak onto the public API router.
+func NewRouter() http.Handler {
+	mux := http.NewServeMux()
+
+	// Profiling; pprof.Index also serves the named profiles such as /debug/pprof/mutex
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// Runtime and application counters
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	mux.HandleFunc("/version", handleVersion)
+
+	// Runtime log levels, e.g. PUT /loglevel?component=repo&level=debug
+	mux.HandleFunc("/loglevel", logging.LevelHandler)
+
+	return mux
+}
+
+// handleVersion responds with the build and version information of the running binary.
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReadVersionInfo())
+}
+
+// ReadVersionInfo collects the module version and VCS stamp embedded by the Go toolchain.
+func ReadVersionInfo() VersionInfo {
+	info := VersionInfo{BuildTime: BuildTime}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Module = buildInfo.Main.Path
+	info.Version = buildInfo.Main.Version
+	info.GoVersion = buildInfo.GoVersion
+
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.VCSRevision = setting.Value
+		case "vcs.time":
+			info.VCSTime = setting.Value
+		case "vcs.modified":
+			info.VCSModified = setting.Value == "true"
+		}
+	}
+
+	if info.BuildTime == "" {
+		info.BuildTime = info.VCSTime
+	}
+
+	return info
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRouter(t *testing.T) {
+	router := NewRouter()
+
+	testCases := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Pprof Index", "/debug/pprof/", http.StatusOK},
+		{"Mutex Profile", "/debug/pprof/mutex?debug=1", http.StatusOK},
+		{"Goroutine Profile", "/debug/pprof/goroutine?debug=1", http.StatusOK},
+		{"Expvar", "/debug/vars", http.StatusOK},
+		{"Version", "/version", http.StatusOK},
+		{"Unknown", "/api/v1/weather", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestHandleVersion(t *testing.T) {
+	BuildTime = "2024-01-02T03:04:05Z"
+	defer func() { BuildTime = "" }()
+
+	req, _ := http.NewRequest("GET", "/version", nil)
+	rr := httptest.NewRecorder()
+	handleVersion(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var info VersionInfo
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &info))
+	assert.Equal(t, "2024-01-02T03:04:05Z", info.BuildTime)
+	assert.NotEmpty(t, info.GoVersion)
+}
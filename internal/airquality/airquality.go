@@ -0,0 +1,63 @@
+// Package airquality computes air quality indices from pollutant concentrations: the US EPA Air Quality
+// Index and the European Common Air Quality Index (CAQI).
+package airquality
+
+// Pollutant names a pollutant by its OpenWeatherMap component key.
+type Pollutant string
+
+const (
+	PM25 Pollutant = "pm2_5"
+	PM10 Pollutant = "pm10"
+	O3   Pollutant = "o3"
+	NO2  Pollutant = "no2"
+	SO2  Pollutant = "so2"
+	CO   Pollutant = "co"
+)
+
+// Pollutants lists the pollutants the indices cover, in the order that breaks ties for the dominant one.
+var Pollutants = []Pollutant{PM25, PM10, O3, NO2, SO2, CO}
+
+// Concentrations are pollutant concentrations in μg/m³, as OpenWeatherMap reports them.
+type Concentrations struct {
+	PM25, PM10, O3, NO2, SO2, CO float64
+}
+
+// Of returns the concentration of a pollutant.
+func (c Concentrations) Of(p Pollutant) float64 {
+	switch p {
+	case PM25:
+		return c.PM25
+	case PM10:
+		return c.PM10
+	case O3:
+		return c.O3
+	case NO2:
+		return c.NO2
+	case SO2:
+		return c.SO2
+	case CO:
+		return c.CO
+	}
+	return 0
+}
+
+// Index is an air quality index with the sub-index of every pollutant. The dominant pollutant has the highest
+// sub-index and determines the index.
+type Index struct {
+	Value      int               `json:"value"`
+	Category   string            `json:"category"`
+	Advice     string            `json:"advice,omitempty"` // Health advice for the category
+	Dominant   Pollutant         `json:"dominantPollutant"`
+	SubIndices map[Pollutant]int `json:"subIndices"`
+}
+
+// maxIndex builds an Index from sub-indices, taking the first pollutant on a tie.
+func maxIndex(subIndices map[Pollutant]int) Index {
+	index := Index{Value: -1, SubIndices: subIndices}
+	for _, p := range Pollutants {
+		if value := subIndices[p]; value > index.Value {
+			index.Value, index.Dominant = value, p
+		}
+	}
+	return index
+}
@@ -0,0 +1,92 @@
+package airquality
+
+import "testing"
+
+func TestSubIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		pollutant Pollutant
+		ugm3      float64
+		expected  int
+	}{
+		{"PM2.5 Top Of Good", PM25, 9.0, 50},
+		{"PM2.5 Truncated Into Good", PM25, 9.09, 50},
+		{"PM2.5 Moderate", PM25, 12.0, 56},
+		{"PM2.5 EPA Example", PM25, 35.9, 102}, // EPA technical assistance document
+		{"PM2.5 Row Boundary", PM25, 35.4, 100},
+		{"PM2.5 Hazardous", PM25, 325.4, 500},
+		{"PM2.5 Beyond The AQI", PM25, 400, 500},
+		{"PM10 Row Start", PM10, 155, 101},
+		{"PM10 Truncated", PM10, 54.9, 50},
+		{"O3 EPA Example", O3, 153.13, 126},             // 0.078 ppm over 8 hours
+		{"O3 Top Of 8-Hour Table", O3, 392.7, 300},      // 0.200 ppm
+		{"O3 Above 8-Hour Table", O3, 394.7, 301},       // 0.201 ppm
+		{"O3 Overlapping One-Hour Table", O3, 450, 301}, // 0.229 ppm
+		{"O3 One-Hour Table", O3, 982, 396},             // 0.500 ppm
+		{"NO2 Truncated Ppb", NO2, 100, 50},             // 53.2 ppb
+		{"SO2 Ppb", SO2, 200, 101},                      // 76 ppb
+		{"CO Ppm", CO, 5000, 49},                        // 4.37 ppm
+		{"Not Reported", CO, 0, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SubIndex(tc.pollutant, tc.ugm3); got != tc.expected {
+				t.Errorf("SubIndex(%s, %v) = %d; want %d", tc.pollutant, tc.ugm3, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSubIndex_Monotonic(t *testing.T) {
+	for _, p := range Pollutants {
+		previous := 0
+		for ugm3 := 0.0; ugm3 <= 5000; ugm3 += 0.5 {
+			got := SubIndex(p, ugm3)
+			if got < previous {
+				t.Fatalf("SubIndex(%s, %v) = %d; want at least %d as at a lower concentration", p, ugm3, got, previous)
+			}
+			previous = got
+		}
+	}
+}
+
+func TestUSAQI(t *testing.T) {
+	index := USAQI(Concentrations{PM25: 35.9, PM10: 60, O3: 80, NO2: 20, SO2: 5, CO: 300})
+	if index.Value != 102 || index.Dominant != PM25 || index.Category != "Unhealthy for Sensitive Groups" {
+		t.Errorf("USAQI() = %+v; want 102 from pm2_5, Unhealthy for Sensitive Groups", index)
+	}
+	if index.SubIndices[PM10] != 53 || index.Advice == "" {
+		t.Errorf("USAQI() = %+v; want a PM10 sub-index of 53 and health advice", index)
+	}
+
+	clean := USAQI(Concentrations{})
+	if clean.Value != 0 || clean.Dominant != PM25 || clean.Category != "Good" {
+		t.Errorf("USAQI() of clean air = %+v; want 0, Good", clean)
+	}
+}
+
+func TestCAQI(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        Concentrations
+		value    int
+		dominant Pollutant
+		category string
+	}{
+		{"Clean", Concentrations{NO2: 10, PM10: 5, O3: 30}, 13, O3, "Very low"},
+		{"Grid Point", Concentrations{NO2: 100, PM10: 20}, 50, NO2, "Medium"},
+		{"Interpolated", Concentrations{NO2: 150, PM10: 20}, 63, NO2, "Medium"},
+		{"Top Of High", Concentrations{O3: 240}, 100, O3, "High"},
+		{"Beyond The Grid", Concentrations{PM10: 270, NO2: 150}, 125, PM10, "Very high"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			index := CAQI(tc.c)
+			if index.Value != tc.value || index.Dominant != tc.dominant || index.Category != tc.category {
+				t.Errorf("CAQI(%+v) = %d from %s, %s; want %d from %s, %s", tc.c, index.Value, index.Dominant, index.Category, tc.value, tc.dominant, tc.category)
+			}
+		})
+	}
+}
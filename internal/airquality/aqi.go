@@ -0,0 +1,159 @@
+package airquality
+
+import "math"
+
+// Molar volume of an ideal gas at 25 °C and 1 atm (L/mol), which the EPA uses to convert μg/m³ to ppb.
+const molarVolume = 24.45
+
+// Molecular weights (g/mol) of the gaseous pollutants.
+const (
+	weightO3  = 48.00
+	weightNO2 = 46.0055
+	weightSO2 = 64.066
+	weightCO  = 28.010
+)
+
+// breakpoint maps the concentrations from cLow to cHigh linearly to the indices from iLow to iHigh.
+type breakpoint struct {
+	cLow, cHigh float64
+	iLow, iHigh int
+}
+
+// aqiTable is the breakpoint table of one pollutant. Concentrations are converted to the table's unit and
+// truncated to its precision first, so every value falls within a row.
+type aqiTable struct {
+	decimals int                        // Precision concentrations are truncated to
+	convert  func(ugm3 float64) float64 // From μg/m³ to the unit of the breakpoints
+	rows     []breakpoint
+}
+
+// toPPB returns a converter from μg/m³ to ppb for a gas, scaled by factor (1e-3 for ppm).
+func toPPB(weight, factor float64) func(float64) float64 {
+	return func(ugm3 float64) float64 { return ugm3 * molarVolume / weight * factor }
+}
+
+func identity(ugm3 float64) float64 { return ugm3 }
+
+// aqiTables are the EPA breakpoints, with the PM2.5 table revised in 2024. O3 uses the 8-hour table up to
+// 0.200 ppm and the 1-hour table above it, as the EPA prescribes, but never below the 8-hour table's top.
+var aqiTables = map[Pollutant]aqiTable{
+	PM25: {1, identity, []breakpoint{ // μg/m³, 24-hour
+		{0.0, 9.0, 0, 50},
+		{9.1, 35.4, 51, 100},
+		{35.5, 55.4, 101, 150},
+		{55.5, 125.4, 151, 200},
+		{125.5, 225.4, 201, 300},
+		{225.5, 325.4, 301, 500},
+	}},
+	PM10: {0, identity, []breakpoint{ // μg/m³, 24-hour
+		{0, 54, 0, 50},
+		{55, 154, 51, 100},
+		{155, 254, 101, 150},
+		{255, 354, 151, 200},
+		{355, 424, 201, 300},
+		{425, 604, 301, 500},
+	}},
+	O3: {3, toPPB(weightO3, 1e-3), []breakpoint{ // ppm, 8-hour
+		{0.000, 0.054, 0, 50},
+		{0.055, 0.070, 51, 100},
+		{0.071, 0.085, 101, 150},
+		{0.086, 0.105, 151, 200},
+		{0.106, 0.200, 201, 300},
+	}},
+	NO2: {0, toPPB(weightNO2, 1), []breakpoint{ // ppb, 1-hour
+		{0, 53, 0, 50},
+		{54, 100, 51, 100},
+		{101, 360, 101, 150},
+		{361, 649, 151, 200},
+		{650, 1249, 201, 300},
+		{1250, 2049, 301, 500},
+	}},
+	SO2: {0, toPPB(weightSO2, 1), []breakpoint{ // ppb, 1-hour up to 304, 24-hour above
+		{0, 35, 0, 50},
+		{36, 75, 51, 100},
+		{76, 185, 101, 150},
+		{186, 304, 151, 200},
+		{305, 604, 201, 300},
+		{605, 1004, 301, 500},
+	}},
+	CO: {1, toPPB(weightCO, 1e-3), []breakpoint{ // ppm, 8-hour
+		{0.0, 4.4, 0, 50},
+		{4.5, 9.4, 51, 100},
+		{9.5, 12.4, 101, 150},
+		{12.5, 15.4, 151, 200},
+		{15.5, 30.4, 201, 300},
+		{30.5, 50.4, 301, 500},
+	}},
+}
+
+// ozoneOneHour is the EPA 1-hour O3 table (ppm) used when the concentration exceeds the 8-hour table. Its
+// lower rows overlap the top of the 8-hour table, so an index from it is raised to at least 301.
+var ozoneOneHour = []breakpoint{
+	{0.165, 0.204, 151, 200},
+	{0.205, 0.404, 201, 300},
+	{0.405, 0.604, 301, 500},
+}
+
+// aqiCategories are the EPA categories with their upper index and general health advice.
+var aqiCategories = []struct {
+	upTo         int
+	name, advice string
+}{
+	{50, "Good", "Air quality is satisfactory, and air pollution poses little or no risk."},
+	{100, "Moderate", "Unusually sensitive people should consider reducing prolonged or heavy exertion outdoors."},
+	{150, "Unhealthy for Sensitive Groups", "People with heart or lung disease, older adults, children and people who are active outdoors should reduce prolonged or heavy exertion."},
+	{200, "Unhealthy", "Everyone should reduce prolonged or heavy exertion outdoors; sensitive groups should avoid it."},
+	{300, "Very Unhealthy", "Everyone should avoid prolonged or heavy exertion outdoors; sensitive groups should avoid all physical activity outdoors."},
+	{500, "Hazardous", "Everyone should avoid all physical activity outdoors; sensitive groups should remain indoors."},
+}
+
+// USAQI computes the US EPA Air Quality Index. OpenWeatherMap reports hourly concentrations, which stand in for
+// the 8- and 24-hour averages of some tables, as on current-conditions maps. Concentrations beyond the tables
+// are reported as 500.
+func USAQI(c Concentrations) Index {
+	subIndices := make(map[Pollutant]int, len(Pollutants))
+	for _, p := range Pollutants {
+		subIndices[p] = SubIndex(p, c.Of(p))
+	}
+
+	index := maxIndex(subIndices)
+	for _, category := range aqiCategories {
+		if index.Value <= category.upTo {
+			index.Category, index.Advice = category.name, category.advice
+			break
+		}
+	}
+	return index
+}
+
+// SubIndex computes the AQI of one pollutant from its concentration in μg/m³.
+func SubIndex(p Pollutant, ugm3 float64) int {
+	table, ok := aqiTables[p]
+	if !ok || ugm3 <= 0 {
+		return 0
+	}
+
+	c := truncate(table.convert(ugm3), table.decimals)
+	if top := table.rows[len(table.rows)-1]; p == O3 && c > top.cHigh {
+		return max(lookup(ozoneOneHour, c), top.iHigh+1)
+	}
+	return lookup(table.rows, c)
+}
+
+// lookup interpolates the index of a truncated concentration within its row of a table.
+func lookup(rows []breakpoint, c float64) int {
+	for _, row := range rows {
+		if c <= row.cHigh {
+			c = math.Max(c, row.cLow)
+			return int(math.Round(float64(row.iHigh-row.iLow)/(row.cHigh-row.cLow)*(c-row.cLow))) + row.iLow
+		}
+	}
+	return 500
+}
+
+// truncate cuts a concentration to the given number of decimals, as the EPA requires before looking up the
+// breakpoints. The small offset keeps values such as 35.4, stored as 35.3999..., from dropping a digit.
+func truncate(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Floor(value*scale+1e-9) / scale
+}
@@ -0,0 +1,57 @@
+package airquality
+
+import "math"
+
+// caqiGrid holds the concentrations (μg/m³) at the CAQI values 0, 25, 50, 75 and 100 of the hourly background
+// index. NO2, PM10 and O3 are its core pollutants; PM2.5, SO2 and CO are auxiliary.
+var caqiGrid = map[Pollutant][5]float64{
+	NO2:  {0, 50, 100, 200, 400},
+	PM10: {0, 25, 50, 90, 180},
+	O3:   {0, 60, 120, 180, 240},
+	PM25: {0, 15, 30, 55, 110},
+	SO2:  {0, 50, 100, 350, 500},
+	CO:   {0, 5000, 7500, 10000, 20000},
+}
+
+// caqiCategories are the CAQI bands with their upper index; the last band is open.
+var caqiCategories = []struct {
+	upTo int
+	name string
+}{
+	{24, "Very low"},
+	{49, "Low"},
+	{74, "Medium"},
+	{100, "High"},
+}
+
+// CAQI computes the hourly European Common Air Quality Index. Concentrations beyond the grid extend the
+// slope of its last band, so the index keeps rising above 100.
+func CAQI(c Concentrations) Index {
+	subIndices := make(map[Pollutant]int, len(Pollutants))
+	for _, p := range Pollutants {
+		subIndices[p] = caqiSubIndex(caqiGrid[p], c.Of(p))
+	}
+
+	index := maxIndex(subIndices)
+	index.Category = "Very high"
+	for _, category := range caqiCategories {
+		if index.Value <= category.upTo {
+			index.Category = category.name
+			break
+		}
+	}
+	return index
+}
+
+// caqiSubIndex interpolates a concentration linearly on a pollutant's grid.
+func caqiSubIndex(grid [5]float64, ugm3 float64) int {
+	if ugm3 <= 0 {
+		return 0
+	}
+	band := 0
+	for band < 3 && ugm3 > grid[band+1] {
+		band++
+	}
+	value := 25 * (float64(band) + (ugm3-grid[band])/(grid[band+1]-grid[band]))
+	return int(math.Round(value))
+}
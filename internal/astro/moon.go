@@ -0,0 +1,186 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	obliquityJ2000 = 23.4397 * rad
+	sunDistance    = 149598000.0  // km
+	synodicMonth   = 29.530588853 // days
+	moonHorizon    = 0.133 * rad  // Altitude of the moon's upper limb at rise and set, allowing for parallax
+)
+
+// MoonInfo describes the moon at a time and its rise and set within one day.
+type MoonInfo struct {
+	Phase        float64    `json:"phase"` // 0 new, 0.25 first quarter, 0.5 full, 0.75 last quarter
+	PhaseName    string     `json:"phaseName"`
+	Illumination float64    `json:"illumination"` // Illuminated fraction of the disc, 0-1
+	Age          float64    `json:"ageDays"`      // Approximate days since new moon
+	Position     Position   `json:"position"`
+	Moonrise     *time.Time `json:"moonrise,omitempty"`
+	Moonset      *time.Time `json:"moonset,omitempty"`
+	AlwaysUp     bool       `json:"alwaysUp,omitempty"`   // The moon does not set within the day
+	AlwaysDown   bool       `json:"alwaysDown,omitempty"` // The moon does not rise within the day
+}
+
+// daysSinceJ2000 returns the days since J2000.0 of a time.
+func daysSinceJ2000(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5 - 2451545
+}
+
+// equatorial converts ecliptic longitude and latitude to right ascension and declination.
+func equatorial(longitude, latitude float64) (rightAscension, declination float64) {
+	rightAscension = math.Atan2(math.Sin(longitude)*math.Cos(obliquityJ2000)-math.Tan(latitude)*math.Sin(obliquityJ2000), math.Cos(longitude))
+	declination = math.Asin(math.Sin(latitude)*math.Cos(obliquityJ2000) + math.Cos(latitude)*math.Sin(obliquityJ2000)*math.Sin(longitude))
+	return rightAscension, declination
+}
+
+// moonCoordinates returns the moon's geocentric right ascension, declination and distance in km.
+func moonCoordinates(d float64) (rightAscension, declination, distance float64) {
+	meanLongitude := rad * (218.316 + 13.176396*d)
+	meanAnomaly := rad * (134.963 + 13.064993*d)
+	argumentOfLatitude := rad * (93.272 + 13.229350*d)
+
+	longitude := meanLongitude + rad*6.289*math.Sin(meanAnomaly)
+	latitude := rad * 5.128 * math.Sin(argumentOfLatitude)
+	distance = 385001 - 20905*math.Cos(meanAnomaly)
+
+	rightAscension, declination = equatorial(longitude, latitude)
+	return rightAscension, declination, distance
+}
+
+// sunEquatorial returns the sun's low-precision right ascension and declination.
+func sunEquatorial(d float64) (rightAscension, declination float64) {
+	meanAnomaly := rad * (357.5291 + 0.98560028*d)
+	center := rad * (1.9148*math.Sin(meanAnomaly) + 0.02*math.Sin(2*meanAnomaly) + 0.0003*math.Sin(3*meanAnomaly))
+	longitude := meanAnomaly + center + rad*102.9372 + math.Pi
+	return equatorial(longitude, 0)
+}
+
+// moonPosition returns the moon's altitude in radians, corrected for refraction, and azimuth in degrees
+// clockwise from north.
+func moonPosition(t time.Time, lat, lon float64) (altitude, azimuth float64) {
+	d := daysSinceJ2000(t)
+	rightAscension, declination, _ := moonCoordinates(d)
+
+	siderealTime := rad*(280.16+360.9856235*d) + lon*rad
+	hourAngle := siderealTime - rightAscension
+	phi := lat * rad
+
+	altitude = math.Asin(math.Sin(phi)*math.Sin(declination) + math.Cos(phi)*math.Cos(declination)*math.Cos(hourAngle))
+	azimuth = math.Atan2(math.Sin(hourAngle), math.Cos(hourAngle)*math.Sin(phi)-math.Tan(declination)*math.Cos(phi))
+
+	// Refraction near the horizon; below it the formula diverges
+	h := math.Max(altitude, 0)
+	altitude += 0.0002967 / math.Tan(h+0.00312536/(h+0.08901179))
+
+	return altitude, math.Mod(azimuth/rad+180+360, 360)
+}
+
+// MoonPhase returns the moon's phase (0-1, from new through full back to new) and illuminated fraction.
+func MoonPhase(t time.Time) (phase, illumination float64) {
+	d := daysSinceJ2000(t)
+	sunRA, sunDec := sunEquatorial(d)
+	moonRA, moonDec, moonDistance := moonCoordinates(d)
+
+	elongation := math.Acos(math.Sin(sunDec)*math.Sin(moonDec) + math.Cos(sunDec)*math.Cos(moonDec)*math.Cos(sunRA-moonRA))
+	phaseAngle := math.Atan2(sunDistance*math.Sin(elongation), moonDistance-sunDistance*math.Cos(elongation))
+	angle := math.Atan2(math.Cos(sunDec)*math.Sin(sunRA-moonRA),
+		math.Sin(sunDec)*math.Cos(moonDec)-math.Cos(sunDec)*math.Sin(moonDec)*math.Cos(sunRA-moonRA))
+
+	sign := 1.0
+	if angle < 0 {
+		sign = -1
+	}
+	return 0.5 + 0.5*phaseAngle*sign/math.Pi, (1 + math.Cos(phaseAngle)) / 2
+}
+
+// PhaseName names a phase; the principal phases span an eighth of the cycle centred on their instant.
+func PhaseName(phase float64) string {
+	names := []string{"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous", "Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent"}
+	return names[int(math.Floor(phase*8+0.5))%8]
+}
+
+// MoonFor describes the moon at a time, with its rise and set during the 24 hours starting at dayStart.
+func MoonFor(t, dayStart time.Time, lat, lon float64) MoonInfo {
+	phase, illumination := MoonPhase(t)
+	altitude, azimuth := moonPosition(t, lat, lon)
+
+	info := MoonInfo{
+		Phase:        phase,
+		PhaseName:    PhaseName(phase),
+		Illumination: illumination,
+		Age:          phase * synodicMonth,
+		Position:     Position{Elevation: altitude / rad, Azimuth: azimuth},
+	}
+	info.Moonrise, info.Moonset, info.AlwaysUp, info.AlwaysDown = moonTimes(dayStart, lat, lon)
+	return info
+}
+
+// moonTimes finds the moon's rise and set in the 24 hours from start by fitting a parabola through its
+// altitude every hour and solving for the horizon crossings.
+func moonTimes(start time.Time, lat, lon float64) (rise, set *time.Time, alwaysUp, alwaysDown bool) {
+	altitudeAt := func(hours float64) float64 {
+		altitude, _ := moonPosition(start.Add(time.Duration(hours*float64(time.Hour))), lat, lon)
+		return altitude - moonHorizon
+	}
+	at := func(hours float64) *time.Time {
+		t := start.Add(time.Duration(hours * float64(time.Hour))).Round(time.Second)
+		return &t
+	}
+
+	h0 := altitudeAt(0)
+	var vertex float64
+	for i := 1.0; i <= 24; i += 2 {
+		h1, h2 := altitudeAt(i), altitudeAt(i+1)
+
+		a := (h0+h2)/2 - h1
+		b := (h2 - h0) / 2
+		xe := -b / (2 * a)
+		vertex = (a*xe+b)*xe + h1
+		discriminant := b*b - 4*a*h1
+
+		roots := 0
+		var x1, x2 float64
+		if discriminant >= 0 {
+			dx := math.Sqrt(discriminant) / (math.Abs(a) * 2)
+			x1, x2 = xe-dx, xe+dx
+			if math.Abs(x1) <= 1 {
+				roots++
+			}
+			if math.Abs(x2) <= 1 {
+				roots++
+			}
+			if x1 < -1 {
+				x1 = x2
+			}
+		}
+
+		switch roots {
+		case 1:
+			if h0 < 0 {
+				rise = at(i + x1)
+			} else {
+				set = at(i + x1)
+			}
+		case 2:
+			if vertex < 0 {
+				rise, set = at(i+x2), at(i+x1)
+			} else {
+				rise, set = at(i+x1), at(i+x2)
+			}
+		}
+
+		if rise != nil && set != nil {
+			break
+		}
+		h0 = h2
+	}
+
+	if rise == nil && set == nil {
+		alwaysUp, alwaysDown = vertex > 0, vertex <= 0
+	}
+	return rise, set, alwaysUp, alwaysDown
+}
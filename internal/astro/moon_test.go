@@ -0,0 +1,84 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestMoonPhase checks the principal phases of January 2024 at their published instants.
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		time         string
+		phase        float64
+		illumination float64
+		name         string
+	}{
+		{"2024-01-11T11:57:00Z", 0, 0, "New Moon"},
+		{"2024-01-18T03:53:00Z", 0.25, 0.5, "First Quarter"},
+		{"2024-01-25T17:54:00Z", 0.5, 1, "Full Moon"},
+		{"2024-02-02T23:18:00Z", 0.75, 0.5, "Last Quarter"},
+	}
+
+	for _, tc := range tests {
+		phase, illumination := MoonPhase(utc(tc.time))
+
+		// The low-precision series places the phases within about half a day
+		if diff := math.Abs(phase - tc.phase); math.Min(diff, 1-diff) > 0.02 {
+			t.Errorf("MoonPhase(%s) phase = %.3f; want %.2f", tc.time, phase, tc.phase)
+		}
+		if math.Abs(illumination-tc.illumination) > 0.02 {
+			t.Errorf("MoonPhase(%s) illumination = %.3f; want %.2f", tc.time, illumination, tc.illumination)
+		}
+		if name := PhaseName(phase); name != tc.name {
+			t.Errorf("PhaseName(%.3f) = %q; want %q", phase, name, tc.name)
+		}
+	}
+}
+
+func TestPhaseName(t *testing.T) {
+	tests := map[float64]string{0: "New Moon", 0.99: "New Moon", 0.1: "Waxing Crescent", 0.4: "Waxing Gibbous", 0.6: "Waning Gibbous", 0.9: "Waning Crescent"}
+	for phase, expected := range tests {
+		if name := PhaseName(phase); name != expected {
+			t.Errorf("PhaseName(%v) = %q; want %q", phase, name, expected)
+		}
+	}
+}
+
+func TestMoonFor(t *testing.T) {
+	dayStart := time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)
+	moon := MoonFor(dayStart.Add(12*time.Hour), dayStart, 51.5074, -0.1278)
+
+	if moon.Moonrise == nil || moon.Moonset == nil || moon.AlwaysUp || moon.AlwaysDown {
+		t.Fatalf("MoonFor() = %+v; want a moonrise and moonset in London", moon)
+	}
+
+	// At its rise and set the moon's upper limb is on the horizon, within the accuracy of the hourly interpolation
+	for name, event := range map[string]time.Time{"moonrise": *moon.Moonrise, "moonset": *moon.Moonset} {
+		if event.Before(dayStart) || !event.Before(dayStart.Add(24*time.Hour)) {
+			t.Errorf("%s %v is outside the day", name, event)
+		}
+		if altitude, _ := moonPosition(event, 51.5074, -0.1278); math.Abs(altitude-moonHorizon) > 0.2*rad {
+			t.Errorf("moon altitude at %s = %.3f°; want the horizon", name, altitude/rad)
+		}
+	}
+
+	if moon.PhaseName != "Full Moon" || moon.Illumination < 0.95 {
+		t.Errorf("MoonFor() = %+v; want a nearly full moon the day before the June 2024 full moon", moon)
+	}
+}
+
+func TestMoonForPolar(t *testing.T) {
+	// Near the pole the moon stays up or down for days at a time
+	found := false
+	for day := 0; day < 30 && !found; day++ {
+		dayStart := time.Date(2024, 1, 1+day, 0, 0, 0, 0, time.UTC)
+		moon := MoonFor(dayStart, dayStart, 89, 0)
+		if moon.AlwaysUp || moon.AlwaysDown {
+			found = moon.Moonrise == nil && moon.Moonset == nil
+		}
+	}
+	if !found {
+		t.Errorf("expected a day on which the moon neither rises nor sets at 89° N")
+	}
+}
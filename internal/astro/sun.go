@@ -0,0 +1,254 @@
+// Package astro computes solar and lunar positions and events in-process. Solar calculations follow the
+// NOAA Solar Calculator (based on Meeus, Astronomical Algorithms); lunar ones use Meeus' low-precision
+// series. Longitudes are east-positive degrees and all times are UTC.
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+const rad = math.Pi / 180
+
+// Zenith angles of the solar events, in degrees. Sunrise and sunset allow for refraction and the solar radius.
+const (
+	zenithSunrise      = 90.833
+	zenithCivil        = 96
+	zenithNautical     = 102
+	zenithAstronomical = 108
+)
+
+// Elevations bounding the golden and blue hours, in degrees.
+const (
+	goldenHourHigh = 6
+	goldenHourLow  = -4
+	blueHourLow    = -6
+)
+
+// DayType tells whether the sun rises and sets on a date.
+type DayType string
+
+const (
+	DayTypeNormal     DayType = "normal"
+	DayTypePolarDay   DayType = "polarDay"   // The sun stays above the horizon
+	DayTypePolarNight DayType = "polarNight" // The sun stays below the horizon
+)
+
+// Position is the apparent position of a body in the sky, in degrees. Elevation includes atmospheric
+// refraction; azimuth is measured clockwise from north.
+type Position struct {
+	Elevation float64 `json:"elevation"`
+	Azimuth   float64 `json:"azimuth"`
+}
+
+// Window is a time interval, such as a golden hour.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Twilight holds the start of dawn and end of dusk for one twilight phase. Either is nil when the sun
+// does not cross the phase's depression angle on that date.
+type Twilight struct {
+	Dawn *time.Time `json:"dawn,omitempty"`
+	Dusk *time.Time `json:"dusk,omitempty"`
+}
+
+// HourWindows holds the morning and evening occurrence of a golden or blue hour.
+type HourWindows struct {
+	Morning *Window `json:"morning,omitempty"`
+	Evening *Window `json:"evening,omitempty"`
+}
+
+// SunTimes are the solar events of one date at a location.
+type SunTimes struct {
+	DayType          DayType     `json:"dayType"`
+	Sunrise          *time.Time  `json:"sunrise,omitempty"`
+	Sunset           *time.Time  `json:"sunset,omitempty"`
+	SolarNoon        time.Time   `json:"solarNoon"`
+	DayLength        float64     `json:"dayLengthSeconds"` // 0 during polar night, 86400 during polar day
+	CivilTwilight    Twilight    `json:"civilTwilight"`
+	NauticalTwilight Twilight    `json:"nauticalTwilight"`
+	AstroTwilight    Twilight    `json:"astronomicalTwilight"`
+	GoldenHour       HourWindows `json:"goldenHour"`
+	BlueHour         HourWindows `json:"blueHour"`
+}
+
+// julianCentury returns the Julian centuries since J2000.0 of a time.
+func julianCentury(t time.Time) float64 {
+	julianDay := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	return (julianDay - 2451545) / 36525
+}
+
+// solarCoordinates returns the sun's apparent declination in degrees and the equation of time in minutes.
+func solarCoordinates(t time.Time) (declination, equationOfTime float64) {
+	c := julianCentury(t)
+
+	meanLongitude := math.Mod(280.46646+c*(36000.76983+c*0.0003032), 360)
+	meanAnomaly := 357.52911 + c*(35999.05029-0.0001537*c)
+	eccentricity := 0.016708634 - c*(0.000042037+0.0000001267*c)
+	center := math.Sin(meanAnomaly*rad)*(1.914602-c*(0.004817+0.000014*c)) +
+		math.Sin(2*meanAnomaly*rad)*(0.019993-0.000101*c) +
+		math.Sin(3*meanAnomaly*rad)*0.000289
+
+	omega := 125.04 - 1934.136*c
+	apparentLongitude := meanLongitude + center - 0.00569 - 0.00478*math.Sin(omega*rad)
+	meanObliquity := 23 + (26+(21.448-c*(46.815+c*(0.00059-c*0.001813)))/60)/60
+	obliquity := meanObliquity + 0.00256*math.Cos(omega*rad)
+
+	declination = math.Asin(math.Sin(obliquity*rad)*math.Sin(apparentLongitude*rad)) / rad
+
+	y := math.Pow(math.Tan(obliquity*rad/2), 2)
+	l0, m := meanLongitude*rad, meanAnomaly*rad
+	equationOfTime = 4 / rad * (y*math.Sin(2*l0) - 2*eccentricity*math.Sin(m) +
+		4*eccentricity*y*math.Sin(m)*math.Cos(2*l0) - 0.5*y*y*math.Sin(4*l0) -
+		1.25*eccentricity*eccentricity*math.Sin(2*m))
+	return declination, equationOfTime
+}
+
+// SunPosition returns the apparent position of the sun at a time and location.
+func SunPosition(t time.Time, lat, lon float64) Position {
+	t = t.UTC()
+	declination, equationOfTime := solarCoordinates(t)
+
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60 + float64(t.Nanosecond())/6e10
+	trueSolarTime := math.Mod(minutes+equationOfTime+4*lon, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
+	}
+	hourAngle := trueSolarTime/4 - 180
+
+	cosZenith := math.Sin(lat*rad)*math.Sin(declination*rad) + math.Cos(lat*rad)*math.Cos(declination*rad)*math.Cos(hourAngle*rad)
+	zenith := math.Acos(clamp(cosZenith)) / rad
+
+	var azimuth float64
+	if sinZenith := math.Sin(zenith * rad); math.Abs(math.Cos(lat*rad)*sinZenith) > 1e-12 {
+		cosAzimuth := (math.Sin(lat*rad)*math.Cos(zenith*rad) - math.Sin(declination*rad)) / (math.Cos(lat*rad) * sinZenith)
+		azimuth = math.Acos(clamp(cosAzimuth)) / rad
+		if hourAngle > 0 {
+			azimuth = math.Mod(azimuth+180, 360)
+		} else {
+			azimuth = math.Mod(540-azimuth, 360)
+		}
+	}
+
+	elevation := 90 - zenith
+	return Position{Elevation: elevation + refraction(elevation), Azimuth: azimuth}
+}
+
+// refraction returns NOAA's approximation of atmospheric refraction in degrees at an elevation.
+func refraction(elevation float64) float64 {
+	tanE := math.Tan(elevation * rad)
+	var arcSeconds float64
+	switch {
+	case elevation > 85:
+		return 0
+	case elevation > 5:
+		arcSeconds = 58.1/tanE - 0.07/math.Pow(tanE, 3) + 0.000086/math.Pow(tanE, 5)
+	case elevation > -0.575:
+		arcSeconds = 1735 + elevation*(-518.2+elevation*(103.4+elevation*(-12.79+elevation*0.711)))
+	default:
+		arcSeconds = -20.772 / tanE
+	}
+	return arcSeconds / 3600
+}
+
+func clamp(cos float64) float64 {
+	return math.Max(-1, math.Min(1, cos))
+}
+
+// crossing kinds returned by eventTime.
+const (
+	crosses     = iota
+	alwaysAbove // The sun stays above the zenith angle all day
+	alwaysBelow // The sun never reaches the zenith angle
+)
+
+// eventTime returns when the sun crosses a zenith angle in the morning (rising) or evening on the date
+// whose UTC midnight is day. The time is refined iteratively as NOAA's calculator does.
+func eventTime(day time.Time, lat, lon, zenith float64, rising bool) (time.Time, int) {
+	t := day.Add(12*time.Hour - time.Duration(lon/15*float64(time.Hour)))
+	for i := 0; i < 4; i++ {
+		declination, equationOfTime := solarCoordinates(t)
+		cosHourAngle := math.Cos(zenith*rad)/(math.Cos(lat*rad)*math.Cos(declination*rad)) - math.Tan(lat*rad)*math.Tan(declination*rad)
+		if cosHourAngle > 1 {
+			return time.Time{}, alwaysBelow
+		}
+		if cosHourAngle < -1 {
+			return time.Time{}, alwaysAbove
+		}
+
+		hourAngle := math.Acos(cosHourAngle) / rad
+		if rising {
+			hourAngle = -hourAngle
+		}
+		minutes := 720 - 4*(lon-hourAngle) - equationOfTime
+		t = day.Add(time.Duration(minutes * float64(time.Minute)))
+	}
+	return t.Round(time.Second), crosses
+}
+
+// solarNoon returns the time of the sun's transit on the date whose UTC midnight is day.
+func solarNoon(day time.Time, lon float64) time.Time {
+	t := day.Add(12*time.Hour - time.Duration(lon/15*float64(time.Hour)))
+	for i := 0; i < 2; i++ {
+		_, equationOfTime := solarCoordinates(t)
+		t = day.Add(time.Duration((720 - 4*lon - equationOfTime) * float64(time.Minute)))
+	}
+	return t.Round(time.Second)
+}
+
+// SunTimesFor returns the solar events of a date at a location. The date is the calendar day at the
+// location in local mean solar time, so the events are those around that day's solar noon.
+func SunTimesFor(date time.Time, lat, lon float64) SunTimes {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	times := SunTimes{DayType: DayTypeNormal, SolarNoon: solarNoon(day, lon)}
+
+	sunrise, kind := eventTime(day, lat, lon, zenithSunrise, true)
+	switch kind {
+	case alwaysAbove:
+		times.DayType, times.DayLength = DayTypePolarDay, 86400
+	case alwaysBelow:
+		times.DayType = DayTypePolarNight
+	default:
+		sunset, _ := eventTime(day, lat, lon, zenithSunrise, false)
+		times.Sunrise, times.Sunset = &sunrise, &sunset
+		times.DayLength = sunset.Sub(sunrise).Seconds()
+	}
+
+	times.CivilTwilight = twilight(day, lat, lon, zenithCivil)
+	times.NauticalTwilight = twilight(day, lat, lon, zenithNautical)
+	times.AstroTwilight = twilight(day, lat, lon, zenithAstronomical)
+
+	times.GoldenHour = HourWindows{
+		Morning: window(day, lat, lon, 90-goldenHourLow, 90-goldenHourHigh, true),
+		Evening: window(day, lat, lon, 90-goldenHourHigh, 90-goldenHourLow, false),
+	}
+	times.BlueHour = HourWindows{
+		Morning: window(day, lat, lon, 90-blueHourLow, 90-goldenHourLow, true),
+		Evening: window(day, lat, lon, 90-goldenHourLow, 90-blueHourLow, false),
+	}
+	return times
+}
+
+// twilight returns the dawn and dusk of the twilight phase that ends at a zenith angle.
+func twilight(day time.Time, lat, lon, zenith float64) Twilight {
+	dawn, kind := eventTime(day, lat, lon, zenith, true)
+	if kind != crosses {
+		return Twilight{}
+	}
+	dusk, _ := eventTime(day, lat, lon, zenith, false)
+	return Twilight{Dawn: &dawn, Dusk: &dusk}
+}
+
+// window returns the interval in which the sun moves from one zenith angle to another in the morning or
+// evening, or nil when it does not cross both.
+func window(day time.Time, lat, lon, fromZenith, toZenith float64, rising bool) *Window {
+	start, startKind := eventTime(day, lat, lon, fromZenith, rising)
+	end, endKind := eventTime(day, lat, lon, toZenith, rising)
+	if startKind != crosses || endKind != crosses {
+		return nil
+	}
+	return &Window{Start: start, End: end}
+}
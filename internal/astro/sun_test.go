@@ -0,0 +1,119 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func utc(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// TestSunTimesFor compares with the NOAA Solar Calculator, whose times are rounded to the minute.
+func TestSunTimesFor(t *testing.T) {
+	tests := []struct {
+		name                       string
+		date                       string
+		lat, lon                   float64
+		sunrise, sunset, solarNoon string
+	}{
+		{"London summer solstice", "2024-06-21", 51.5074, -0.1278, "2024-06-21T03:43:00Z", "2024-06-21T20:22:00Z", "2024-06-21T12:02:00Z"},
+		{"London winter solstice", "2024-12-21", 51.5074, -0.1278, "2024-12-21T08:04:00Z", "2024-12-21T15:54:00Z", "2024-12-21T11:59:00Z"},
+		{"New York", "2024-06-20", 40.7128, -74.006, "2024-06-20T09:25:00Z", "2024-06-21T00:31:00Z", "2024-06-20T16:58:00Z"},
+		{"Sydney", "2024-01-01", -33.8688, 151.2093, "2023-12-31T18:47:00Z", "2024-01-01T09:09:00Z", "2024-01-01T01:58:00Z"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			times := SunTimesFor(date(tc.date), tc.lat, tc.lon)
+			if times.DayType != DayTypeNormal || times.Sunrise == nil || times.Sunset == nil {
+				t.Fatalf("SunTimesFor() = %+v; want a normal day", times)
+			}
+
+			assertNear(t, "sunrise", *times.Sunrise, utc(tc.sunrise))
+			assertNear(t, "sunset", *times.Sunset, utc(tc.sunset))
+			assertNear(t, "solar noon", times.SolarNoon, utc(tc.solarNoon))
+
+			// Twilight and the golden and blue hours nest around sunrise and sunset
+			if !times.CivilTwilight.Dawn.Before(*times.Sunrise) || !times.CivilTwilight.Dusk.After(*times.Sunset) {
+				t.Errorf("civil twilight %v - %v does not enclose the day", times.CivilTwilight.Dawn, times.CivilTwilight.Dusk)
+			}
+			if !times.NauticalTwilight.Dawn.Before(*times.CivilTwilight.Dawn) {
+				t.Errorf("nautical dawn %v is not before civil dawn %v", times.NauticalTwilight.Dawn, times.CivilTwilight.Dawn)
+			}
+			morningBlue, morningGolden := times.BlueHour.Morning, times.GoldenHour.Morning
+			if !morningBlue.End.Equal(morningGolden.Start) || !morningGolden.Start.Before(*times.Sunrise) || !morningGolden.End.After(*times.Sunrise) {
+				t.Errorf("morning blue hour %+v and golden hour %+v do not surround sunrise %v", morningBlue, morningGolden, times.Sunrise)
+			}
+			if !times.GoldenHour.Evening.End.Equal(times.BlueHour.Evening.Start) {
+				t.Errorf("evening golden hour %+v does not lead into the blue hour %+v", times.GoldenHour.Evening, times.BlueHour.Evening)
+			}
+			if math.Abs(times.DayLength-times.Sunset.Sub(*times.Sunrise).Seconds()) > 0 {
+				t.Errorf("day length %v does not match sunrise and sunset", times.DayLength)
+			}
+		})
+	}
+}
+
+func assertNear(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff < -time.Minute || diff > time.Minute {
+		t.Errorf("%s = %v; want %v within a minute", name, got, want)
+	}
+}
+
+func TestSunTimesForPolarDayAndNight(t *testing.T) {
+	// Tromsø has midnight sun in June and polar night, with civil twilight at noon, in December
+	summer := SunTimesFor(date("2024-06-21"), 69.6496, 18.956)
+	if summer.DayType != DayTypePolarDay || summer.Sunrise != nil || summer.DayLength != 86400 {
+		t.Errorf("Tromsø in June = %+v; want polar day", summer)
+	}
+	if summer.CivilTwilight.Dawn != nil || summer.GoldenHour.Morning != nil {
+		t.Errorf("Tromsø in June has twilight %+v; want none", summer.CivilTwilight)
+	}
+
+	winter := SunTimesFor(date("2024-12-21"), 69.6496, 18.956)
+	if winter.DayType != DayTypePolarNight || winter.Sunset != nil || winter.DayLength != 0 {
+		t.Errorf("Tromsø in December = %+v; want polar night", winter)
+	}
+	if winter.CivilTwilight.Dawn == nil || winter.GoldenHour.Morning != nil || winter.BlueHour.Morning == nil {
+		t.Errorf("Tromsø in December = %+v; want civil twilight and a blue hour but no golden hour", winter)
+	}
+
+	// Longyearbyen in December only reaches nautical twilight
+	svalbard := SunTimesFor(date("2024-12-21"), 78.2232, 15.6267)
+	if svalbard.CivilTwilight.Dawn != nil || svalbard.NauticalTwilight.Dawn == nil {
+		t.Errorf("Longyearbyen in December = %+v; want nautical but no civil twilight", svalbard)
+	}
+}
+
+// TestSunPosition compares with the NOAA Solar Calculator's apparent elevation and azimuth.
+func TestSunPosition(t *testing.T) {
+	tests := []struct {
+		name               string
+		time               string
+		lat, lon           float64
+		elevation, azimuth float64
+	}{
+		{"London noon at the solstice", "2024-06-21T12:00:00Z", 51.5074, -0.1278, 61.93, 178.81},
+		{"Equator at the equinox", "2024-03-20T12:07:00Z", 0, 0, 89.5, 0},
+		{"Below the horizon", "2024-12-21T00:00:00Z", 51.5074, -0.1278, -61.6, 0},
+	}
+
+	for _, tc := range tests {
+		position := SunPosition(utc(tc.time), tc.lat, tc.lon)
+		if math.Abs(position.Elevation-tc.elevation) > 0.6 {
+			t.Errorf("%s: elevation = %.2f; want %.2f", tc.name, position.Elevation, tc.elevation)
+		}
+		if tc.azimuth != 0 && math.Abs(position.Azimuth-tc.azimuth) > 0.5 {
+			t.Errorf("%s: azimuth = %.2f; want %.2f", tc.name, position.Azimuth, tc.azimuth)
+		}
+	}
+}
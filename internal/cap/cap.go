@@ -0,0 +1,80 @@
+// Package cap encodes alerts as OASIS Common Alerting Protocol (CAP) 1.2 XML documents.
+package cap
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Namespace is the XML namespace of CAP 1.2.
+const Namespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+// ContentType is the media type of CAP documents.
+const ContentType = "application/cap+xml"
+
+// Values of the alert and info elements used by this service
+const (
+	StatusActual = "Actual"
+	MsgTypeAlert = "Alert"
+	ScopePublic  = "Public"
+	CategoryMet  = "Met"
+	ValueUnknown = "Unknown" // Urgency, severity or certainty that is not known
+)
+
+// Time is a CAP date-time. CAP forbids the "Z" designator and fractional seconds, so UTC is written as +00:00.
+type Time time.Time
+
+// MarshalText formats the time as required by CAP, e.g. 2024-01-01T12:00:00+00:00.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(time.Time(t).Format("2006-01-02T15:04:05-07:00")), nil
+}
+
+// Alert is a CAP alert message. Each Info block describes one event.
+type Alert struct {
+	XMLName    xml.Name `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string   `xml:"identifier"`
+	Sender     string   `xml:"sender"`
+	Sent       Time     `xml:"sent"`
+	Status     string   `xml:"status"`
+	MsgType    string   `xml:"msgType"`
+	Scope      string   `xml:"scope"`
+	Info       []Info   `xml:"info"`
+}
+
+// Info describes one event of an alert.
+type Info struct {
+	Language    string `xml:"language,omitempty"`
+	Category    string `xml:"category"`
+	Event       string `xml:"event"`
+	Urgency     string `xml:"urgency"`
+	Severity    string `xml:"severity"`
+	Certainty   string `xml:"certainty"`
+	Onset       *Time  `xml:"onset,omitempty"`
+	Expires     *Time  `xml:"expires,omitempty"`
+	SenderName  string `xml:"senderName,omitempty"`
+	Headline    string `xml:"headline,omitempty"`
+	Description string `xml:"description,omitempty"`
+	Instruction string `xml:"instruction,omitempty"`
+	Area        []Area `xml:"area"`
+}
+
+// Area is the area an event affects.
+type Area struct {
+	AreaDesc string `xml:"areaDesc"`
+	Circle   string `xml:"circle,omitempty"` // "lat,lon radius" in WGS 84 degrees and kilometers
+}
+
+// Encode writes the alert as an indented XML document.
+func (a Alert) Encode(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(a); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,59 @@
+package cap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAlertEncode(t *testing.T) {
+	onset := Time(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	alert := Alert{
+		Identifier: "weather-service-api-1",
+		Sender:     "weather-service-api",
+		Sent:       Time(time.Date(2024, 1, 1, 21, 0, 0, 0, time.FixedZone("JST", 9*3600))),
+		Status:     StatusActual,
+		MsgType:    MsgTypeAlert,
+		Scope:      ScopePublic,
+		Info: []Info{{
+			Category:  CategoryMet,
+			Event:     "Flood Warning",
+			Urgency:   "Immediate",
+			Severity:  "Severe",
+			Certainty: "Likely",
+			Onset:     &onset,
+			Area:      []Area{{AreaDesc: "35,139", Circle: "35,139 0"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := alert.Encode(&buf); err != nil {
+		t.Fatalf("Encode() returned an unexpected error: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+  <identifier>weather-service-api-1</identifier>
+  <sender>weather-service-api</sender>
+  <sent>2024-01-01T21:00:00+09:00</sent>
+  <status>Actual</status>
+  <msgType>Alert</msgType>
+  <scope>Public</scope>
+  <info>
+    <category>Met</category>
+    <event>Flood Warning</event>
+    <urgency>Immediate</urgency>
+    <severity>Severe</severity>
+    <certainty>Likely</certainty>
+    <onset>2024-01-01T12:00:00+00:00</onset>
+    <area>
+      <areaDesc>35,139</areaDesc>
+      <circle>35,139 0</circle>
+    </area>
+  </info>
+</alert>
+`
+	if buf.String() != expected {
+		t.Errorf("Encode() =\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,172 @@
+package category
+
+import (
+	"fmt"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// DefaultSchemeName is the scheme used when neither the request nor the configuration selects one.
+const DefaultSchemeName = "default"
+
+// FrostSchemeName is the builtin scheme of frost risk levels, which categorizes the expected minimum
+// temperature of a night at plant level. It is the default frost risk scheme.
+const FrostSchemeName = "frost"
+
+func threshold(v float64) *float64 { return &v }
+
+// Builtin returns the schemes that are always available. The default scheme keeps the service's original
+// six Fahrenheit bands.
+func Builtin() []Scheme {
+	return []Scheme{
+		{
+			Name:        DefaultSchemeName,
+			Description: "Six bands from Freezing to Hot on air temperature",
+			Unit:        units.Fahrenheit,
+			Basis:       BasisAir,
+			Bands: []Band{
+				{"Freezing", threshold(32)},
+				{"Cold", threshold(50)},
+				{"Cool", threshold(68)},
+				{"Mild", threshold(77)},
+				{"Warm", threshold(95)},
+				{"Hot", nil},
+			},
+		},
+		{
+			Name:        "simple",
+			Description: "Cold, moderate or hot on air temperature",
+			Unit:        units.Celsius,
+			Basis:       BasisAir,
+			Bands: []Band{
+				{"Cold", threshold(10)},
+				{"Moderate", threshold(25)},
+				{"Hot", nil},
+			},
+		},
+		{
+			Name:        "tropical",
+			Description: "Bands for tropical climates on feels-like temperature",
+			Unit:        units.Celsius,
+			Basis:       BasisFeelsLike,
+			Bands: []Band{
+				{"Cool", threshold(22)},
+				{"Comfortable", threshold(27)},
+				{"Warm", threshold(32)},
+				{"Hot", threshold(39)},
+				{"Very Hot", nil},
+			},
+		},
+		{
+			Name:        "nordic",
+			Description: "Bands for Nordic climates on feels-like temperature",
+			Unit:        units.Celsius,
+			Basis:       BasisFeelsLike,
+			Bands: []Band{
+				{"Extreme Cold", threshold(-25)},
+				{"Very Cold", threshold(-10)},
+				{"Freezing", threshold(0)},
+				{"Cold", threshold(8)},
+				{"Cool", threshold(15)},
+				{"Mild", threshold(20)},
+				{"Warm", nil},
+			},
+		},
+	}
+}
+
+// BuiltinFrost returns the frost risk schemes that are always available.
+func BuiltinFrost() []Scheme {
+	return []Scheme{
+		{
+			Name:        FrostSchemeName,
+			Description: "Frost risk levels on the expected night minimum at plant level",
+			Unit:        units.Celsius,
+			Basis:       BasisAir,
+			Bands: []Band{
+				{"Severe", threshold(-2)}, // A hard freeze, damaging most tender crops
+				{"High", threshold(0)},
+				{"Medium", threshold(2)}, // Frost in sheltered or low-lying spots
+				{"Low", threshold(4)},
+				{"None", nil},
+			},
+			NoRisk: "None",
+		},
+	}
+}
+
+// Registry holds the available schemes of one kind, temperature categories or frost risk levels, by name
+// in the order they were added.
+type Registry struct {
+	kind        string // For error messages
+	defaultName string
+	order       []string
+	schemes     map[string]Scheme
+}
+
+// NewRegistry creates a registry with the builtin temperature category schemes followed by custom ones,
+// which replace builtin schemes of the same name. Custom frost risk schemes are left out. defaultName selects
+// the default scheme; empty means DefaultSchemeName.
+func NewRegistry(defaultName string, custom ...Scheme) (*Registry, error) {
+	if defaultName == "" {
+		defaultName = DefaultSchemeName
+	}
+	return newRegistry("category", defaultName, Builtin(), custom, false)
+}
+
+// NewFrostRegistry creates a registry with the builtin frost risk schemes followed by custom ones, which
+// replace builtin schemes of the same name. Custom temperature category schemes are left out. defaultName
+// selects the default scheme; empty means FrostSchemeName.
+func NewFrostRegistry(defaultName string, custom ...Scheme) (*Registry, error) {
+	if defaultName == "" {
+		defaultName = FrostSchemeName
+	}
+	return newRegistry("frost risk", defaultName, BuiltinFrost(), custom, true)
+}
+
+func newRegistry(kind, defaultName string, builtin, custom []Scheme, frostRisk bool) (*Registry, error) {
+	r := &Registry{kind: kind, defaultName: defaultName, schemes: make(map[string]Scheme)}
+	for _, scheme := range append(builtin, custom...) {
+		if scheme.IsFrostRisk() != frostRisk {
+			continue
+		}
+		if err := scheme.Validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := r.schemes[scheme.Name]; !exists {
+			r.order = append(r.order, scheme.Name)
+		}
+		r.schemes[scheme.Name] = scheme
+	}
+
+	if _, ok := r.schemes[defaultName]; !ok {
+		return nil, fmt.Errorf("default %s scheme %q is not defined", kind, defaultName)
+	}
+	return r, nil
+}
+
+// Lookup returns the scheme with the given name, or the default scheme for an empty name.
+func (r *Registry) Lookup(name string) (Scheme, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	scheme, ok := r.schemes[name]
+	if !ok {
+		return Scheme{}, fmt.Errorf("unknown %s scheme %q", r.kind, name)
+	}
+	return scheme, nil
+}
+
+// DefaultName returns the name of the default scheme.
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// Schemes returns every scheme in registration order.
+func (r *Registry) Schemes() []Scheme {
+	schemes := make([]Scheme, 0, len(r.order))
+	for _, name := range r.order {
+		schemes = append(schemes, r.schemes[name])
+	}
+	return schemes
+}
@@ -0,0 +1,81 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+func TestRegistry(t *testing.T) {
+	custom := Scheme{Name: "desert", Unit: units.Celsius, Basis: BasisFeelsLike, Bands: []Band{{"Pleasant", threshold(28)}, {"Hot", nil}}}
+	simple := Scheme{Name: "simple", Unit: units.Celsius, Basis: BasisAir, Bands: []Band{{"Cold", threshold(5)}, {"Warm", nil}}}
+
+	registry, err := NewRegistry("desert", custom, simple)
+	if err != nil {
+		t.Fatalf("NewRegistry returned an unexpected error: %v", err)
+	}
+
+	if scheme, err := registry.Lookup(""); err != nil || scheme.Name != "desert" {
+		t.Errorf("Lookup(\"\") = %q, %v; want the desert default", scheme.Name, err)
+	}
+	if scheme, err := registry.Lookup("simple"); err != nil || len(scheme.Bands) != 2 {
+		t.Errorf("Lookup(simple) = %+v, %v; want the configured scheme replacing the builtin one", scheme, err)
+	}
+	if _, err := registry.Lookup("arctic"); err == nil {
+		t.Errorf("expected an error for an unknown scheme")
+	}
+
+	var names []string
+	for _, scheme := range registry.Schemes() {
+		names = append(names, scheme.Name)
+	}
+	expected := []string{"default", "simple", "tropical", "nordic", "desert"}
+	if len(names) != len(expected) {
+		t.Fatalf("Schemes() = %v; want %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Schemes() = %v; want %v", names, expected)
+			break
+		}
+	}
+}
+
+func TestFrostRegistry(t *testing.T) {
+	orchard := Scheme{Name: "orchard", Unit: units.Fahrenheit, Basis: BasisAir, Bands: []Band{{"Damaging", threshold(28)}, {"Safe", nil}}, NoRisk: "Safe"}
+	desert := Scheme{Name: "desert", Unit: units.Celsius, Basis: BasisAir, Bands: []Band{{"Pleasant", threshold(28)}, {"Hot", nil}}}
+
+	registry, err := NewFrostRegistry("", orchard, desert)
+	if err != nil {
+		t.Fatalf("NewFrostRegistry returned an unexpected error: %v", err)
+	}
+	if scheme, err := registry.Lookup(""); err != nil || scheme.Name != FrostSchemeName {
+		t.Errorf("Lookup(\"\") = %q, %v; want the frost default", scheme.Name, err)
+	}
+	if _, err := registry.Lookup("orchard"); err != nil {
+		t.Errorf("Lookup(orchard) = %v; want the custom frost risk scheme", err)
+	}
+	if _, err := registry.Lookup("desert"); err == nil {
+		t.Errorf("expected temperature category schemes to be left out")
+	}
+
+	// Frost risk schemes are not temperature categories, and temperature categories are not frost risk levels
+	if categories, _ := NewRegistry("", orchard); len(categories.Schemes()) != len(Builtin()) {
+		t.Errorf("NewRegistry() = %v; want the custom frost risk scheme left out", categories.Schemes())
+	}
+	if _, err := NewFrostRegistry(DefaultSchemeName); err == nil {
+		t.Errorf("expected an error for a temperature category scheme as the default frost risk scheme")
+	}
+}
+
+func TestNewRegistryErrors(t *testing.T) {
+	if _, err := NewRegistry("arctic"); err == nil {
+		t.Errorf("expected an error for an undefined default scheme")
+	}
+	if _, err := NewRegistry("", Scheme{Name: "broken"}); err == nil {
+		t.Errorf("expected an error for an invalid custom scheme")
+	}
+	if registry, err := NewRegistry(""); err != nil || registry.DefaultName() != DefaultSchemeName {
+		t.Errorf("NewRegistry(\"\") = %v; want the default scheme", err)
+	}
+}
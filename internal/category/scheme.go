@@ -0,0 +1,156 @@
+// Package category assigns temperature categories such as "Cold" or "Hot" using named schemes of bands.
+package category
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Basis selects which temperature a scheme categorizes.
+type Basis string
+
+const (
+	BasisAir       Basis = "air"       // The measured air temperature
+	BasisFeelsLike Basis = "feelsLike" // The apparent temperature reported by OpenWeatherMap
+)
+
+// Band is a named temperature range. UpTo is the inclusive upper bound; the last band of a scheme has
+// none and catches every warmer temperature.
+type Band struct {
+	Name string   `json:"name"`
+	UpTo *float64 `json:"upTo,omitempty"`
+}
+
+// Scheme is a named set of bands in ascending order, with thresholds in Unit. A scheme with a NoRisk band
+// holds frost risk levels rather than temperature categories.
+type Scheme struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Unit        units.TemperatureUnit `json:"unit"`
+	Basis       Basis                 `json:"basis"`
+	Bands       []Band                `json:"bands"`
+	NoRisk      string                `json:"noRisk,omitempty"` // Band of a frost risk scheme from which there is no risk
+}
+
+// Validate checks that the scheme has a name, a known unit and basis, ascending thresholds and an open last band.
+// A frost risk scheme must also categorize the air temperature and name one of its bands as the no-risk band.
+func (s Scheme) Validate() error {
+	if s.Name == "" {
+		return errors.New("category scheme without a name")
+	}
+	if _, err := units.ParseTemperatureUnit(string(s.Unit)); err != nil {
+		return fmt.Errorf("category scheme %q: %w", s.Name, err)
+	}
+	if s.Basis != BasisAir && s.Basis != BasisFeelsLike {
+		return fmt.Errorf("category scheme %q: unknown basis %q", s.Name, s.Basis)
+	}
+	if len(s.Bands) == 0 {
+		return fmt.Errorf("category scheme %q has no bands", s.Name)
+	}
+
+	for i, band := range s.Bands {
+		last := i == len(s.Bands)-1
+		switch {
+		case band.Name == "":
+			return fmt.Errorf("category scheme %q: band %d has no name", s.Name, i)
+		case last && band.UpTo != nil:
+			return fmt.Errorf("category scheme %q: the last band %q must not have an upper bound", s.Name, band.Name)
+		case !last && band.UpTo == nil:
+			return fmt.Errorf("category scheme %q: band %q needs an upper bound", s.Name, band.Name)
+		case i > 0 && !last && *band.UpTo <= *s.Bands[i-1].UpTo:
+			return fmt.Errorf("category scheme %q: band %q does not follow %q in ascending order", s.Name, band.Name, s.Bands[i-1].Name)
+		}
+	}
+
+	if s.IsFrostRisk() {
+		if s.Basis != BasisAir {
+			return fmt.Errorf("frost risk scheme %q must use the air basis", s.Name)
+		}
+		if s.bandIndex(s.NoRisk) < 0 {
+			return fmt.Errorf("frost risk scheme %q: no-risk band %q is not one of its bands", s.Name, s.NoRisk)
+		}
+	}
+	return nil
+}
+
+// IsFrostRisk reports whether the scheme holds frost risk levels rather than temperature categories.
+func (s Scheme) IsFrostRisk() bool {
+	return s.NoRisk != ""
+}
+
+// AtRisk reports whether a band of a frost risk scheme is colder than its no-risk band.
+func (s Scheme) AtRisk(band string) bool {
+	i := s.bandIndex(band)
+	return i >= 0 && i < s.bandIndex(s.NoRisk)
+}
+
+// bandIndex returns the position of the band with the given name, or -1.
+func (s Scheme) bandIndex(name string) int {
+	for i, band := range s.Bands {
+		if band.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Categorize returns the name of the band the temperature falls into.
+func (s Scheme) Categorize(temperature units.Temperature) (string, error) {
+	converted, err := temperature.To(s.Unit)
+	if err != nil {
+		return "", err
+	}
+
+	for _, band := range s.Bands {
+		if band.UpTo == nil || converted.Value <= *band.UpTo {
+			return band.Name, nil
+		}
+	}
+	return "", fmt.Errorf("category scheme %q has no band for %v %s", s.Name, converted.Value, s.Unit)
+}
+
+// schemeFile is the JSON layout of a category scheme file.
+type schemeFile struct {
+	Schemes []Scheme `json:"schemes"`
+}
+
+// Load reads schemes from JSON of the form {"schemes": [{"name": ..., "unit": "C", "basis": "air",
+// "bands": [{"name": "Cold", "upTo": 10}, {"name": "Warm"}]}]}. Basis defaults to air. Frost risk schemes also
+// have "noRisk": the name of their no-risk band.
+func Load(r io.Reader) ([]Scheme, error) {
+	var file schemeFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("decoding category schemes: %w", err)
+	}
+
+	for i := range file.Schemes {
+		scheme := &file.Schemes[i]
+		if scheme.Basis == "" {
+			scheme.Basis = BasisAir
+		}
+		if unit, err := units.ParseTemperatureUnit(string(scheme.Unit)); err == nil {
+			scheme.Unit = unit
+		}
+		if err := scheme.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return file.Schemes, nil
+}
+
+// LoadFile reads schemes from a JSON file; see Load.
+func LoadFile(path string) ([]Scheme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
@@ -0,0 +1,126 @@
+package category
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+func TestSchemeCategorize(t *testing.T) {
+	simple := Builtin()[1]
+
+	tests := []struct {
+		name        string
+		temperature units.Temperature
+		expected    string
+	}{
+		{"Below threshold", units.Temperature{Value: 5, Unit: units.Celsius}, "Cold"},
+		{"On threshold is inclusive", units.Temperature{Value: 10, Unit: units.Celsius}, "Cold"},
+		{"Middle band", units.Temperature{Value: 10.5, Unit: units.Celsius}, "Moderate"},
+		{"Open last band", units.Temperature{Value: 40, Unit: units.Celsius}, "Hot"},
+		{"Converted from Kelvin", units.Temperature{Value: 293.15, Unit: units.Kelvin}, "Moderate"},
+		{"Converted from Fahrenheit", units.Temperature{Value: 100, Unit: units.Fahrenheit}, "Hot"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := simple.Categorize(tc.temperature)
+			if err != nil {
+				t.Fatalf("Categorize returned an unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("Categorize(%+v) = %q; want %q", tc.temperature, result, tc.expected)
+			}
+		})
+	}
+
+	if _, err := simple.Categorize(units.Temperature{Value: 1, Unit: "X"}); err == nil {
+		t.Errorf("expected an error for an unknown temperature unit")
+	}
+}
+
+func TestSchemeValidate(t *testing.T) {
+	valid := func() Scheme {
+		return Scheme{Name: "test", Unit: units.Celsius, Basis: BasisAir, Bands: []Band{{"Cold", threshold(0)}, {"Warm", threshold(20)}, {"Hot", nil}}}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Scheme)
+	}{
+		{"Missing name", func(s *Scheme) { s.Name = "" }},
+		{"Unknown unit", func(s *Scheme) { s.Unit = "X" }},
+		{"Unknown basis", func(s *Scheme) { s.Basis = "dewPoint" }},
+		{"No bands", func(s *Scheme) { s.Bands = nil }},
+		{"Unnamed band", func(s *Scheme) { s.Bands[1].Name = "" }},
+		{"Bounded last band", func(s *Scheme) { s.Bands[2].UpTo = threshold(40) }},
+		{"Unbounded middle band", func(s *Scheme) { s.Bands[1].UpTo = nil }},
+		{"Descending thresholds", func(s *Scheme) { s.Bands[1].UpTo = threshold(-5) }},
+		{"Unknown no-risk band", func(s *Scheme) { s.NoRisk = "Mild" }},
+		{"Frost risk on feels-like", func(s *Scheme) { s.NoRisk, s.Basis = "Warm", BasisFeelsLike }},
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate returned an unexpected error for a valid scheme: %v", err)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := valid()
+			tc.modify(&scheme)
+			if err := scheme.Validate(); err == nil {
+				t.Errorf("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestBuiltinSchemesAreValid(t *testing.T) {
+	for _, scheme := range append(Builtin(), BuiltinFrost()...) {
+		if err := scheme.Validate(); err != nil {
+			t.Errorf("builtin scheme %q is invalid: %v", scheme.Name, err)
+		}
+	}
+}
+
+func TestSchemeAtRisk(t *testing.T) {
+	frost := BuiltinFrost()[0]
+	for band, expected := range map[string]bool{"Severe": true, "Low": true, "None": false, "Unknown": false} {
+		if got := frost.AtRisk(band); got != expected {
+			t.Errorf("AtRisk(%s) = %v; want %v", band, got, expected)
+		}
+	}
+
+	// Bands warmer than the no-risk band carry no risk either
+	orchard := Scheme{Bands: []Band{{"Damaging", threshold(28)}, {"Safe", threshold(40)}, {"Warm", nil}}, NoRisk: "Safe"}
+	if orchard.AtRisk("Warm") || !orchard.AtRisk("Damaging") {
+		t.Errorf("AtRisk() of the orchard scheme = %v, %v; want false, true", orchard.AtRisk("Warm"), orchard.AtRisk("Damaging"))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	schemes, err := Load(strings.NewReader(`{"schemes": [
+		{"name": "desert", "unit": "c", "bands": [{"name": "Pleasant", "upTo": 28}, {"name": "Hot"}]}
+	]}`))
+	if err != nil {
+		t.Fatalf("Load returned an unexpected error: %v", err)
+	}
+
+	if len(schemes) != 1 {
+		t.Fatalf("Load returned %d schemes; want 1", len(schemes))
+	}
+	if scheme := schemes[0]; scheme.Unit != units.Celsius || scheme.Basis != BasisAir || len(scheme.Bands) != 2 {
+		t.Errorf("Load() = %+v; want a Celsius air temperature scheme with two bands", scheme)
+	}
+
+	invalid := []string{
+		`{"schemes": [{"name": "desert", "unit": "C", "bands": [{"name": "Hot", "upTo": 30}]}]}`,
+		`{"schemes": [{"name": "desert", "unit": "C", "bands": [{"name": "Hot"}], "colour": "red"}]}`,
+		`{"schemes": [`,
+	}
+	for _, input := range invalid {
+		if _, err := Load(strings.NewReader(input)); err == nil {
+			t.Errorf("expected an error loading %s", input)
+		}
+	}
+}
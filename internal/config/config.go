@@ -1,11 +1,82 @@
 package config
 
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Reasonable Defaults
 const (
 	DefaultPort               = "8080"
 	DefaultRateLimitPerSecond = 5
 	DefaultOpenWeatherMapURL  = "https://api.openweathermap.org/data/2.5/weather"
+	DefaultForecastURL        = "https://api.openweathermap.org/data/2.5/forecast"
+	DefaultOneCallURL         = "https://api.openweathermap.org/data/3.0/onecall"
+	DefaultAirPollutionURL    = "https://api.openweathermap.org/data/2.5/air_pollution"
+	DefaultNWSAlertsURL       = "https://api.weather.gov/alerts/active"
+	DefaultNWSUserAgent       = "weather-service-api" // api.weather.gov rejects requests without a User-Agent
 	DefaultUnitsOfMeasurement = "imperial"
+	DefaultAdminAddr          = "127.0.0.1:6060" // Loopback only; profiling must never be publicly reachable
+	DefaultMutexProfileRate   = 10               // Sample 1 in 10 mutex contention events
+	DefaultLogFormat          = "text"
+	DefaultLogLevel           = "info"
+	DefaultAccessLogSampling  = 100 // Access log lines written per second before sampling starts
+	DefaultAccessLogSampleN   = 10  // Then write every 10th line for the rest of the second
+	DefaultAccessLogMaxSizeMB = 100
+	DefaultAccessLogBackups   = 7
+	DefaultCacheTTL           = 2 * time.Minute // OpenWeatherMap updates current conditions about every 10 minutes
+	DefaultCacheMaxEntries    = 10000
+	DefaultCategoryScheme     = "default"
+	DefaultFrostScheme        = "frost"
+	DefaultHistoryRetention   = 366 * 24 * time.Hour // As far back as the history endpoint reaches
+	DefaultMemoryRetention    = 48 * time.Hour       // Without a history store directory, history is kept in memory
+	DefaultHistoryHourlyAfter = 7 * 24 * time.Hour
+	DefaultHistoryDailyAfter  = 90 * 24 * time.Hour
+	DefaultHistoryCompaction  = time.Hour
+)
+
+// Environment variables that override the defaults
+const (
+	EnvPort                = "WEATHER_PORT"
+	EnvRateLimitPerSecond  = "WEATHER_RATE_LIMIT_PER_SECOND"
+	EnvOpenWeatherMapURL   = "WEATHER_OPENWEATHERMAP_URL"
+	EnvForecastURL         = "WEATHER_FORECAST_URL"
+	EnvOneCallURL          = "WEATHER_ONECALL_URL"
+	EnvAirPollutionURL     = "WEATHER_AIR_POLLUTION_URL"
+	EnvUnitsOfMeasurement  = "WEATHER_UNITS"
+	EnvSensitiveParams     = "WEATHER_SENSITIVE_PARAMS"  // Comma-separated query parameters to redact in addition to appid
+	EnvSensitiveHeaders    = "WEATHER_SENSITIVE_HEADERS" // Comma-separated headers to redact in addition to X-API-Key
+	EnvAdminAddr           = "WEATHER_ADMIN_ADDR"        // Set to an empty value to disable the admin listener
+	EnvMutexProfileRate    = "WEATHER_MUTEX_PROFILE_RATE"
+	EnvBlockProfileRate    = "WEATHER_BLOCK_PROFILE_RATE"
+	EnvLogFormat           = "WEATHER_LOG_FORMAT"          // text or json
+	EnvLogLevel            = "WEATHER_LOG_LEVEL"           // e.g. "info,repo=debug"
+	EnvAccessLogSampling   = "WEATHER_ACCESS_LOG_SAMPLING" // Lines per second before sampling; 0 disables sampling
+	EnvAccessLogSampleN    = "WEATHER_ACCESS_LOG_SAMPLE_N" // Then keep every Nth line
+	EnvAccessLogFile       = "WEATHER_ACCESS_LOG_FILE"     // Combined Log Format output file; empty disables it
+	EnvAccessLogMaxSizeMB  = "WEATHER_ACCESS_LOG_MAX_SIZE_MB"
+	EnvAccessLogRotate     = "WEATHER_ACCESS_LOG_ROTATE_EVERY" // Duration such as 24h; empty disables time-based rotation
+	EnvAccessLogBackups    = "WEATHER_ACCESS_LOG_BACKUPS"
+	EnvAccessLogCompress   = "WEATHER_ACCESS_LOG_COMPRESS"
+	EnvCacheTTL            = "WEATHER_CACHE_TTL" // Duration such as 2m; 0 disables caching
+	EnvCacheMaxEntries     = "WEATHER_CACHE_MAX_ENTRIES"
+	EnvCategoryScheme      = "WEATHER_CATEGORY_SCHEME"       // Default temperature category scheme
+	EnvCategorySchemesFile = "WEATHER_CATEGORY_SCHEMES_FILE" // JSON file with additional category schemes
+	EnvFrostScheme         = "WEATHER_FROST_SCHEME"          // Default frost risk scheme
+	EnvNowcastMinutely     = "WEATHER_NOWCAST_MINUTELY"      // true to refine nowcasts with One Call minutely data
+	EnvUVOneCall           = "WEATHER_UV_ONECALL"            // true to take UV indices from One Call
+	EnvCurrentFromOneCall  = "WEATHER_CURRENT_FROM_ONECALL"  // true to fetch current conditions from One Call
+	EnvNWSAlerts           = "WEATHER_NWS_ALERTS"            // true to take alerts for US points from api.weather.gov
+	EnvNWSAlertsURL        = "WEATHER_NWS_ALERTS_URL"
+	EnvNWSUserAgent        = "WEATHER_NWS_USER_AGENT"    // NWS asks for an application name and contact address
+	EnvHistoryURL          = "WEATHER_HISTORY_URL"       // e.g. https://history.openweathermap.org/data/2.5/history/city
+	EnvHistoryRetention    = "WEATHER_HISTORY_RETENTION" // Duration such as 720h; 0 keeps recorded observations forever
+	EnvHistoryDir          = "WEATHER_HISTORY_DIR"       // Directory of the persistent history store; empty keeps history in memory
+	EnvHistoryHourlyAfter  = "WEATHER_HISTORY_HOURLY_AFTER"
+	EnvHistoryDailyAfter   = "WEATHER_HISTORY_DAILY_AFTER"
+	EnvHistoryCompactEvery = "WEATHER_HISTORY_COMPACT_EVERY"
 )
 
 // AppConfig holds application configuration
@@ -13,7 +84,41 @@ type AppConfig struct {
 	Port                 string
 	RateLimitPerSecond   int
 	OpenWeatherMapAPIURL string
-	UnitOfMeasurement    string
+	ForecastURL          string        // OpenWeatherMap 5 day / 3 hour forecast endpoint
+	OneCallURL           string        // OpenWeatherMap One Call endpoint, which needs a One Call subscription
+	AirPollutionURL      string        // OpenWeatherMap air pollution endpoint; its forecast is at /forecast below it
+	UnitOfMeasurement    string        // Default output unit system; upstream data is always fetched in standard units
+	SensitiveParams      []string      // Extra query parameters redacted from URLs, errors and logs
+	SensitiveHeaders     []string      // Extra headers redacted from logs
+	AdminAddr            string        // Address of the admin listener (pprof, expvar, version); empty disables it
+	MutexProfileRate     int           // runtime.SetMutexProfileFraction rate; 0 disables mutex profiling
+	BlockProfileRate     int           // runtime.SetBlockProfileRate rate in nanoseconds; 0 disables block profiling
+	LogFormat            string        // Application log format, text or json
+	LogLevel             string        // Default and per-component log levels, e.g. "info,repo=debug"
+	AccessLogSampling    int           // Access log lines written per second before sampling; 0 disables sampling
+	AccessLogSampleN     int           // Every Nth access log line written once sampling has started
+	AccessLogFile        string        // Combined Log Format access log file; empty disables it
+	AccessLogMaxSizeMB   int           // Rotate the access log file at this size; 0 disables size-based rotation
+	AccessLogRotateEvery time.Duration // Rotate the access log file at this age; 0 disables time-based rotation
+	AccessLogBackups     int           // Rotated access log files to keep; 0 keeps all
+	AccessLogCompress    bool          // Gzip rotated access log files
+	CacheTTL             time.Duration // How long upstream responses are reused; 0 disables caching
+	CacheMaxEntries      int           // Maximum number of cached upstream responses
+	CategoryScheme       string        // Temperature category scheme used when a request does not select one
+	CategorySchemesFile  string        // Optional JSON file defining category schemes in addition to the builtin ones
+	FrostScheme          string        // Frost risk scheme used when a request does not select one
+	NowcastMinutely      bool          // Use One Call minutely precipitation for the next hour of nowcasts
+	UVOneCall            bool          // Take UV indices from One Call instead of estimating them for a clear sky
+	CurrentFromOneCall   bool          // Fetch current conditions, with alerts, from One Call instead of /weather
+	NWSAlerts            bool          // Take alerts for US points from the National Weather Service
+	NWSAlertsURL         string        // api.weather.gov active alerts endpoint
+	NWSUserAgent         string        // User-Agent sent to api.weather.gov
+	HistoryURL           string        // OpenWeatherMap history endpoint, which needs a history subscription; empty disables it
+	HistoryRetention     time.Duration // How long recorded observations are kept; 0 keeps them forever
+	HistoryDir           string        // Directory of the persistent history store; empty keeps history in memory
+	HistoryHourlyAfter   time.Duration // Age at which stored observations are rolled up into hours; 0 never rolls them up
+	HistoryDailyAfter    time.Duration // Age at which hourly rollups are rolled up into days; 0 never rolls them up
+	HistoryCompactEvery  time.Duration // Interval of history store compaction; 0 disables it
 }
 
 // DefaultConfig creates a new AppConfig with default settings.
@@ -40,6 +145,142 @@ func NewAppConfig(port string, rateLimit int, apiURL, unit string) *AppConfig {
 		Port:                 port,
 		RateLimitPerSecond:   rateLimit,
 		OpenWeatherMapAPIURL: apiURL,
+		ForecastURL:          DefaultForecastURL,
+		OneCallURL:           DefaultOneCallURL,
+		AirPollutionURL:      DefaultAirPollutionURL,
 		UnitOfMeasurement:    unit,
+		AdminAddr:            DefaultAdminAddr,
+		MutexProfileRate:     DefaultMutexProfileRate,
+		LogFormat:            DefaultLogFormat,
+		LogLevel:             DefaultLogLevel,
+		AccessLogSampling:    DefaultAccessLogSampling,
+		AccessLogSampleN:     DefaultAccessLogSampleN,
+		AccessLogMaxSizeMB:   DefaultAccessLogMaxSizeMB,
+		AccessLogBackups:     DefaultAccessLogBackups,
+		CacheTTL:             DefaultCacheTTL,
+		CacheMaxEntries:      DefaultCacheMaxEntries,
+		CategoryScheme:       DefaultCategoryScheme,
+		FrostScheme:          DefaultFrostScheme,
+		NWSAlertsURL:         DefaultNWSAlertsURL,
+		NWSUserAgent:         DefaultNWSUserAgent,
+		HistoryRetention:     DefaultHistoryRetention,
+		HistoryHourlyAfter:   DefaultHistoryHourlyAfter,
+		HistoryDailyAfter:    DefaultHistoryDailyAfter,
+		HistoryCompactEvery:  DefaultHistoryCompaction,
+	}
+}
+
+// FromEnv creates a new AppConfig with default settings overridden by any WEATHER_* environment variables.
+func FromEnv() *AppConfig {
+	rateLimit, _ := strconv.Atoi(os.Getenv(EnvRateLimitPerSecond)) // Invalid values fall back to the default
+
+	cfg := NewAppConfig(os.Getenv(EnvPort), rateLimit, os.Getenv(EnvOpenWeatherMapURL), os.Getenv(EnvUnitsOfMeasurement))
+	if forecastURL := os.Getenv(EnvForecastURL); forecastURL != "" {
+		cfg.ForecastURL = forecastURL
+	}
+	if oneCallURL := os.Getenv(EnvOneCallURL); oneCallURL != "" {
+		cfg.OneCallURL = oneCallURL
+	}
+	if airPollutionURL := os.Getenv(EnvAirPollutionURL); airPollutionURL != "" {
+		cfg.AirPollutionURL = airPollutionURL
+	}
+	cfg.SensitiveParams = splitList(os.Getenv(EnvSensitiveParams))
+	cfg.SensitiveHeaders = splitList(os.Getenv(EnvSensitiveHeaders))
+
+	if adminAddr, ok := os.LookupEnv(EnvAdminAddr); ok {
+		cfg.AdminAddr = adminAddr
+	}
+	if rate, err := strconv.Atoi(os.Getenv(EnvMutexProfileRate)); err == nil {
+		cfg.MutexProfileRate = rate
+	}
+	if rate, err := strconv.Atoi(os.Getenv(EnvBlockProfileRate)); err == nil {
+		cfg.BlockProfileRate = rate
+	}
+	if format := os.Getenv(EnvLogFormat); format != "" {
+		cfg.LogFormat = format
+	}
+	if level := os.Getenv(EnvLogLevel); level != "" {
+		cfg.LogLevel = level
+	}
+	if sampling, err := strconv.Atoi(os.Getenv(EnvAccessLogSampling)); err == nil {
+		cfg.AccessLogSampling = sampling
+	}
+	if sampleN, err := strconv.Atoi(os.Getenv(EnvAccessLogSampleN)); err == nil {
+		cfg.AccessLogSampleN = sampleN
+	}
+	cfg.AccessLogFile = os.Getenv(EnvAccessLogFile)
+	if maxSize, err := strconv.Atoi(os.Getenv(EnvAccessLogMaxSizeMB)); err == nil {
+		cfg.AccessLogMaxSizeMB = maxSize
+	}
+	if rotateEvery, err := time.ParseDuration(os.Getenv(EnvAccessLogRotate)); err == nil {
+		cfg.AccessLogRotateEvery = rotateEvery
+	}
+	if backups, err := strconv.Atoi(os.Getenv(EnvAccessLogBackups)); err == nil {
+		cfg.AccessLogBackups = backups
+	}
+	if compress, err := strconv.ParseBool(os.Getenv(EnvAccessLogCompress)); err == nil {
+		cfg.AccessLogCompress = compress
+	}
+	if ttl, err := time.ParseDuration(os.Getenv(EnvCacheTTL)); err == nil {
+		cfg.CacheTTL = ttl
+	}
+	if maxEntries, err := strconv.Atoi(os.Getenv(EnvCacheMaxEntries)); err == nil {
+		cfg.CacheMaxEntries = maxEntries
+	}
+	if scheme := os.Getenv(EnvCategoryScheme); scheme != "" {
+		cfg.CategoryScheme = scheme
+	}
+	cfg.CategorySchemesFile = os.Getenv(EnvCategorySchemesFile)
+	if scheme := os.Getenv(EnvFrostScheme); scheme != "" {
+		cfg.FrostScheme = scheme
+	}
+	if minutely, err := strconv.ParseBool(os.Getenv(EnvNowcastMinutely)); err == nil {
+		cfg.NowcastMinutely = minutely
+	}
+	if oneCall, err := strconv.ParseBool(os.Getenv(EnvUVOneCall)); err == nil {
+		cfg.UVOneCall = oneCall
+	}
+	if oneCall, err := strconv.ParseBool(os.Getenv(EnvCurrentFromOneCall)); err == nil {
+		cfg.CurrentFromOneCall = oneCall
+	}
+	if nws, err := strconv.ParseBool(os.Getenv(EnvNWSAlerts)); err == nil {
+		cfg.NWSAlerts = nws
+	}
+	if nwsURL := os.Getenv(EnvNWSAlertsURL); nwsURL != "" {
+		cfg.NWSAlertsURL = nwsURL
+	}
+	if userAgent := os.Getenv(EnvNWSUserAgent); userAgent != "" {
+		cfg.NWSUserAgent = userAgent
+	}
+	cfg.HistoryURL = os.Getenv(EnvHistoryURL)
+	cfg.HistoryDir = os.Getenv(EnvHistoryDir)
+	if cfg.HistoryDir == "" {
+		// Memory grows with every location asked for, so keep far less than on disk
+		cfg.HistoryRetention = DefaultMemoryRetention
+	}
+	if retention, err := time.ParseDuration(os.Getenv(EnvHistoryRetention)); err == nil {
+		cfg.HistoryRetention = retention
+	}
+	if after, err := time.ParseDuration(os.Getenv(EnvHistoryHourlyAfter)); err == nil {
+		cfg.HistoryHourlyAfter = after
+	}
+	if after, err := time.ParseDuration(os.Getenv(EnvHistoryDailyAfter)); err == nil {
+		cfg.HistoryDailyAfter = after
+	}
+	if every, err := time.ParseDuration(os.Getenv(EnvHistoryCompactEvery)); err == nil {
+		cfg.HistoryCompactEvery = every
+	}
+
+	return cfg
+}
+
+// splitList splits a comma-separated value into its trimmed, non-empty elements.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
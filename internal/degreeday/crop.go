@@ -0,0 +1,43 @@
+package degreeday
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Crop holds the growing degree-day thresholds of a crop, in °C.
+type Crop struct {
+	Name    string
+	Base    float64
+	Ceiling float64 // NoCeiling when development does not level off
+}
+
+// DefaultCrop is the crop whose thresholds apply when none is selected.
+const DefaultCrop = "corn"
+
+// crops are the thresholds in common use by agricultural extension services.
+var crops = map[string]Crop{
+	"corn":    {Name: "corn", Base: 10, Ceiling: 30},          // 50/86 °F
+	"soybean": {Name: "soybean", Base: 10, Ceiling: 30},       // 50/86 °F
+	"sorghum": {Name: "sorghum", Base: 10, Ceiling: 37.8},     // 50/100 °F
+	"cotton":  {Name: "cotton", Base: 15.6, Ceiling: 37.8},    // DD60, 60/100 °F
+	"wheat":   {Name: "wheat", Base: 0, Ceiling: NoCeiling},   // 32 °F
+	"alfalfa": {Name: "alfalfa", Base: 5, Ceiling: NoCeiling}, // 41 °F
+}
+
+// LookupCrop returns the thresholds of a crop by name; the empty name selects DefaultCrop.
+func LookupCrop(name string) (Crop, error) {
+	if name == "" {
+		name = DefaultCrop
+	}
+	if crop, ok := crops[strings.ToLower(name)]; ok {
+		return crop, nil
+	}
+	names := make([]string, 0, len(crops))
+	for name := range crops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return Crop{}, fmt.Errorf("unknown crop %q, expected one of %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,102 @@
+// Package degreeday computes heating, cooling and growing degree-days from daily minimum and maximum
+// temperatures. Degree-days are in the unit of the temperatures and thresholds given.
+package degreeday
+
+import (
+	"fmt"
+	"math"
+)
+
+// Method is a way of estimating the temperature curve of a day from its minimum and maximum.
+type Method string
+
+const (
+	Average    Method = "average"     // The mean of the minimum and maximum stands for the whole day
+	SingleSine Method = "single-sine" // A sine curve from the minimum to the maximum and back (Baskerville & Emin, 1969)
+	DoubleSine Method = "double-sine" // A sine curve from the minimum to the maximum, then another to the next day's minimum
+)
+
+// ParseMethod validates a method name; the empty name selects the averaging method.
+func ParseMethod(name string) (Method, error) {
+	switch method := Method(name); method {
+	case "":
+		return Average, nil
+	case Average, SingleSine, DoubleSine:
+		return method, nil
+	}
+	return "", fmt.Errorf("unknown method %q, expected average, single-sine or double-sine", name)
+}
+
+// NoCeiling is the ceiling of growing degree-days without an upper threshold.
+var NoCeiling = math.Inf(1)
+
+// Day is the temperature range of a day. NextMin, the minimum of the following day, is only used by the
+// double-sine method.
+type Day struct {
+	Min, Max, NextMin float64
+}
+
+// Heating returns the heating degree-days of a day: how far, integrated over the day, the temperature was
+// below base.
+func Heating(day Day, base float64, method Method) float64 {
+	// The time below base and the time above it add up to the difference between base and the mean
+	return math.Max(0, base-mean(day, method)+Cooling(day, base, method))
+}
+
+// Cooling returns the cooling degree-days of a day: how far, integrated over the day, the temperature was
+// above base.
+func Cooling(day Day, base float64, method Method) float64 {
+	if method == Average {
+		return math.Max(0, mean(day, method)-base)
+	}
+	return Growing(day, base, NoCeiling, method)
+}
+
+// Growing returns the growing degree-days of a day above base. Temperatures above ceiling count as the
+// ceiling (a horizontal cutoff), as development stops rather than reverses there. The averaging method also
+// counts temperatures below base as base, as in the modified 86/50 method for corn, so a cold night does not
+// cancel out a warm afternoon.
+func Growing(day Day, base, ceiling float64, method Method) float64 {
+	switch method {
+	case SingleSine:
+		return singleSine(day.Min, day.Max, base, ceiling)
+	case DoubleSine:
+		return (singleSine(day.Min, day.Max, base, ceiling) + singleSine(day.NextMin, day.Max, base, ceiling)) / 2
+	}
+	clamp := func(temperature float64) float64 { return math.Min(math.Max(temperature, base), ceiling) }
+	return (clamp(day.Min)+clamp(day.Max))/2 - base
+}
+
+// mean returns the mean temperature of a day under a method.
+func mean(day Day, method Method) float64 {
+	if method == DoubleSine {
+		return (day.Min+day.NextMin)/4 + day.Max/2
+	}
+	return (day.Min + day.Max) / 2
+}
+
+// singleSine integrates the part of a sine curve from min to max and back between the lower and upper
+// thresholds over one day.
+func singleSine(min, max, lower, upper float64) float64 {
+	switch {
+	case min >= upper:
+		return upper - lower
+	case max <= lower:
+		return 0
+	}
+
+	mean, amplitude := (max+min)/2, (max-min)/2
+	// Phases, from -π/2 at the minimum to π/2 at the maximum, where the curve crosses the thresholds
+	lowerPhase, upperPhase := -math.Pi/2, math.Pi/2
+	if min < lower {
+		lowerPhase = math.Asin((lower - mean) / amplitude)
+	}
+	var capped float64 // Above the upper crossing the curve counts as the upper threshold
+	if max > upper {
+		upperPhase = math.Asin((upper - mean) / amplitude)
+		capped = (upper - lower) * (math.Pi/2 - upperPhase)
+	}
+
+	between := (mean-lower)*(upperPhase-lowerPhase) + amplitude*(math.Cos(lowerPhase)-math.Cos(upperPhase))
+	return (between + capped) / math.Pi
+}
@@ -0,0 +1,120 @@
+package degreeday
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// Temperatures in °F, as in the extension service and ASHRAE examples.
+func TestAverage(t *testing.T) {
+	tests := []struct {
+		name                   string
+		day                    Day
+		heating, cooling, corn float64
+	}{
+		{"Heating Day", Day{Min: 40, Max: 60}, 15, 0, 5},  // ASHRAE: mean 50 °F, base 65 °F; corn: (50 + 60) / 2 - 50
+		{"Cooling Day", Day{Min: 70, Max: 90}, 0, 15, 28}, // Corn: (86 + 70) / 2 - 50
+		{"Hot Day", Day{Min: 55, Max: 95}, 0, 10, 20.5},   // The maximum counts as 86 °F
+		{"Cold Day", Day{Min: 20, Max: 40}, 35, 0, 0},     // No growth below the base
+		{"Mild Day", Day{Min: 48, Max: 62}, 10, 0, 6},     // The minimum counts as 50 °F
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Heating(tc.day, 65, Average); !near(got, tc.heating) {
+				t.Errorf("Heating() = %v; want %v", got, tc.heating)
+			}
+			if got := Cooling(tc.day, 65, Average); !near(got, tc.cooling) {
+				t.Errorf("Cooling() = %v; want %v", got, tc.cooling)
+			}
+			if got := Growing(tc.day, 50, 86, Average); !near(got, tc.corn) {
+				t.Errorf("Growing() = %v; want %v", got, tc.corn)
+			}
+		})
+	}
+}
+
+// The cases of the single-sine method with a horizontal cutoff (Baskerville & Emin, 1969; UC IPM), with
+// a lower threshold of 50 °F and an upper one of 86 °F.
+func TestSingleSine(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+		expected float64
+	}{
+		{"Entirely Above Both Thresholds", 90, 100, 36},
+		{"Entirely Below Both Thresholds", 30, 45, 0},
+		{"Entirely Between Both Thresholds", 55, 75, 15},
+		{"Intercepted By The Lower Threshold", 40, 80, 12.18},
+		{"Intercepted By The Upper Threshold", 50, 100, 21.76},
+		{"Intercepted By Both Thresholds", 40, 100, 18.82},
+		{"Constant", 60, 60, 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Growing(Day{Min: tc.min, Max: tc.max}, 50, 86, SingleSine); !near(got, tc.expected) {
+				t.Errorf("Growing(%v, %v) = %v; want %v", tc.min, tc.max, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDoubleSine(t *testing.T) {
+	// The first half day rises from 40 to 80 °F (12.18 / 2), the second falls to 60 °F (20 / 2)
+	day := Day{Min: 40, Max: 80, NextMin: 60}
+	if got := Growing(day, 50, NoCeiling, DoubleSine); !near(got, 16.09) {
+		t.Errorf("Growing() = %v; want 16.09", got)
+	}
+
+	// With the same minimum on both days, it is the single-sine method
+	day.NextMin = 40
+	if single, double := Growing(day, 50, 86, SingleSine), Growing(day, 50, 86, DoubleSine); !near(single, double) {
+		t.Errorf("Double sine = %v; want the single-sine result %v", double, single)
+	}
+}
+
+func TestHeatingAndCooling(t *testing.T) {
+	day := Day{Min: 40, Max: 80, NextMin: 50}
+	if got := Heating(day, 65, SingleSine); !near(got, 9.07) {
+		t.Errorf("Heating() = %v; want 9.07", got)
+	}
+
+	// Heating and cooling degree-days differ by the difference between the base and the mean temperature
+	for _, method := range []Method{Average, SingleSine, DoubleSine} {
+		for _, base := range []float64{35, 55, 65, 85} {
+			heating, cooling := Heating(day, base, method), Cooling(day, base, method)
+			if !near(heating-cooling, base-mean(day, method)) || heating < 0 || cooling < 0 {
+				t.Errorf("%s at base %v: heating %v and cooling %v do not match the mean %v", method, base, heating, cooling, mean(day, method))
+			}
+		}
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	tests := map[string]Method{"": Average, "average": Average, "single-sine": SingleSine, "double-sine": DoubleSine}
+	for name, expected := range tests {
+		if method, err := ParseMethod(name); err != nil || method != expected {
+			t.Errorf("ParseMethod(%q) = %q, %v; want %q", name, method, err, expected)
+		}
+	}
+	if _, err := ParseMethod("triangle"); err == nil {
+		t.Errorf("Expected an error for an unknown method")
+	}
+}
+
+func TestLookupCrop(t *testing.T) {
+	if crop, err := LookupCrop(""); err != nil || crop.Name != DefaultCrop || crop.Base != 10 || crop.Ceiling != 30 {
+		t.Errorf("LookupCrop(\"\") = %+v, %v; want corn at 10-30 °C", crop, err)
+	}
+	if crop, err := LookupCrop("Wheat"); err != nil || crop.Ceiling != NoCeiling {
+		t.Errorf("LookupCrop(\"Wheat\") = %+v, %v; want wheat without a ceiling", crop, err)
+	}
+	if _, err := LookupCrop("kale"); err == nil {
+		t.Errorf("Expected an error for an unknown crop")
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/airquality"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+)
+
+// Limits of the air quality hours query parameter. OpenWeatherMap forecasts air pollution 4 days ahead.
+const (
+	DefaultAirForecastHours = 24
+	MaxAirForecastHours     = 96
+)
+
+// AirQualityHandler handles air quality HTTP requests.
+type AirQualityHandler struct {
+	Repo   repo.AirPollutionAPI // Interface for fetching air pollution
+	Config *config.AppConfig    // Application configuration settings
+}
+
+// NewAirQualityHandler creates an AirQualityHandler.
+func NewAirQualityHandler(repo repo.AirPollutionAPI, cfg *config.AppConfig) *AirQualityHandler {
+	return &AirQualityHandler{Repo: repo, Config: cfg}
+}
+
+// GetAirQualityByCoordinates handles HTTP requests for the current air quality and its hourly forecast by
+// coordinates.
+func (h *AirQualityHandler) GetAirQualityByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	hours := DefaultAirForecastHours
+	if text := query.Get("hours"); text != "" {
+		hours, err = strconv.Atoi(text)
+		if err != nil || hours < 0 || hours > MaxAirForecastHours {
+			http.Error(w, "Invalid query parameter: hours must be a whole number between 0 and 96", http.StatusBadRequest)
+			return
+		}
+	}
+
+	current, err := h.Repo.FetchAirPollution(r.Context(), coords.latText, coords.lonText, h.Config.AirPollutionURL)
+	if err == nil && len(current.List) == 0 {
+		err = fmt.Errorf("%w: air pollution response has no data", repo.ErrDecodingResponse)
+	}
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch air pollution", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		handleWeatherDataError(err, w)
+		return
+	}
+
+	response := model.AirQualityResponse{
+		Coordinates: model.Coordinates{Lat: coords.lat, Lon: coords.lon},
+		Current:     MapAirPollution(current.List[0]),
+		Forecast:    []model.AirQuality{},
+	}
+
+	if hours > 0 {
+		forecast, err := h.Repo.FetchAirPollutionForecast(r.Context(), coords.latText, coords.lonText, h.Config.AirPollutionURL)
+		if err != nil {
+			logger.WarnContext(r.Context(), "failed to fetch air pollution forecast", "lat", coords.latText, "lon", coords.lonText, "error", err)
+			handleWeatherDataError(err, w)
+			return
+		}
+		// The forecast starts with the current hour, which the current data already covers
+		for _, item := range forecast.List {
+			if len(response.Forecast) == hours {
+				break
+			}
+			if item.Dt > current.List[0].Dt {
+				response.Forecast = append(response.Forecast, MapAirPollution(item))
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// MapAirPollution computes the air quality indices of one hour of air pollution.
+func MapAirPollution(item model.AirPollutionItem) model.AirQuality {
+	concentrations := item.Components.Concentrations()
+	return model.AirQuality{
+		Time:       time.Unix(item.Dt, 0).UTC(),
+		Components: item.Components,
+		USAQI:      airquality.USAQI(concentrations),
+		CAQI:       airquality.CAQI(concentrations),
+		OWMIndex:   item.Main.AQI,
+	}
+}
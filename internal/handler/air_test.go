@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/airquality"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockAirPollutionAPI implementation for testing
+type MockAirPollutionAPI struct {
+	FetchFunc         func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error)
+	FetchForecastFunc func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error)
+}
+
+func (m *MockAirPollutionAPI) FetchAirPollution(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	return m.FetchFunc(ctx, lat, lon, openWeatherMapAirPollutionURL)
+}
+
+func (m *MockAirPollutionAPI) FetchAirPollutionForecast(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	return m.FetchForecastFunc(ctx, lat, lon, openWeatherMapAirPollutionURL)
+}
+
+var airStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// airItem builds the air pollution of the hour at the given hours after airStart.
+func airItem(hours int, pm25, o3 float64) model.AirPollutionItem {
+	item := model.AirPollutionItem{
+		Dt:         airStart.Add(time.Duration(hours) * time.Hour).Unix(),
+		Components: model.AirComponents{PM25: pm25, PM10: pm25 * 1.5, O3: o3, NO2: 20, SO2: 5, CO: 300},
+	}
+	item.Main.AQI = 2
+	return item
+}
+
+func newTestAirQualityHandler(t *testing.T) *AirQualityHandler {
+	mockAPI := &MockAirPollutionAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+			return model.AirPollutionData{List: []model.AirPollutionItem{airItem(0, 35.9, 80)}}, nil
+		},
+		FetchForecastFunc: func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+			assert.Equal(t, "http://example.com/air", openWeatherMapAirPollutionURL)
+			return model.AirPollutionData{List: []model.AirPollutionItem{airItem(0, 35.9, 80), airItem(1, 8, 60), airItem(2, 5, 130), airItem(3, 5, 60)}}, nil
+		},
+	}
+	return NewAirQualityHandler(mockAPI, &config.AppConfig{AirPollutionURL: "http://example.com/air"})
+}
+
+func TestAirQualityHandler_GetAirQualityByCoordinates_Success(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/air?lat=35&lon=139&hours=2", nil)
+	rr := httptest.NewRecorder()
+	newTestAirQualityHandler(t).GetAirQualityByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response model.AirQualityResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, model.Coordinates{Lat: 35, Lon: 139}, response.Coordinates)
+
+	current := response.Current
+	assert.Equal(t, airStart, current.Time)
+	assert.Equal(t, 2, current.OWMIndex)
+	assert.Equal(t, 102, current.USAQI.Value)
+	assert.Equal(t, airquality.PM25, current.USAQI.Dominant)
+	assert.Equal(t, "Unhealthy for Sensitive Groups", current.USAQI.Category)
+	assert.NotEmpty(t, current.USAQI.Advice)
+	assert.Equal(t, 56, current.CAQI.Value) // 35.9 μg/m³ of PM2.5 lies between the grid points 30 (50) and 55 (75)
+	assert.Equal(t, "Medium", current.CAQI.Category)
+
+	// The forecast skips the current hour and stops after the requested hours
+	if assert.Len(t, response.Forecast, 2) {
+		assert.Equal(t, airStart.Add(time.Hour), response.Forecast[0].Time)
+		assert.Equal(t, "Good", response.Forecast[0].USAQI.Category)
+		assert.Equal(t, airquality.O3, response.Forecast[1].USAQI.Dominant)
+		assert.Equal(t, 8, response.Forecast[1].CAQI.SubIndices[airquality.PM25])
+	}
+}
+
+func TestAirQualityHandler_GetAirQualityByCoordinates_NoForecast(t *testing.T) {
+	h := newTestAirQualityHandler(t)
+	h.Repo.(*MockAirPollutionAPI).FetchForecastFunc = nil // Must not be called
+
+	req, _ := http.NewRequest("GET", "/air?lat=35&lon=139&hours=0", nil)
+	rr := httptest.NewRecorder()
+	h.GetAirQualityByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"forecast":[]`)
+}
+
+func TestAirQualityHandler_GetAirQualityByCoordinates_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		data       model.AirPollutionData
+		err        error
+		wantStatus int
+	}{
+		{"Invalid Hours", "?lat=35&lon=139&hours=97", model.AirPollutionData{}, nil, http.StatusBadRequest},
+		{"Missing Coordinates", "?lat=35", model.AirPollutionData{}, nil, http.StatusBadRequest},
+		{"Upstream Error", "?lat=35&lon=139", model.AirPollutionData{}, repo.ErrServiceUnavailable, http.StatusServiceUnavailable},
+		{"No Data", "?lat=35&lon=139", model.AirPollutionData{}, nil, http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPI := &MockAirPollutionAPI{
+				FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+					return tc.data, tc.err
+				},
+			}
+			req, _ := http.NewRequest("GET", "/air"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			NewAirQualityHandler(mockAPI, &config.AppConfig{}).GetAirQualityByCoordinates(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+		})
+	}
+}
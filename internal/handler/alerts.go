@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/cap"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// capSender identifies this service as the sender of the CAP documents it builds.
+const capSender = "weather-service-api"
+
+// severityRank orders the CAP severities for sorting, most severe first.
+var severityRank = map[string]int{"Extreme": 4, "Severe": 3, "Moderate": 2, "Minor": 1}
+
+// keywordRule maps a whole word or phrase of an event name to a CAP value.
+type keywordRule struct {
+	keyword, value string
+}
+
+// severityKeywords derive a CAP severity from the event name of an alert relayed by OpenWeatherMap; the first
+// keyword found wins. Colours are the MeteoAlarm warning levels used across Europe.
+var severityKeywords = []keywordRule{
+	{"red flag", "Severe"}, // A US fire weather warning, not a MeteoAlarm level
+	{"red", "Extreme"},
+	{"orange", "Severe"},
+	{"yellow", "Moderate"},
+	{"emergency", "Extreme"},
+	{"extreme", "Extreme"},
+	{"warning", "Severe"},
+	{"watch", "Moderate"},
+	{"advisory", "Minor"},
+	{"statement", "Minor"},
+	{"outlook", "Minor"},
+}
+
+// certaintyKeywords derive a CAP certainty the same way: warnings are issued when an event is expected, watches
+// when it is possible.
+var certaintyKeywords = []keywordRule{
+	{"watch", "Possible"},
+	{"outlook", "Possible"},
+	{"warning", "Likely"},
+	{"emergency", "Likely"},
+	{"advisory", "Likely"},
+	{"statement", "Likely"},
+}
+
+// usRegions are the bounding boxes of the US states and territories that the National Weather Service covers.
+// They include some border areas of Canada and Mexico, where NWS simply reports no alerts.
+var usRegions = []struct{ minLat, maxLat, minLon, maxLon float64 }{
+	{24.4, 49.4, -125, -66.9},    // Contiguous United States
+	{51, 71.5, -180, -129.9},     // Alaska
+	{51, 55, 172, 180},           // Western Aleutians, across the antimeridian
+	{18.9, 22.3, -160.3, -154.8}, // Hawaii
+	{17.6, 18.6, -67.3, -64.5},   // Puerto Rico and the US Virgin Islands
+	{13.2, 20.6, 144.6, 146.1},   // Guam and the Northern Mariana Islands
+	{-14.6, -11, -171.1, -168.1}, // American Samoa
+}
+
+// AlertsHandler serves the official weather warnings active at a point as JSON or CAP.
+type AlertsHandler struct {
+	OneCall repo.OneCallAPI   // Source of alerts relayed by OpenWeatherMap
+	NWS     repo.NWSAlertsAPI // Preferred source for US points; nil when it is not enabled
+	Config  *config.AppConfig // Application configuration settings
+	now     func() time.Time  // Replaced in tests
+}
+
+// NewAlertsHandler creates an AlertsHandler. nws may be nil, in which case One Call serves every point.
+func NewAlertsHandler(oneCall repo.OneCallAPI, nws repo.NWSAlertsAPI, cfg *config.AppConfig) *AlertsHandler {
+	return &AlertsHandler{OneCall: oneCall, NWS: nws, Config: cfg, now: time.Now}
+}
+
+// GetAlertsByCoordinates handles HTTP requests for the active weather alerts by coordinates. The response is
+// a CAP 1.2 document when the Accept header prefers application/cap+xml, and JSON otherwise.
+func (h *AlertsHandler) GetAlertsByCoordinates(w http.ResponseWriter, r *http.Request) {
+	coords, err := parseCoordinates(r.URL.Query())
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	now := h.now()
+	source, alerts, err := h.fetchAlerts(r.Context(), coords, now)
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch alerts", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		handleWeatherDataError(err, w)
+		return
+	}
+	alerts = activeAlerts(alerts, now)
+
+	w.Header().Set("Vary", "Accept")
+	if prefersCAP(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", cap.ContentType)
+		if err := capAlert(alerts, coords, now).Encode(w); err != nil {
+			logger.WarnContext(r.Context(), "failed to write CAP document", "error", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.AlertsResponse{
+		Coordinates: model.Coordinates{Lat: coords.lat, Lon: coords.lon},
+		Source:      source,
+		Alerts:      alerts,
+	})
+}
+
+// fetchAlerts takes alerts for US points from the National Weather Service when enabled; an empty answer means
+// there are no alerts for the point. When NWS fails, the alerts relayed by One Call are used instead.
+func (h *AlertsHandler) fetchAlerts(ctx context.Context, coords coordinates, now time.Time) (string, []model.WeatherAlert, error) {
+	if h.NWS != nil && isUSPoint(coords.lat, coords.lon) {
+		alerts, err := h.NWS.FetchActiveAlerts(ctx, coords.lat, coords.lon)
+		if err != nil {
+			logger.WarnContext(ctx, "failed to fetch NWS alerts, using One Call", "error", err)
+		} else {
+			return model.AlertSourceNWS, mapNWSAlerts(alerts), nil
+		}
+	}
+
+	oneCall, err := h.OneCall.FetchOneCall(ctx, coords.latText, coords.lonText, h.Config.OneCallURL, units.CanonicalSystem, repo.ExcludeAllBut(repo.BlockAlerts))
+	if err != nil {
+		return "", nil, err
+	}
+	return model.AlertSourceOpenWeatherMap, mapOneCallAlerts(oneCall.Alerts, now), nil
+}
+
+// mapOneCallAlerts maps the alerts relayed by the One Call API to the response format. OpenWeatherMap reports
+// no CAP qualifiers, so they are derived from the event name and the times relative to now.
+func mapOneCallAlerts(alerts []model.OneCallAlert, now time.Time) []model.WeatherAlert {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	mapped := make([]model.WeatherAlert, len(alerts))
+	for i, alert := range alerts {
+		onset, expires := time.Unix(alert.Start, 0).UTC(), time.Unix(alert.End, 0).UTC()
+		words := eventWords(alert.Event)
+		mapped[i] = model.WeatherAlert{
+			Source:      model.AlertSourceOpenWeatherMap,
+			Sender:      alert.SenderName,
+			Event:       alert.Event,
+			Severity:    matchKeyword(words, severityKeywords),
+			Urgency:     alertUrgency(onset, expires, now),
+			Certainty:   matchKeyword(words, certaintyKeywords),
+			Onset:       onset,
+			Expires:     expires,
+			Description: alert.Description,
+			Tags:        alert.Tags,
+		}
+	}
+	return mapped
+}
+
+// mapNWSAlerts maps National Weather Service alerts, which carry their own CAP qualifiers, to the response
+// format. Cancellations of earlier alerts are dropped.
+func mapNWSAlerts(alerts []model.NWSAlert) []model.WeatherAlert {
+	mapped := make([]model.WeatherAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.MessageType == "Cancel" {
+			continue
+		}
+		mapped = append(mapped, model.WeatherAlert{
+			ID:          alert.ID,
+			Source:      model.AlertSourceNWS,
+			Sender:      alert.SenderName,
+			Event:       alert.Event,
+			Headline:    alert.Headline,
+			Severity:    orUnknown(alert.Severity),
+			Urgency:     orUnknown(alert.Urgency),
+			Certainty:   orUnknown(alert.Certainty),
+			Onset:       firstTime(alert.Onset, alert.Effective, alert.Sent),
+			Expires:     firstTime(alert.Ends, alert.Expires), // Ends is when the hazard ends, Expires when the message does
+			Description: alert.Description,
+			Instruction: alert.Instruction,
+			Area:        alert.AreaDesc,
+		})
+	}
+	return mapped
+}
+
+// activeAlerts drops expired alerts and sorts the rest by severity, then by onset.
+func activeAlerts(alerts []model.WeatherAlert, now time.Time) []model.WeatherAlert {
+	active := make([]model.WeatherAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.Expires.IsZero() || alert.Expires.After(now) {
+			active = append(active, alert)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if rank, other := severityRank[active[i].Severity], severityRank[active[j].Severity]; rank != other {
+			return rank > other
+		}
+		return active[i].Onset.Before(active[j].Onset)
+	})
+	return active
+}
+
+// alertUrgency derives the CAP urgency of an alert from its times. CAP 1.2 expects action within the next hour
+// for Expected and later for Future.
+func alertUrgency(onset, expires, now time.Time) string {
+	switch {
+	case !expires.IsZero() && !expires.After(now):
+		return "Past"
+	case !onset.After(now):
+		return "Immediate"
+	case onset.Sub(now) <= time.Hour:
+		return "Expected"
+	default:
+		return "Future"
+	}
+}
+
+// eventWords lower-cases an event name and separates its words with single spaces, padded at both ends so
+// keywords only match whole words.
+func eventWords(event string) string {
+	words := strings.FieldsFunc(strings.ToLower(event), func(r rune) bool { return !unicode.IsLetter(r) })
+	return " " + strings.Join(words, " ") + " "
+}
+
+// matchKeyword returns the value of the first keyword found in words, or Unknown.
+func matchKeyword(words string, rules []keywordRule) string {
+	for _, rule := range rules {
+		if strings.Contains(words, " "+rule.keyword+" ") {
+			return rule.value
+		}
+	}
+	return cap.ValueUnknown
+}
+
+// orUnknown returns value, or Unknown when the source left a CAP qualifier empty.
+func orUnknown(value string) string {
+	if value == "" {
+		return cap.ValueUnknown
+	}
+	return value
+}
+
+// firstTime returns the first of times that is set, in UTC.
+func firstTime(times ...*time.Time) time.Time {
+	for _, t := range times {
+		if t != nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// isUSPoint reports whether a point lies within the coverage of the National Weather Service.
+func isUSPoint(lat, lon float64) bool {
+	for _, region := range usRegions {
+		if lat >= region.minLat && lat <= region.maxLat && lon >= region.minLon && lon <= region.maxLon {
+			return true
+		}
+	}
+	return false
+}
+
+// prefersCAP reports whether an Accept header ranks application/cap+xml above application/json. JSON wins
+// ties and is the default.
+func prefersCAP(accept string) bool {
+	capQuality, jsonQuality := 0.0, 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		switch mediaType {
+		case cap.ContentType:
+			capQuality = max(capQuality, quality)
+		case "application/json", "application/*", "*/*":
+			jsonQuality = max(jsonQuality, quality)
+		}
+	}
+	return capQuality > 0 && capQuality > jsonQuality
+}
+
+// capAlert builds the CAP message listing the active alerts at a point, one info block per alert.
+func capAlert(alerts []model.WeatherAlert, coords coordinates, now time.Time) cap.Alert {
+	point := strconv.FormatFloat(coords.lat, 'f', 4, 64) + "," + strconv.FormatFloat(coords.lon, 'f', 4, 64)
+	sent := now.UTC().Truncate(time.Second)
+
+	message := cap.Alert{
+		// Identifiers must not contain commas or spaces
+		Identifier: fmt.Sprintf("%s-%s-%d", capSender, strings.ReplaceAll(point, ",", "_"), sent.Unix()),
+		Sender:     capSender,
+		Sent:       cap.Time(sent),
+		Status:     cap.StatusActual,
+		MsgType:    cap.MsgTypeAlert,
+		Scope:      cap.ScopePublic,
+		Info:       make([]cap.Info, 0, len(alerts)),
+	}
+	for _, alert := range alerts {
+		info := cap.Info{
+			Category:    cap.CategoryMet,
+			Event:       alert.Event,
+			Urgency:     alert.Urgency,
+			Severity:    alert.Severity,
+			Certainty:   alert.Certainty,
+			SenderName:  alert.Sender,
+			Headline:    alert.Headline,
+			Description: alert.Description,
+			Instruction: alert.Instruction,
+			Area:        []cap.Area{{AreaDesc: alert.Area, Circle: point + " 0"}},
+		}
+		if alert.Source == model.AlertSourceNWS {
+			info.Language = "en-US"
+		}
+		if info.Area[0].AreaDesc == "" {
+			info.Area[0].AreaDesc = point
+		}
+		if !alert.Onset.IsZero() {
+			onset := cap.Time(alert.Onset)
+			info.Onset = &onset
+		}
+		if !alert.Expires.IsZero() {
+			expires := cap.Time(alert.Expires)
+			info.Expires = &expires
+		}
+		message.Info = append(message.Info, info)
+	}
+	return message
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/cap"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockNWSAlertsAPI implementation for testing
+type MockNWSAlertsAPI struct {
+	FetchFunc func(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error)
+}
+
+func (m *MockNWSAlertsAPI) FetchActiveAlerts(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error) {
+	return m.FetchFunc(ctx, lat, lon)
+}
+
+var alertsNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// oneCallAlerts returns an expired alert, a watch starting in 2 days and a warning in effect.
+func oneCallAlerts() *MockOneCallAPI {
+	return &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{Lat: 35, Lon: 139, Alerts: []model.OneCallAlert{
+				{SenderName: "JMA", Event: "Gale Advisory", Start: alertsNow.Add(-6 * time.Hour).Unix(), End: alertsNow.Add(-time.Hour).Unix()},
+				{SenderName: "JMA", Event: "Flood Watch", Start: alertsNow.Add(48 * time.Hour).Unix(), End: alertsNow.Add(60 * time.Hour).Unix()},
+				{SenderName: "JMA", Event: "Heavy Rain Warning", Start: alertsNow.Add(-time.Hour).Unix(), End: alertsNow.Add(6 * time.Hour).Unix(), Description: "Heavy rain expected."},
+			}}, nil
+		},
+	}
+}
+
+func newTestAlertsHandler(oneCall *MockOneCallAPI, nws repo.NWSAlertsAPI) *AlertsHandler {
+	h := NewAlertsHandler(oneCall, nws, &config.AppConfig{OneCallURL: "http://example.com/onecall"})
+	h.now = func() time.Time { return alertsNow }
+	return h
+}
+
+func getAlerts(t *testing.T, h *AlertsHandler, query string) model.AlertsResponse {
+	req, _ := http.NewRequest("GET", "/alerts"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetAlertsByCoordinates(rr, req)
+
+	var response model.AlertsResponse
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_OneCall(t *testing.T) {
+	oneCall := oneCallAlerts()
+	fetch := oneCall.FetchOneCallFunc
+	oneCall.FetchOneCallFunc = func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+		assert.Equal(t, "http://example.com/onecall", openWeatherMapOneCallURL)
+		assert.Equal(t, repo.ExcludeAllBut(repo.BlockAlerts), exclude)
+		return fetch(ctx, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement, exclude)
+	}
+
+	response := getAlerts(t, newTestAlertsHandler(oneCall, nil), "?lat=35&lon=139")
+	assert.Equal(t, model.AlertSourceOpenWeatherMap, response.Source)
+	assert.Equal(t, model.Coordinates{Lat: 35, Lon: 139}, response.Coordinates)
+	assert.Equal(t, []model.WeatherAlert{
+		{
+			Source: "owm", Sender: "JMA", Event: "Heavy Rain Warning",
+			Severity: "Severe", Urgency: "Immediate", Certainty: "Likely",
+			Onset: alertsNow.Add(-time.Hour), Expires: alertsNow.Add(6 * time.Hour), Description: "Heavy rain expected.",
+		},
+		{
+			Source: "owm", Sender: "JMA", Event: "Flood Watch",
+			Severity: "Moderate", Urgency: "Future", Certainty: "Possible",
+			Onset: alertsNow.Add(48 * time.Hour), Expires: alertsNow.Add(60 * time.Hour),
+		},
+	}, response.Alerts)
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_NoAlerts(t *testing.T) {
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{Lat: 35, Lon: 139}, nil
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/alerts?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	newTestAlertsHandler(oneCall, nil).GetAlertsByCoordinates(rr, req)
+
+	assert.Contains(t, rr.Body.String(), `"alerts":[]`)
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_NWS(t *testing.T) {
+	onset := alertsNow.Add(2 * time.Hour)
+	ends := alertsNow.Add(8 * time.Hour)
+	nws := &MockNWSAlertsAPI{
+		FetchFunc: func(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error) {
+			assert.Equal(t, 30.27, lat)
+			assert.Equal(t, -97.74, lon)
+			return []model.NWSAlert{
+				{ID: "urn:oid:1", MessageType: "Cancel", Event: "Wind Advisory"},
+				{
+					ID: "urn:oid:2", AreaDesc: "Travis, TX", Onset: &onset, Ends: &ends, MessageType: "Alert",
+					Severity: "Severe", Certainty: "Likely", Urgency: "Expected", Event: "Flash Flood Warning",
+					SenderName: "NWS Austin/San Antonio TX", Headline: "Flash Flood Warning issued", Instruction: "Turn around, don't drown.",
+				},
+			}, nil
+		},
+	}
+
+	// One Call must not be asked when NWS reports alerts
+	response := getAlerts(t, newTestAlertsHandler(&MockOneCallAPI{}, nws), "?lat=30.27&lon=-97.74")
+	assert.Equal(t, model.AlertSourceNWS, response.Source)
+	assert.Equal(t, []model.WeatherAlert{{
+		ID: "urn:oid:2", Source: "nws", Sender: "NWS Austin/San Antonio TX", Event: "Flash Flood Warning",
+		Headline: "Flash Flood Warning issued", Severity: "Severe", Urgency: "Expected", Certainty: "Likely",
+		Onset: onset, Expires: ends, Instruction: "Turn around, don't drown.", Area: "Travis, TX",
+	}}, response.Alerts)
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_NWSNoAlerts(t *testing.T) {
+	nws := &MockNWSAlertsAPI{
+		FetchFunc: func(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error) {
+			return nil, nil
+		},
+	}
+
+	// An empty NWS answer is authoritative, so One Call must not be asked
+	response := getAlerts(t, newTestAlertsHandler(&MockOneCallAPI{}, nws), "?lat=30.27&lon=-97.74")
+	assert.Equal(t, model.AlertSourceNWS, response.Source)
+	assert.Empty(t, response.Alerts)
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_NWSFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		nwsErr error
+		called bool
+	}{
+		{"NWS Unavailable", "?lat=30.27&lon=-97.74", repo.ErrNWSUnavailable, true},
+		{"Outside The US", "?lat=35&lon=139", nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			nws := &MockNWSAlertsAPI{
+				FetchFunc: func(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error) {
+					called = true
+					return nil, tc.nwsErr
+				},
+			}
+
+			response := getAlerts(t, newTestAlertsHandler(oneCallAlerts(), nws), tc.query)
+			assert.Equal(t, tc.called, called)
+			assert.Equal(t, model.AlertSourceOpenWeatherMap, response.Source)
+			assert.Len(t, response.Alerts, 2)
+		})
+	}
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_CAP(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/alerts?lat=35&lon=139", nil)
+	req.Header.Set("Accept", "application/cap+xml, application/json;q=0.5")
+	rr := httptest.NewRecorder()
+	newTestAlertsHandler(oneCallAlerts(), nil).GetAlertsByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, cap.ContentType, rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+
+	body := rr.Body.String()
+	assert.True(t, strings.HasPrefix(body, xml.Header))
+	assert.Contains(t, body, `<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">`)
+	assert.Contains(t, body, "<identifier>weather-service-api-35.0000_139.0000-1704110400</identifier>")
+	assert.Contains(t, body, "<sent>2024-01-01T12:00:00+00:00</sent>")
+
+	var document struct {
+		Info []struct {
+			Event    string `xml:"event"`
+			Severity string `xml:"severity"`
+			Onset    string `xml:"onset"`
+			Area     struct {
+				AreaDesc string `xml:"areaDesc"`
+				Circle   string `xml:"circle"`
+			} `xml:"area"`
+		} `xml:"info"`
+	}
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &document))
+	if assert.Len(t, document.Info, 2) {
+		assert.Equal(t, "Heavy Rain Warning", document.Info[0].Event)
+		assert.Equal(t, "Severe", document.Info[0].Severity)
+		assert.Equal(t, "2024-01-01T11:00:00+00:00", document.Info[0].Onset)
+		assert.Equal(t, "35.0000,139.0000", document.Info[0].Area.AreaDesc)
+		assert.Equal(t, "35.0000,139.0000 0", document.Info[0].Area.Circle)
+	}
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_FetchError(t *testing.T) {
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{}, repo.ErrInvalidAPIKey
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/alerts?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	newTestAlertsHandler(oneCall, nil).GetAlertsByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAlertsHandler_GetAlertsByCoordinates_MissingParams(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/alerts?lat=35", nil)
+	rr := httptest.NewRecorder()
+	newTestAlertsHandler(oneCallAlerts(), nil).GetAlertsByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Missing required query parameters: lat and/or lon\n", rr.Body.String())
+}
+
+func TestOneCallAlertQualifiers(t *testing.T) {
+	tests := []struct {
+		event     string
+		severity  string
+		certainty string
+	}{
+		{"Heavy Rain Warning", "Severe", "Likely"},
+		{"Yellow Wind Warning", "Moderate", "Likely"},
+		{"Red warning - thunderstorms", "Extreme", "Likely"},
+		{"Red Flag Warning", "Severe", "Likely"},
+		{"Tornado Watch", "Moderate", "Possible"},
+		{"Extreme Heat Emergency", "Extreme", "Likely"},
+		{"Special Weather Statement", "Minor", "Likely"},
+		{"Fog", "Unknown", "Unknown"},
+		{"Predicted storm", "Unknown", "Unknown"}, // "red" only counts as a whole word
+	}
+
+	for _, tc := range tests {
+		words := eventWords(tc.event)
+		assert.Equal(t, tc.severity, matchKeyword(words, severityKeywords), tc.event)
+		assert.Equal(t, tc.certainty, matchKeyword(words, certaintyKeywords), tc.event)
+	}
+}
+
+func TestAlertUrgency(t *testing.T) {
+	tests := []struct {
+		onset, expires time.Duration
+		expected       string
+	}{
+		{-2 * time.Hour, -time.Hour, "Past"},
+		{-time.Hour, time.Hour, "Immediate"},
+		{30 * time.Minute, 30 * time.Hour, "Expected"},
+		{time.Hour, 30 * time.Hour, "Expected"},
+		{time.Hour + time.Minute, 30 * time.Hour, "Future"},
+		{24 * time.Hour, 30 * time.Hour, "Future"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, alertUrgency(alertsNow.Add(tc.onset), alertsNow.Add(tc.expires), alertsNow))
+	}
+}
+
+func TestIsUSPoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		expected bool
+	}{
+		{"Austin", 30.27, -97.74, true},
+		{"Anchorage", 61.22, -149.9, true},
+		{"Honolulu", 21.31, -157.86, true},
+		{"San Juan", 18.47, -66.11, true},
+		{"Guam", 13.44, 144.79, true},
+		{"Pago Pago", -14.28, -170.7, true},
+		{"Tokyo", 35.68, 139.69, false},
+		{"Mexico City", 19.43, -99.13, false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, isUSPoint(tc.lat, tc.lon), tc.name)
+	}
+}
+
+func TestPrefersCAP(t *testing.T) {
+	tests := map[string]bool{
+		"":                                      false,
+		"application/json":                      false,
+		"application/cap+xml":                   true,
+		"application/cap+xml, */*;q=0.8":        true,
+		"application/json, application/cap+xml": false,
+		"application/cap+xml;q=0.5, */*":        false,
+		"application/cap+xml;q=0":               false,
+		"text/html, application/cap+xml;q=0.9":  true,
+	}
+
+	for accept, expected := range tests {
+		assert.Equal(t, expected, prefersCAP(accept), "Accept: %q", accept)
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/astro"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// AstronomyHandler serves sun and moon data computed in-process, without calling Open Weather Map.
+type AstronomyHandler struct {
+	now func() time.Time // Replaced in tests
+}
+
+// NewAstronomyHandler creates an AstronomyHandler using the system clock.
+func NewAstronomyHandler() *AstronomyHandler {
+	return &AstronomyHandler{now: time.Now}
+}
+
+// GetAstronomy handles HTTP requests for solar and lunar data by coordinates and an optional date.
+func (h *AstronomyHandler) GetAstronomy(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	// Without a date, report today at the location and the sun's current position. Local mean solar
+	// time needs no timezone database and keeps solar noon near 12:00.
+	solarOffset := time.Duration(coords.lon / 15 * float64(time.Hour))
+	at := h.now().UTC()
+	date := at.Add(solarOffset)
+	fixedDate := query.Get("date") != ""
+	if fixedDate {
+		date, err = time.Parse(dateLayout, query.Get("date"))
+		if err != nil {
+			http.Error(w, "Invalid date: expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sun := astro.SunTimesFor(date, coords.lat, coords.lon)
+	if fixedDate {
+		// A requested date has no current position, so describe the sky at its solar noon
+		at = sun.SolarNoon
+	}
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Add(-solarOffset)
+
+	response := model.AstronomyResponse{
+		Date:       date.Format(dateLayout),
+		Latitude:   coords.lat,
+		Longitude:  coords.lon,
+		ComputedAt: at,
+		Sun:        model.SunInfo{SunTimes: sun, Position: astro.SunPosition(at, coords.lat, coords.lon)},
+		Moon:       astro.MoonFor(at, dayStart, coords.lat, coords.lon),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/astro"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAstronomyHandler(now time.Time) *AstronomyHandler {
+	return &AstronomyHandler{now: func() time.Time { return now }}
+}
+
+func getAstronomy(h *AstronomyHandler, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/astronomy"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetAstronomy(rr, req)
+	return rr
+}
+
+func TestAstronomyHandler_GetAstronomy_Date(t *testing.T) {
+	h := newTestAstronomyHandler(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// NOAA solar calculator, London on the 2024 summer solstice: sunrise 03:43, solar noon 12:02, sunset 20:21 UTC
+	rr := getAstronomy(h, "?lat=51.5074&lon=-0.1278&date=2024-06-21")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response model.AstronomyResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "2024-06-21", response.Date)
+	assert.Equal(t, astro.DayTypeNormal, response.Sun.DayType)
+	assert.WithinDuration(t, time.Date(2024, 6, 21, 3, 43, 0, 0, time.UTC), *response.Sun.Sunrise, time.Minute)
+	assert.WithinDuration(t, time.Date(2024, 6, 21, 12, 2, 0, 0, time.UTC), response.Sun.SolarNoon, time.Minute)
+	assert.WithinDuration(t, time.Date(2024, 6, 21, 20, 21, 0, 0, time.UTC), *response.Sun.Sunset, time.Minute)
+
+	// A requested date is described at its solar noon, when the sun is due south at 62 degrees
+	assert.Equal(t, response.Sun.SolarNoon, response.ComputedAt)
+	assert.InDelta(t, 61.9, response.Sun.Position.Elevation, 0.2)
+	assert.InDelta(t, 180, response.Sun.Position.Azimuth, 0.5)
+	assert.NotEmpty(t, response.Moon.PhaseName)
+}
+
+func TestAstronomyHandler_GetAstronomy_Today(t *testing.T) {
+	// 23:30 UTC is already the next day in Tokyo's mean solar time
+	now := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	h := newTestAstronomyHandler(now)
+
+	rr := getAstronomy(h, "?lat=35.6762&lon=139.6503")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.AstronomyResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "2024-03-11", response.Date)
+	assert.True(t, response.ComputedAt.Equal(now))
+	assert.Greater(t, response.Sun.Position.Elevation, 0.0) // 08:30 in Tokyo
+}
+
+func TestAstronomyHandler_GetAstronomy_PolarNight(t *testing.T) {
+	h := newTestAstronomyHandler(time.Now())
+
+	rr := getAstronomy(h, "?lat=78.2232&lon=15.6267&date=2024-12-21")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	sun := body["sun"].(map[string]any)
+	assert.Equal(t, "polarNight", sun["dayType"])
+	assert.NotContains(t, sun, "sunrise")
+	assert.NotContains(t, sun, "sunset")
+	assert.Equal(t, 0.0, sun["dayLengthSeconds"])
+}
+
+func TestAstronomyHandler_GetAstronomy_InvalidParams(t *testing.T) {
+	h := NewAstronomyHandler()
+
+	testCases := []struct {
+		name     string
+		query    string
+		wantBody string
+	}{
+		{"Missing Coordinates", "", "Missing required query parameters: lat and/or lon\n"},
+		{"Lat Out Of Range", "?lat=91&lon=0", "Invalid query parameters: lat must be a number between -90 and 90, got \"91\"\n"},
+		{"Lat NaN", "?lat=NaN&lon=0", "Invalid query parameters: lat must be a number between -90 and 90, got \"NaN\"\n"},
+		{"Lon Infinite", "?lat=0&lon=%2BInf", "Invalid query parameters: lon must be a number between -180 and 180, got \"+Inf\"\n"},
+		{"Invalid Date", "?lat=0&lon=0&date=21/06/2024", "Invalid date: expected YYYY-MM-DD\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := getAstronomy(h, tc.query)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, tc.wantBody, rr.Body.String())
+		})
+	}
+}
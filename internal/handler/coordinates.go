@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var errMissingCoordinates = errors.New("missing lat and/or lon")
+
+// coordinates are the validated lat and lon query parameters. The original text is kept so upstream
+// requests and cache keys are unaffected by float formatting.
+type coordinates struct {
+	lat, lon         float64
+	latText, lonText string
+}
+
+// parseCoordinates reads and validates the lat and lon query parameters shared by the endpoints.
+func parseCoordinates(query url.Values) (coordinates, error) {
+	c := coordinates{latText: query.Get("lat"), lonText: query.Get("lon")}
+	if c.latText == "" || c.lonText == "" {
+		return c, errMissingCoordinates
+	}
+
+	// ParseFloat accepts "NaN" and "Inf", which would slip through the range checks or break the astronomy math
+	var err error
+	if c.lat, err = strconv.ParseFloat(c.latText, 64); err != nil || !isFinite(c.lat) || c.lat < -90 || c.lat > 90 {
+		return c, fmt.Errorf("lat must be a number between -90 and 90, got %q", c.latText)
+	}
+	if c.lon, err = strconv.ParseFloat(c.lonText, 64); err != nil || !isFinite(c.lon) || c.lon < -180 || c.lon > 180 {
+		return c, fmt.Errorf("lon must be a number between -180 and 180, got %q", c.lonText)
+	}
+	return c, nil
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// writeCoordinatesError responds to a request whose coordinates failed to parse.
+func writeCoordinatesError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errMissingCoordinates) {
+		http.Error(w, "Missing required query parameters: lat and/or lon", http.StatusBadRequest)
+		return
+	}
+	http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/degreeday"
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Defaults and limits of the degree-day query parameters.
+const (
+	DefaultDegreeDayRange = 30  // Days
+	MaxDegreeDayRange     = 366 // Days
+	DefaultDegreeDayBase  = 18  // °C, the base of heating and cooling degree-days outside the US
+	DefaultDegreeDayBaseF = 65  // °F, the base in the US
+)
+
+// minDayObservations is the number of observations a day needs for its minimum and maximum to be trusted.
+const minDayObservations = 8
+
+// DegreeDaysHandler serves heating, cooling and growing degree-days computed from past observations.
+type DegreeDaysHandler struct {
+	Store  history.Store     // Recorded observations
+	Repo   repo.HistoryAPI   // Fallback for periods without recorded observations; nil to disable it
+	Config *config.AppConfig // Application configuration settings
+	now    func() time.Time  // Replaced in tests
+}
+
+// NewDegreeDaysHandler creates a DegreeDaysHandler. repo may be nil, in which case only recorded
+// observations are used.
+func NewDegreeDaysHandler(store history.Store, repo repo.HistoryAPI, cfg *config.AppConfig) *DegreeDaysHandler {
+	return &DegreeDaysHandler{Store: store, Repo: repo, Config: cfg, now: time.Now}
+}
+
+// GetDegreeDaysByCoordinates handles HTTP requests for the degree-days of each day from from to to, both
+// local dates, in the output temperature unit.
+func (h *DegreeDaysHandler) GetDegreeDaysByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	method, err := degreeday.ParseMethod(query.Get("method"))
+	if err != nil {
+		http.Error(w, "Invalid method: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	crop, err := degreeday.LookupCrop(query.Get("crop"))
+	if err != nil {
+		http.Error(w, "Invalid crop: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit := output.Temperature
+	fromCelsius := func(value float64) float64 {
+		if math.IsInf(value, 0) {
+			return value
+		}
+		converted, _ := units.ConvertTemperature(value, units.Celsius, unit)
+		return converted
+	}
+	base := fromCelsius(DefaultDegreeDayBase)
+	if unit == units.Fahrenheit {
+		base = DefaultDegreeDayBaseF
+	}
+	growing := degreeday.Crop{Name: crop.Name, Base: fromCelsius(crop.Base), Ceiling: fromCelsius(crop.Ceiling)}
+	for _, threshold := range []struct {
+		name  string
+		value *float64
+	}{{"base", &base}, {"gddBase", &growing.Base}, {"gddCeiling", &growing.Ceiling}} {
+		text := query.Get(threshold.name)
+		if text == "" {
+			continue
+		}
+		if threshold.name == "gddCeiling" && text == "none" {
+			*threshold.value = degreeday.NoCeiling
+			continue
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			http.Error(w, "Invalid query parameter: "+threshold.name+" must be a temperature in "+unit.Symbol(), http.StatusBadRequest)
+			return
+		}
+		*threshold.value = value
+	}
+	if query.Get("gddBase") != "" && query.Get("gddCeiling") != "" {
+		growing.Name = ""
+	}
+	if growing.Ceiling <= growing.Base {
+		http.Error(w, "Invalid query parameters: gddCeiling must be above gddBase", http.StatusBadRequest)
+		return
+	}
+
+	// Dates are local to the location; until observations tell its timezone, local mean solar time is close
+	zone := time.FixedZone("", localOffset(nil, coords.lon))
+	now := h.now().UTC()
+	today := now.In(zone)
+	lastDay := time.Date(today.Year(), today.Month(), today.Day()-1, 0, 0, 0, 0, time.UTC)
+	if text := query.Get("to"); text != "" {
+		if lastDay, err = time.Parse(dateLayout, text); err != nil {
+			http.Error(w, "Invalid query parameter: to must be a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+	}
+	firstDay := lastDay.AddDate(0, 0, 1-DefaultDegreeDayRange)
+	if text := query.Get("from"); text != "" {
+		if firstDay, err = time.Parse(dateLayout, text); err != nil {
+			http.Error(w, "Invalid query parameter: from must be a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+	}
+	if lastDay.Before(firstDay) {
+		http.Error(w, "Invalid query parameters: from must not be after to", http.StatusBadRequest)
+		return
+	}
+	if lastDay.Sub(firstDay) >= MaxDegreeDayRange*24*time.Hour {
+		http.Error(w, "Invalid query parameters: the range from from to to must not exceed 366 days", http.StatusBadRequest)
+		return
+	}
+	if !firstDay.Before(now) {
+		http.Error(w, "Invalid query parameters: from must be in the past", http.StatusBadRequest)
+		return
+	}
+
+	// Local days span from 14 hours before to 12 hours after the UTC day, and the double-sine method needs
+	// the minimum of the day after to
+	records, err := fetchHistoryRecords(r.Context(), h.Store, h.Repo, h.Config.HistoryURL, coords,
+		firstDay.Add(-24*time.Hour), minTime(lastDay.Add(72*time.Hour), now))
+	if err != nil {
+		handleWeatherDataError(err, w)
+		return
+	}
+	offset := localOffset(records.observations, coords.lon)
+	for i := range records.observations {
+		records.observations[i].TimezoneOffset = offset
+	}
+	days := dailyRanges(history.DownsampleWithRollups(records.observations, records.rollups, history.IntervalDaily), unit)
+
+	ceiling := &growing.Ceiling
+	if math.IsInf(growing.Ceiling, 1) {
+		ceiling = nil
+	} else {
+		*ceiling = units.Round(*ceiling, 2)
+	}
+	response := model.DegreeDaysResponse{
+		Coordinates: model.Coordinates{Lat: coords.lat, Lon: coords.lon},
+		From:        firstDay.Format(dateLayout),
+		To:          lastDay.Format(dateLayout),
+		Method:      string(method),
+		Unit:        unit.Symbol(),
+		Base:        units.Round(base, 2),
+		Growing:     model.GrowingBase{Crop: growing.Name, Base: units.Round(growing.Base, 2), Ceiling: ceiling},
+		Source:      records.source,
+		Days:        []model.DegreeDay{},
+		MissingDays: []string{},
+	}
+	var total model.DegreeTotals
+	for date := firstDay; !date.After(lastDay); date = date.AddDate(0, 0, 1) {
+		key := date.Format(dateLayout)
+		day, ok := days[key]
+		if !ok {
+			response.MissingDays = append(response.MissingDays, key)
+			continue
+		}
+		// Without the next day, the night after is assumed as cold as the one before
+		day.NextMin = day.Min
+		if next, ok := days[date.AddDate(0, 0, 1).Format(dateLayout)]; ok {
+			day.NextMin = next.Min
+		}
+
+		heating := degreeday.Heating(day, base, method)
+		cooling := degreeday.Cooling(day, base, method)
+		growth := degreeday.Growing(day, growing.Base, growing.Ceiling, method)
+		total.Heating += heating
+		total.Cooling += cooling
+		total.Growing += growth
+		response.Days = append(response.Days, model.DegreeDay{
+			Date:       key,
+			Min:        units.Round(day.Min, 2),
+			Max:        units.Round(day.Max, 2),
+			Heating:    units.Round(heating, 2),
+			Cooling:    units.Round(cooling, 2),
+			Growing:    units.Round(growth, 2),
+			Cumulative: roundTotals(total),
+		})
+	}
+	response.Total = roundTotals(total)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// dailyRanges returns the temperature range of each local day with enough observations, keyed by date and
+// converted from canonical units.
+func dailyRanges(buckets []history.Bucket, unit units.TemperatureUnit) map[string]degreeday.Day {
+	in, _ := units.ForSystem(units.CanonicalSystem)
+	days := make(map[string]degreeday.Day, len(buckets))
+	for _, bucket := range buckets {
+		if bucket.Count < minDayObservations {
+			continue
+		}
+		min, _ := units.ConvertTemperature(bucket.Temperature.Min, in.Temperature, unit)
+		max, _ := units.ConvertTemperature(bucket.Temperature.Max, in.Temperature, unit)
+		days[bucket.Start.Format(dateLayout)] = degreeday.Day{Min: min, Max: max}
+	}
+	return days
+}
+
+// roundTotals rounds degree-day totals for output.
+func roundTotals(total model.DegreeTotals) model.DegreeTotals {
+	return model.DegreeTotals{
+		Heating: units.Round(total.Heating, 2),
+		Cooling: units.Round(total.Cooling, 2),
+		Growing: units.Round(total.Growing, 2),
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+// upstreamDays serves hourly observations in London of 5 °C before noon and 25 °C after it, recording the
+// periods asked for.
+func upstreamDays(periods *[][2]time.Time) *MockHistoryAPI {
+	return &MockHistoryAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+			*periods = append(*periods, [2]time.Time{start, end})
+			var data model.HistoryData
+			for at := start.Truncate(time.Hour); at.Before(end); at = at.Add(time.Hour) {
+				temperature := 278.15
+				if at.Hour() >= 12 {
+					temperature = 298.15
+				}
+				data.List = append(data.List, model.WeatherData{Dt: at.Unix(), Main: model.MainInfo{Temp: temperature}})
+			}
+			data.Cnt = len(data.List)
+			return data, nil
+		},
+	}
+}
+
+func getDegreeDays(t *testing.T, store history.Store, api repo.HistoryAPI, query string) (*httptest.ResponseRecorder, model.DegreeDaysResponse) {
+	h := NewDegreeDaysHandler(store, api, &config.AppConfig{HistoryURL: "http://example.com/history", UnitOfMeasurement: "metric"})
+	h.now = func() time.Time { return historyStart.Add(10 * 24 * time.Hour) }
+
+	req, _ := http.NewRequest("GET", "/degree-days?lat=51.5&lon=0&"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetDegreeDaysByCoordinates(rr, req)
+
+	var response model.DegreeDaysResponse
+	if rr.Code == http.StatusOK {
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	}
+	return rr, response
+}
+
+func TestDegreeDaysHandler_Upstream(t *testing.T) {
+	var periods [][2]time.Time
+	rr, response := getDegreeDays(t, history.NewMemoryStore(0), upstreamDays(&periods), "from=2024-01-01&to=2024-01-03")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceUpstream, response.Source)
+	assert.Equal(t, "average", response.Method)
+	assert.Equal(t, "°C", response.Unit)
+	assert.Equal(t, 18.0, response.Base)
+	ceiling := 30.0
+	assert.Equal(t, model.GrowingBase{Crop: "corn", Base: 10, Ceiling: &ceiling}, response.Growing)
+	if assert.Len(t, periods, 1) {
+		assert.True(t, periods[0][0].Equal(historyStart.Add(-24*time.Hour)), "got %v", periods[0][0])
+		assert.True(t, periods[0][1].Equal(historyStart.Add(5*24*time.Hour)), "got %v", periods[0][1])
+	}
+
+	// Each day ranges from 5 to 25 °C: a mean of 15 °C is 3 heating degree-days, and with the minimum raised
+	// to the corn base of 10 °C, 7.5 growing ones
+	if assert.Len(t, response.Days, 3) {
+		assert.Equal(t, model.DegreeDay{
+			Date: "2024-01-01", Min: 5, Max: 25, Heating: 3, Growing: 7.5,
+			Cumulative: model.DegreeTotals{Heating: 3, Growing: 7.5},
+		}, response.Days[0])
+		assert.Equal(t, model.DegreeTotals{Heating: 9, Growing: 22.5}, response.Days[2].Cumulative)
+	}
+	assert.Equal(t, model.DegreeTotals{Heating: 9, Growing: 22.5}, response.Total)
+	assert.Empty(t, response.MissingDays)
+}
+
+func TestDegreeDaysHandler_Fahrenheit(t *testing.T) {
+	var periods [][2]time.Time
+	rr, response := getDegreeDays(t, history.NewMemoryStore(0), upstreamDays(&periods), "from=2024-01-01&to=2024-01-01&units=imperial")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "°F", response.Unit)
+	assert.Equal(t, 65.0, response.Base)
+	assert.Equal(t, 50.0, response.Growing.Base)
+	// From 41 to 77 °F, the mean of 59 °F is 6 heating degree-days; from 50 to 77 °F, 13.5 growing ones
+	if assert.Len(t, response.Days, 1) {
+		assert.Equal(t, 41.0, response.Days[0].Min)
+		assert.Equal(t, 77.0, response.Days[0].Max)
+		assert.Equal(t, 6.0, response.Days[0].Heating)
+		assert.Equal(t, 13.5, response.Days[0].Growing)
+	}
+}
+
+func TestDegreeDaysHandler_Thresholds(t *testing.T) {
+	var periods [][2]time.Time
+	api := upstreamDays(&periods)
+
+	_, response := getDegreeDays(t, history.NewMemoryStore(0), api, "from=2024-01-01&to=2024-01-01&base=10&gddBase=5&gddCeiling=20&method=single-sine")
+	assert.Equal(t, "single-sine", response.Method)
+	assert.Empty(t, response.Growing.Crop, "a crop does not apply when both thresholds are given")
+	if assert.Len(t, response.Days, 1) {
+		// The sine curve from 5 to 25 °C is symmetric around its mean, 5 degrees above the base
+		assert.InDelta(t, 5, response.Days[0].Cooling-response.Days[0].Heating, 0.01)
+		assert.Less(t, response.Days[0].Growing, 10.0, "the ceiling caps the growth above 20 °C")
+	}
+
+	_, response = getDegreeDays(t, history.NewMemoryStore(0), api, "from=2024-01-01&to=2024-01-01&crop=wheat&gddCeiling=none")
+	assert.Equal(t, model.GrowingBase{Crop: "wheat", Base: 0}, response.Growing)
+	if assert.Len(t, response.Days, 1) {
+		assert.Equal(t, 15.0, response.Days[0].Growing)
+	}
+}
+
+func TestDegreeDaysHandler_MissingDays(t *testing.T) {
+	// Recorded observations cover the first day in London, and only the morning of the second
+	store := history.NewMemoryStore(0)
+	for at := historyStart; at.Before(historyStart.Add(30 * time.Hour)); at = at.Add(time.Hour) {
+		store.Append(context.Background(), history.Observation{Time: at, Lat: 51.5, Lon: 0, Temperature: 273.15 + float64(at.Hour())})
+	}
+
+	rr, response := getDegreeDays(t, store, nil, "from=2024-01-01&to=2024-01-03&method=double-sine")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceRecorded, response.Source)
+	assert.Equal(t, []string{"2024-01-02", "2024-01-03"}, response.MissingDays)
+	// The double-sine method falls back to the day's own minimum without the next day's
+	if assert.Len(t, response.Days, 1) {
+		assert.Equal(t, model.DegreeDay{
+			Date: "2024-01-01", Min: 0, Max: 23, Heating: 7.51, Cooling: 1.01, Growing: 4.44,
+			Cumulative: model.DegreeTotals{Heating: 7.51, Cooling: 1.01, Growing: 4.44},
+		}, response.Days[0])
+	}
+}
+
+func TestDegreeDaysHandler_DefaultRange(t *testing.T) {
+	var periods [][2]time.Time
+	_, response := getDegreeDays(t, history.NewMemoryStore(0), upstreamDays(&periods), "")
+
+	// The 30 days up to yesterday
+	assert.Equal(t, "2023-12-12", response.From)
+	assert.Equal(t, "2024-01-10", response.To)
+	assert.Len(t, response.Days, 30)
+}
+
+func TestDegreeDaysHandler_UpstreamErrors(t *testing.T) {
+	failing := &MockHistoryAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+			return model.HistoryData{}, repo.ErrInvalidAPIKey
+		},
+	}
+
+	rr, _ := getDegreeDays(t, history.NewMemoryStore(0), failing, "from=2024-01-01&to=2024-01-02")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestDegreeDaysHandler_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		query, message string
+	}{
+		{"from=2024-01-01T00:00:00Z", "from must be a YYYY-MM-DD date"},
+		{"to=tomorrow", "to must be a YYYY-MM-DD date"},
+		{"from=2024-01-03&to=2024-01-01", "from must not be after to"},
+		{"from=2023-01-01&to=2024-01-02", "must not exceed 366 days"},
+		{"from=2024-02-01&to=2024-02-02", "from must be in the past"},
+		{"method=triangle", "unknown method"},
+		{"crop=kale", "unknown crop"},
+		{"base=warm", "base must be a temperature in °C"},
+		{"gddBase=40", "gddCeiling must be above gddBase"},
+		{"units=cubits", "Invalid units"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rr, _ := getDegreeDays(t, history.NewMemoryStore(0), nil, tt.query)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.message)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Limits of the hours query parameter. OpenWeatherMap forecasts 5 days ahead.
+const (
+	DefaultForecastHours = 24
+	MaxForecastHours     = 120
+)
+
+// forecastStep is the spacing of OpenWeatherMap's forecast, which its precipitation volumes cover.
+const forecastStep = 3 * time.Hour
+
+// ForecastHandler handles forecast HTTP requests.
+type ForecastHandler struct {
+	Repo       repo.ForecastAPI   // Interface for fetching forecasts
+	Config     *config.AppConfig  // Application configuration settings
+	Categories *category.Registry // Temperature category schemes selectable with ?scheme=
+}
+
+// NewForecastHandler creates a ForecastHandler with the builtin category schemes; replace Categories to add
+// configured ones.
+func NewForecastHandler(repo repo.ForecastAPI, cfg *config.AppConfig) *ForecastHandler {
+	return &ForecastHandler{Repo: repo, Config: cfg, Categories: builtinCategories}
+}
+
+// GetForecastByCoordinates handles HTTP requests for the hourly and daily forecast by coordinates.
+func (h *ForecastHandler) GetForecastByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	hours := DefaultForecastHours
+	if text := query.Get("hours"); text != "" {
+		hours, err = strconv.Atoi(text)
+		if err != nil || hours < 1 || hours > MaxForecastHours {
+			http.Error(w, "Invalid query parameter: hours must be a whole number between 1 and 120", http.StatusBadRequest)
+			return
+		}
+	}
+
+	scheme, err := h.Categories.Lookup(query.Get("scheme"))
+	if err != nil {
+		http.Error(w, "Invalid scheme: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lang, err := requestLanguage(r)
+	if err != nil {
+		http.Error(w, "Invalid lang: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := i18n.NewContext(r.Context(), lang)
+
+	forecast, err := h.Repo.FetchForecast(ctx, coords.latText, coords.lonText, h.Config.ForecastURL, units.CanonicalSystem)
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch forecast", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		handleWeatherDataError(err, w)
+		return
+	}
+
+	response := MapForecastToResponse(forecast, units.CanonicalSystem, output, scheme, hours)
+	for i := range response.Hourly {
+		response.Hourly[i].WeatherCondition = i18n.Condition(lang, response.Hourly[i].WeatherCondition)
+		response.Hourly[i].TempCategory = i18n.Category(lang, response.Hourly[i].TempCategory)
+	}
+	for i := range response.Daily {
+		response.Daily[i].WeatherCondition = i18n.Condition(lang, response.Daily[i].WeatherCondition)
+		response.Daily[i].TempCategory = i18n.Category(lang, response.Daily[i].TempCategory)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(lang))
+	json.NewEncoder(w).Encode(response)
+}
+
+// MapForecastToResponse maps a forecast fetched in the unitOfMeasurement system to at most hours hourly
+// slots and the daily summaries of the whole forecast, converted to the output units.
+func MapForecastToResponse(data model.ForecastData, unitOfMeasurement string, output units.Set, scheme category.Scheme, hours int) model.ForecastResponse {
+	upstream, _ := units.Upstream(unitOfMeasurement)
+	c := converter{from: upstream, to: output}
+
+	return model.ForecastResponse{
+		Location:       data.City.Name,
+		Country:        data.City.Country,
+		Coordinates:    data.City.Coord,
+		TimezoneOffset: data.City.Timezone,
+		Units: model.UnitLabels{
+			Temperature:   output.Temperature.Symbol(),
+			Speed:         output.Speed.Symbol(),
+			Pressure:      output.Pressure.Symbol(),
+			Distance:      output.Distance.Symbol(),
+			Precipitation: output.Precipitation.Symbol(),
+		},
+		Hourly: interpolateHourly(data.List, c, scheme, hours),
+		Daily:  aggregateDaily(data.List, data.City.Timezone, c, scheme),
+	}
+}
+
+// interpolateHourly interpolates the 3-hour steps linearly to hourly slots, starting at the first step.
+// Precipitation, its probability and the condition are taken from the step whose period contains the hour.
+func interpolateHourly(steps []model.ForecastItem, c converter, scheme category.Scheme, hours int) []model.ForecastHour {
+	if len(steps) == 0 {
+		return []model.ForecastHour{}
+	}
+
+	start := time.Unix(steps[0].Dt, 0).UTC()
+	slots := make([]model.ForecastHour, 0, hours)
+	next := 0
+	for i := 0; i < hours; i++ {
+		t := start.Add(time.Duration(i) * time.Hour)
+		for next < len(steps) && steps[next].Dt < t.Unix() {
+			next++
+		}
+		if next == len(steps) {
+			break
+		}
+
+		step, previous := steps[next], steps[next]
+		if next > 0 {
+			previous = steps[next-1]
+		}
+		fraction := 1.0
+		if step.Dt > previous.Dt {
+			fraction = float64(t.Unix()-previous.Dt) / float64(step.Dt-previous.Dt)
+		}
+		lerp := func(from, to float64) float64 { return from + (to-from)*fraction }
+
+		temperature := lerp(previous.Main.Temp, step.Main.Temp)
+		feelsLike := lerp(previous.Main.FeelsLike, step.Main.FeelsLike)
+		slots = append(slots, model.ForecastHour{
+			Time:             t,
+			Temperature:      c.temperature(temperature),
+			FeelsLike:        c.temperature(feelsLike),
+			Humidity:         units.Round(lerp(previous.Main.Humidity, step.Main.Humidity), 2),
+			Pressure:         c.pressure(lerp(previous.Main.Pressure, step.Main.Pressure)),
+			WindSpeed:        c.speed(lerp(previous.Wind.Speed, step.Wind.Speed)),
+			WindDirection:    interpolateDirection(previous.Wind.Deg, step.Wind.Deg, fraction),
+			Cloudiness:       units.Round(lerp(previous.Clouds.All, step.Clouds.All), 2),
+			Precipitation:    c.precipitation(precipitationVolume(step) / float64(forecastStep/time.Hour)),
+			Pop:              step.Pop,
+			WeatherCondition: mainCondition(step.Weather),
+			TempCategory:     categorizeTemperatures(temperature, feelsLike, c.from.Temperature, scheme),
+			Interpolated:     t.Unix() != step.Dt,
+		})
+	}
+	return slots
+}
+
+// interpolateDirection interpolates between two bearings along the shorter arc.
+func interpolateDirection(from, to, fraction float64) float64 {
+	delta := math.Mod(to-from+540, 360) - 180
+	return units.Round(math.Mod(from+delta*fraction+360, 360), 2)
+}
+
+// aggregateDaily summarizes the 3-hour steps by calendar day in the location's timezone.
+func aggregateDaily(steps []model.ForecastItem, timezoneOffset int, c converter, scheme category.Scheme) []model.ForecastDay {
+	zone := time.FixedZone("", timezoneOffset)
+
+	type accumulator struct {
+		day                model.ForecastDay
+		maxTemp, maxFeels  float64
+		minTemp            float64
+		counts, severities map[string]int
+		order              []string
+	}
+
+	days := []*accumulator{}
+	for _, step := range steps {
+		date := time.Unix(step.Dt, 0).In(zone).Format(dateLayout)
+		if len(days) == 0 || days[len(days)-1].day.Date != date {
+			days = append(days, &accumulator{
+				day:        model.ForecastDay{Date: date},
+				maxTemp:    math.Inf(-1),
+				maxFeels:   math.Inf(-1),
+				minTemp:    math.Inf(1),
+				counts:     map[string]int{},
+				severities: map[string]int{},
+			})
+		}
+
+		acc := days[len(days)-1]
+		acc.day.Steps++
+		acc.day.Precipitation += precipitationVolume(step)
+		acc.day.MaxPop = math.Max(acc.day.MaxPop, step.Pop)
+		acc.minTemp = math.Min(acc.minTemp, math.Min(step.Main.TempMin, step.Main.Temp))
+		acc.maxTemp = math.Max(acc.maxTemp, math.Max(step.Main.TempMax, step.Main.Temp))
+		acc.maxFeels = math.Max(acc.maxFeels, step.Main.FeelsLike)
+
+		condition := mainCondition(step.Weather)
+		if _, seen := acc.counts[condition]; !seen {
+			acc.order = append(acc.order, condition)
+		}
+		acc.counts[condition]++
+		if len(step.Weather) > 0 {
+			if known, ok := taxonomy.Lookup(step.Weather[0].ID); ok && known.Severity > acc.severities[condition] {
+				acc.severities[condition] = known.Severity
+			}
+		}
+	}
+
+	daily := make([]model.ForecastDay, 0, len(days))
+	for _, acc := range days {
+		// The most frequent condition wins; ties go to the more severe, then the earlier one
+		dominant := acc.order[0]
+		for _, condition := range acc.order[1:] {
+			if acc.counts[condition] > acc.counts[dominant] ||
+				(acc.counts[condition] == acc.counts[dominant] && acc.severities[condition] > acc.severities[dominant]) {
+				dominant = condition
+			}
+		}
+
+		day := acc.day
+		day.WeatherCondition = dominant
+		day.TempMin = c.temperature(acc.minTemp)
+		day.TempMax = c.temperature(acc.maxTemp)
+		day.TempCategory = categorizeTemperatures(acc.maxTemp, acc.maxFeels, c.from.Temperature, scheme)
+		day.Precipitation = c.precipitation(day.Precipitation)
+		daily = append(daily, day)
+	}
+	return daily
+}
+
+// precipitationVolume returns the rain and snow of a forecast step's 3-hour period.
+func precipitationVolume(step model.ForecastItem) float64 {
+	var volume float64
+	if step.Rain != nil && step.Rain.ThreeHours != nil {
+		volume += *step.Rain.ThreeHours
+	}
+	if step.Snow != nil && step.Snow.ThreeHours != nil {
+		volume += *step.Snow.ThreeHours
+	}
+	return volume
+}
+
+// mainCondition returns the primary condition group, or "Unknown" when none is reported.
+func mainCondition(weather []model.WeatherCondition) string {
+	if len(weather) == 0 {
+		return "Unknown"
+	}
+	return weather[0].Main
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockForecastAPI implementation for testing
+type MockForecastAPI struct {
+	FetchFunc func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error)
+}
+
+func (m *MockForecastAPI) FetchForecast(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+	return m.FetchFunc(ctx, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement)
+}
+
+var forecastStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) // 21:00 in Tokyo
+
+// forecastItem builds a canonical forecast step at the given hours after forecastStart.
+func forecastItem(hours int, celsius float64, id int, main string, rain3h, snow3h, pop, windSpeed, windDeg float64) model.ForecastItem {
+	kelvin := celsius + 273.15
+	item := model.ForecastItem{
+		Dt:      forecastStart.Add(time.Duration(hours) * time.Hour).Unix(),
+		Main:    model.MainInfo{Temp: kelvin, FeelsLike: kelvin - 2, TempMin: kelvin, TempMax: kelvin, Pressure: 1010, Humidity: 70},
+		Weather: []model.WeatherCondition{{ID: id, Main: main}},
+		Wind:    model.Wind{Speed: windSpeed, Deg: windDeg},
+		Clouds:  model.Clouds{All: 50},
+		Pop:     pop,
+	}
+	if rain3h > 0 {
+		item.Rain = &model.Precipitation{ThreeHours: &rain3h}
+	}
+	if snow3h > 0 {
+		item.Snow = &model.Precipitation{ThreeHours: &snow3h}
+	}
+	return item
+}
+
+func canonicalForecastData() model.ForecastData {
+	return model.ForecastData{
+		City: model.ForecastCity{Name: "Shibuya", Country: "JP", Coord: model.Coordinates{Lat: 35, Lon: 139}, Timezone: 32400},
+		List: []model.ForecastItem{
+			forecastItem(0, 7, 800, "Clear", 0, 0, 0, 4, 350),
+			forecastItem(3, 10, 500, "Rain", 1.5, 0, 0.6, 7, 10),
+			forecastItem(6, 4, 600, "Snow", 0, 0.9, 0.8, 1, 90),
+			forecastItem(9, 4, 501, "Rain", 0.3, 0, 0.3, 1, 90),
+		},
+	}
+}
+
+func TestForecastHandler_GetForecastByCoordinates_Success(t *testing.T) {
+	mockAPI := &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			assert.Equal(t, "35", lat)
+			assert.Equal(t, "139", lon)
+			assert.Equal(t, "http://example.com/forecast", openWeatherMapForecastURL)
+			assert.Equal(t, units.CanonicalSystem, unitsOfMeasurement)
+			return canonicalForecastData(), nil
+		},
+	}
+
+	cfg := &config.AppConfig{ForecastURL: "http://example.com/forecast", UnitOfMeasurement: "metric"}
+	h := NewForecastHandler(mockAPI, cfg)
+
+	req, _ := http.NewRequest("GET", "/forecast?lat=35&lon=139&hours=5", nil)
+	rr := httptest.NewRecorder()
+	h.GetForecastByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response model.ForecastResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "Shibuya", response.Location)
+	assert.Equal(t, "°C", response.Units.Temperature)
+
+	if assert.Len(t, response.Hourly, 5) {
+		exact, between := response.Hourly[0], response.Hourly[1]
+		assert.Equal(t, forecastStart, exact.Time)
+		assert.False(t, exact.Interpolated)
+		assert.Equal(t, 7.0, exact.Temperature)
+		assert.Equal(t, "Clear", exact.WeatherCondition)
+
+		// A third of the way from 7°C to 10°C, with the rain of the step ending at 15:00 spread over its hours
+		assert.Equal(t, forecastStart.Add(time.Hour), between.Time)
+		assert.True(t, between.Interpolated)
+		assert.Equal(t, 8.0, between.Temperature)
+		assert.Equal(t, 6.0, between.FeelsLike)
+		assert.Equal(t, 5.0, between.WindSpeed)
+		assert.Equal(t, 356.67, between.WindDirection) // Through north, not back around through south
+		assert.Equal(t, 0.5, between.Precipitation)
+		assert.Equal(t, 0.6, between.Pop)
+		assert.Equal(t, "Rain", between.WeatherCondition)
+		assert.Equal(t, CategorizeTemperature(46.4), between.TempCategory)
+
+		assert.Equal(t, 8.0, response.Hourly[4].Temperature)
+		assert.Equal(t, 0.3, response.Hourly[4].Precipitation)
+	}
+
+	expectedDaily := []model.ForecastDay{
+		{Date: "2024-01-01", TempMin: 7, TempMax: 7, WeatherCondition: "Clear", TempCategory: CategorizeTemperature(44.6), Steps: 1},
+		{Date: "2024-01-02", TempMin: 4, TempMax: 10, WeatherCondition: "Rain", TempCategory: CategorizeTemperature(50), Precipitation: 2.7, MaxPop: 0.8, Steps: 3},
+	}
+	assert.Equal(t, expectedDaily, response.Daily)
+}
+
+func TestForecastHandler_GetForecastByCoordinates_HoursBeyondForecast(t *testing.T) {
+	mockAPI := &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			return canonicalForecastData(), nil
+		},
+	}
+	h := NewForecastHandler(mockAPI, &config.AppConfig{UnitOfMeasurement: "metric"})
+
+	req, _ := http.NewRequest("GET", "/forecast?lat=35&lon=139&hours=120&lang=de", nil)
+	rr := httptest.NewRecorder()
+	h.GetForecastByCoordinates(rr, req)
+
+	var response model.ForecastResponse
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Hourly, 10) // 12:00 through the last step at 21:00
+	assert.Equal(t, "de", rr.Header().Get("Content-Language"))
+	assert.Equal(t, "Regen", response.Daily[1].WeatherCondition)
+}
+
+func TestForecastHandler_GetForecastByCoordinates_InvalidParams(t *testing.T) {
+	h := NewForecastHandler(&MockForecastAPI{}, &config.AppConfig{UnitOfMeasurement: "metric"})
+
+	testCases := []struct {
+		name     string
+		query    string
+		wantBody string
+	}{
+		{"Missing Coordinates", "", "Missing required query parameters: lat and/or lon\n"},
+		{"Zero Hours", "?lat=35&lon=139&hours=0", "Invalid query parameter: hours must be a whole number between 1 and 120\n"},
+		{"Too Many Hours", "?lat=35&lon=139&hours=121", "Invalid query parameter: hours must be a whole number between 1 and 120\n"},
+		{"Invalid Scheme", "?lat=35&lon=139&scheme=nope", "Invalid scheme: unknown category scheme \"nope\"\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/forecast"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			h.GetForecastByCoordinates(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, tc.wantBody, rr.Body.String())
+		})
+	}
+}
+
+func TestForecastHandler_GetForecastByCoordinates_FetchError(t *testing.T) {
+	mockAPI := &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			return model.ForecastData{}, repo.ErrServiceUnavailable
+		},
+	}
+	h := NewForecastHandler(mockAPI, &config.AppConfig{UnitOfMeasurement: "metric"})
+
+	req, _ := http.NewRequest("GET", "/forecast?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	h.GetForecastByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestAggregateDaily_SevereConditionWinsTie(t *testing.T) {
+	steps := []model.ForecastItem{
+		forecastItem(3, 0, 800, "Clear", 0, 0, 0, 1, 0),
+		forecastItem(6, -1, 601, "Snow", 0, 2, 0.9, 1, 0),
+	}
+	metric, _ := units.ForSystem(units.Metric)
+	canonical, _ := units.Upstream(units.CanonicalSystem)
+	scheme, _ := builtinCategories.Lookup("")
+
+	daily := aggregateDaily(steps, 0, converter{from: canonical, to: metric}, scheme)
+	if assert.Len(t, daily, 1) {
+		assert.Equal(t, "Snow", daily[0].WeatherCondition)
+		assert.Equal(t, -1.0, daily[0].TempMin)
+		assert.Equal(t, 2.0, daily[0].Precipitation)
+	}
+}
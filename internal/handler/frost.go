@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Nights are assessed from 18:00 to 09:00 local time, which covers the coldest hours around dawn.
+const (
+	nightStartHour = 18
+	nightEndHour   = 9
+)
+
+// Radiational cooling heuristics. On clear, calm nights leaves and the ground radiate heat to the sky and
+// cool below the air temperature measured at 2 m; clouds send the heat back and wind mixes warmer air down.
+const (
+	maxRadiativeCooling = 3.0 // °C below the air on a cloudless, windless night
+	calmWind            = 1.0 // m/s; cooling is full up to this wind speed
+	mixedWind           = 5.0 // m/s; from this wind speed the air is mixed and cooling stops
+	freezing            = 0.0 // °C
+	hardFreeze          = -2.0
+)
+
+// builtinFrostSchemes holds the builtin frost risk schemes, which always validate.
+var builtinFrostSchemes, _ = category.NewFrostRegistry("")
+
+// FrostRiskHandler assesses the frost risk of the upcoming nights from the forecast.
+type FrostRiskHandler struct {
+	Forecast repo.ForecastAPI   // Interface for fetching the 3-hour forecast
+	OneCall  repo.OneCallAPI    // Source of the hourly forecast with dew points; nil to use the forecast only
+	Schemes  *category.Registry // Frost risk schemes selectable with ?scheme=
+	Config   *config.AppConfig  // Application configuration settings
+	now      func() time.Time   // Replaced in tests
+}
+
+// NewFrostRiskHandler creates a FrostRiskHandler with the builtin frost risk schemes; replace Schemes to add
+// configured ones. oneCall may be nil, in which case only the forecast is used.
+func NewFrostRiskHandler(forecast repo.ForecastAPI, oneCall repo.OneCallAPI, cfg *config.AppConfig) *FrostRiskHandler {
+	return &FrostRiskHandler{Forecast: forecast, OneCall: oneCall, Schemes: builtinFrostSchemes, Config: cfg, now: time.Now}
+}
+
+// GetFrostRiskByCoordinates handles HTTP requests for the frost risk of the upcoming nights by coordinates.
+func (h *FrostRiskHandler) GetFrostRiskByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	scheme, err := h.Schemes.Lookup(query.Get("scheme"))
+	if err != nil {
+		http.Error(w, "Invalid scheme: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lang, err := requestLanguage(r)
+	if err != nil {
+		http.Error(w, "Invalid lang: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := i18n.NewContext(r.Context(), lang)
+
+	forecast, err := h.Forecast.FetchForecast(ctx, coords.latText, coords.lonText, h.Config.ForecastURL, units.CanonicalSystem)
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch forecast", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		handleWeatherDataError(err, w)
+		return
+	}
+
+	source := model.FrostSourceForecast
+	points := forecastFrostPoints(forecast.List)
+	if h.OneCall != nil {
+		if hourly, err := h.fetchHourly(ctx, coords); err != nil {
+			logger.WarnContext(r.Context(), "failed to fetch the hourly forecast, using the 3-hour forecast only", "error", err)
+		} else if len(hourly) > 0 {
+			source = model.FrostSourceOneCall
+			points = withHourly(points, hourly)
+		}
+	}
+
+	in, _ := units.ForSystem(units.CanonicalSystem)
+	c := converter{from: in, to: output}
+	response := model.FrostRiskResponse{
+		Coordinates:    model.Coordinates{Lat: coords.lat, Lon: coords.lon},
+		TimezoneOffset: forecast.City.Timezone,
+		Source:         source,
+		Scheme:         scheme.Name,
+		Units: model.UnitLabels{
+			Temperature:   output.Temperature.Symbol(),
+			Speed:         output.Speed.Symbol(),
+			Pressure:      output.Pressure.Symbol(),
+			Distance:      output.Distance.Symbol(),
+			Precipitation: output.Precipitation.Symbol(),
+		},
+		Nights: []model.FrostNight{},
+	}
+	facts := i18n.FrostFacts{Freezing: formatQuantity(*c.celsius(freezing), output.Temperature.Symbol())}
+	coldest := math.Inf(1)
+	for i, night := range assessNights(points, h.now().UTC(), time.FixedZone("", forecast.City.Timezone)) {
+		level, _ := scheme.Categorize(units.Temperature{Value: night.min.expected, Unit: units.Celsius})
+		response.Nights = append(response.Nights, mapFrostNight(night, level, c))
+
+		if scheme.AtRisk(level) && night.min.expected < coldest {
+			coldest = night.min.expected
+			facts.AtRisk = true
+			facts.Night = i
+			facts.Level = level
+			facts.Min = formatQuantity(*c.celsius(night.min.expected), output.Temperature.Symbol())
+			facts.HoursBelowFreezing = night.belowFreezing
+		}
+	}
+	facts.Nights = len(response.Nights)
+	response.Summary = i18n.Frost(lang, facts)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(lang))
+	json.NewEncoder(w).Encode(response)
+}
+
+// fetchHourly fetches the One Call hourly forecast in canonical units.
+func (h *FrostRiskHandler) fetchHourly(ctx context.Context, coords coordinates) ([]model.OneCallHourly, error) {
+	data, err := h.OneCall.FetchOneCall(ctx, coords.latText, coords.lonText, h.Config.OneCallURL, units.CanonicalSystem,
+		repo.ExcludeAllBut(repo.BlockHourly))
+	if err != nil {
+		return nil, err
+	}
+	return data.Hourly, nil
+}
+
+// frostPoint is the forecast at one time, in °C, percent and m/s. expected is the temperature expected at
+// plant level.
+type frostPoint struct {
+	at                                  time.Time
+	temperature, dewPoint, clouds, wind float64
+	expected                            float64
+}
+
+// forecastFrostPoints turns 3-hour forecast steps, fetched in canonical units, into points. The forecast has
+// no dew point, so it is derived from the humidity.
+func forecastFrostPoints(steps []model.ForecastItem) []frostPoint {
+	points := make([]frostPoint, 0, len(steps))
+	for _, step := range steps {
+		temperature := step.Main.Temp - 273.15
+		points = append(points, frostPoint{
+			at:          time.Unix(step.Dt, 0).UTC(),
+			temperature: temperature,
+			dewPoint:    util.DewPoint(temperature, math.Max(step.Main.Humidity, 1)),
+			clouds:      step.Clouds.All,
+			wind:        step.Wind.Speed,
+		})
+	}
+	return points
+}
+
+// withHourly replaces the forecast points within the hours of the One Call hourly forecast, fetched in
+// canonical units, by its hourly points.
+func withHourly(points []frostPoint, hourly []model.OneCallHourly) []frostPoint {
+	merged := make([]frostPoint, 0, len(hourly)+len(points))
+	for _, hour := range hourly {
+		merged = append(merged, frostPoint{
+			at:          time.Unix(hour.Dt, 0).UTC(),
+			temperature: hour.Temp - 273.15,
+			dewPoint:    hour.DewPoint - 273.15,
+			clouds:      hour.Clouds,
+			wind:        hour.WindSpeed,
+		})
+	}
+	last := merged[len(merged)-1].at
+	for _, point := range points {
+		if point.at.After(last) {
+			merged = append(merged, point)
+		}
+	}
+	return merged
+}
+
+// expectedTemperature applies radiational cooling to the air temperature of a point. Plants rarely cool
+// below the dew point, as the dew or frost forming on them releases heat.
+func expectedTemperature(p frostPoint) float64 {
+	clear := 1 - math.Min(math.Max(p.clouds, 0), 100)/100
+	calm := math.Min(math.Max((mixedWind-p.wind)/(mixedWind-calmWind), 0), 1)
+	cooled := p.temperature - maxRadiativeCooling*clear*calm
+	return math.Max(cooled, math.Min(p.temperature, p.dewPoint))
+}
+
+// frostNight is the assessment of one night in °C.
+type frostNight struct {
+	start, end                     time.Time
+	min                            frostPoint // Hour of the lowest expected temperature
+	belowFreezing, belowHardFreeze int
+}
+
+// assessNights assesses every night, from now on, that the points reach the end of, with the forecast
+// interpolated hourly.
+func assessNights(points []frostPoint, now time.Time, zone *time.Location) []frostNight {
+	if len(points) == 0 {
+		return nil
+	}
+	last := points[len(points)-1].at
+
+	local := now.In(zone)
+	evening := time.Date(local.Year(), local.Month(), local.Day(), nightStartHour, 0, 0, 0, zone)
+	if local.Hour() < nightEndHour {
+		evening = evening.AddDate(0, 0, -1) // The night that started yesterday evening is still on
+	}
+
+	var nights []frostNight
+	for ; ; evening = evening.AddDate(0, 0, 1) {
+		night := frostNight{start: evening, end: evening.Add((24 - nightStartHour + nightEndHour) * time.Hour)}
+		if night.end.After(last) {
+			return nights
+		}
+		night.min.expected = math.Inf(1)
+		for at := maxTime(night.start, now.Truncate(time.Hour)); at.Before(night.end); at = at.Add(time.Hour) {
+			point := interpolateFrostPoint(points, at)
+			point.expected = expectedTemperature(point)
+			if point.expected < night.min.expected {
+				night.min = point
+			}
+			if point.expected < freezing {
+				night.belowFreezing++
+			}
+			if point.expected < hardFreeze {
+				night.belowHardFreeze++
+			}
+		}
+		nights = append(nights, night)
+	}
+}
+
+// interpolateFrostPoint interpolates the points linearly at a time, holding the first point before it.
+func interpolateFrostPoint(points []frostPoint, at time.Time) frostPoint {
+	i := 0
+	for i < len(points)-1 && !points[i+1].at.After(at) {
+		i++
+	}
+	from := points[i]
+	if i == len(points)-1 || at.Before(from.at) {
+		from.at = at
+		return from
+	}
+
+	to := points[i+1]
+	fraction := float64(at.Sub(from.at)) / float64(to.at.Sub(from.at))
+	lerp := func(a, b float64) float64 { return a + (b-a)*fraction }
+	return frostPoint{
+		at:          at,
+		temperature: lerp(from.temperature, to.temperature),
+		dewPoint:    lerp(from.dewPoint, to.dewPoint),
+		clouds:      lerp(from.clouds, to.clouds),
+		wind:        lerp(from.wind, to.wind),
+	}
+}
+
+// mapFrostNight converts a night assessed in °C and m/s to the output units.
+func mapFrostNight(night frostNight, level string, c converter) model.FrostNight {
+	expected, air := *c.celsius(night.min.expected), *c.celsius(night.min.temperature)
+	return model.FrostNight{
+		Date:                 night.start.Format(dateLayout),
+		Start:                night.start.UTC(),
+		End:                  night.end.UTC(),
+		MinTemperature:       expected,
+		MinTime:              night.min.at,
+		AirTemperature:       air,
+		RadiativeCooling:     units.Round(air-expected, 2),
+		DewPoint:             *c.celsius(night.min.dewPoint),
+		Cloudiness:           units.Round(night.min.clouds, 2),
+		WindSpeed:            c.speed(night.min.wind),
+		HoursBelowFreezing:   night.belowFreezing,
+		HoursBelowHardFreeze: night.belowHardFreeze,
+		RiskLevel:            level,
+	}
+}
+
+// maxTime returns the later of two times.
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
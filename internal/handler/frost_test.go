@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+// frostConditions are the temperature in °C, humidity, cloudiness and wind speed of a forecast step.
+type frostConditions struct {
+	celsius, humidity, clouds, wind float64
+}
+
+// frostForecast covers three nights in London from forecastStart in 3-hour steps: an overcast night at
+// 5 °C, a clear and calm one at 0.5 °C, and a clear but windy one at 1 °C. Days are at 10 °C.
+func frostForecast() model.ForecastData {
+	conditions := func(at time.Time) frostConditions {
+		nights := []frostConditions{{5, 90, 100, 2}, {0.5, 50, 0, 0.5}, {1, 80, 0, 6}}
+		for i, night := range nights {
+			start := forecastStart.Add(time.Duration(6+24*i) * time.Hour) // 18:00
+			if !at.Before(start) && at.Before(start.Add(15*time.Hour)) {
+				return night
+			}
+		}
+		return frostConditions{10, 70, 50, 3}
+	}
+
+	data := model.ForecastData{City: model.ForecastCity{Name: "London", Country: "GB", Coord: model.Coordinates{Lat: 51.5, Lon: 0}}}
+	for hours := 0; hours <= 72; hours += 3 {
+		at := forecastStart.Add(time.Duration(hours) * time.Hour)
+		c := conditions(at)
+		data.List = append(data.List, model.ForecastItem{
+			Dt:     at.Unix(),
+			Main:   model.MainInfo{Temp: c.celsius + 273.15, Humidity: c.humidity},
+			Clouds: model.Clouds{All: c.clouds},
+			Wind:   model.Wind{Speed: c.wind},
+		})
+	}
+	return data
+}
+
+func newTestFrostRiskHandler(data model.ForecastData, oneCall repo.OneCallAPI) *FrostRiskHandler {
+	forecastAPI := &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			return data, nil
+		},
+	}
+	h := NewFrostRiskHandler(forecastAPI, oneCall, &config.AppConfig{UnitOfMeasurement: "metric"})
+	h.now = func() time.Time { return forecastStart }
+	return h
+}
+
+func getFrostRisk(t *testing.T, h *FrostRiskHandler, query string) (*httptest.ResponseRecorder, model.FrostRiskResponse) {
+	req, _ := http.NewRequest("GET", "/frost-risk?lat=51.5&lon=0&"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetFrostRiskByCoordinates(rr, req)
+
+	var response model.FrostRiskResponse
+	if rr.Code == http.StatusOK {
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	}
+	return rr, response
+}
+
+func TestFrostRiskHandler_Forecast(t *testing.T) {
+	rr, response := getFrostRisk(t, newTestFrostRiskHandler(frostForecast(), nil), "")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.FrostSourceForecast, response.Source)
+	assert.Equal(t, "frost", response.Scheme)
+	assert.Equal(t, "°C", response.Units.Temperature)
+	if !assert.Len(t, response.Nights, 3, "the forecast reaches the end of three nights") {
+		return
+	}
+
+	overcast := response.Nights[0]
+	assert.Equal(t, "2024-01-01", overcast.Date)
+	assert.True(t, overcast.Start.Equal(forecastStart.Add(6*time.Hour)), "got %v", overcast.Start)
+	assert.True(t, overcast.End.Equal(forecastStart.Add(21*time.Hour)), "got %v", overcast.End)
+	assert.Equal(t, 5.0, overcast.MinTemperature, "clouds stop radiational cooling")
+	assert.Equal(t, 0.0, overcast.RadiativeCooling)
+	assert.Equal(t, "None", overcast.RiskLevel)
+
+	// Clear and calm, plants cool 3 °C below the air: from 18:00 to 06:00, before the morning warms up
+	clear := response.Nights[1]
+	assert.Equal(t, -2.5, clear.MinTemperature)
+	assert.Equal(t, 0.5, clear.AirTemperature)
+	assert.Equal(t, 3.0, clear.RadiativeCooling)
+	assert.True(t, clear.MinTime.Equal(forecastStart.Add(30*time.Hour)), "got %v", clear.MinTime)
+	assert.Equal(t, 13, clear.HoursBelowFreezing)
+	assert.Equal(t, 13, clear.HoursBelowHardFreeze)
+	assert.Equal(t, "Severe", clear.RiskLevel)
+
+	windy := response.Nights[2]
+	assert.Equal(t, 1.0, windy.MinTemperature, "wind mixes the air and stops radiational cooling")
+	assert.Equal(t, 6.0, windy.WindSpeed)
+	assert.Equal(t, 0, windy.HoursBelowFreezing)
+	assert.Equal(t, "Medium", windy.RiskLevel)
+
+	assert.Equal(t, "Frost risk tomorrow night: Severe, low of -3°C, 13 h below 0°C", response.Summary)
+}
+
+func TestFrostRiskHandler_NoRisk(t *testing.T) {
+	data := frostForecast()
+	for i := range data.List {
+		data.List[i].Main.Temp = 283.15
+	}
+
+	rr, response := getFrostRisk(t, newTestFrostRiskHandler(data, nil), "lang=de")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "de", rr.Header().Get("Content-Language"))
+	assert.Equal(t, "Kein Frostrisiko in den nächsten 3 Nächten", response.Summary)
+}
+
+func TestFrostRiskHandler_OneCall(t *testing.T) {
+	// The hourly forecast for the next 48 hours is clear and calm at 1 °C, with the dew point at 0.5 °C
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			assert.NotContains(t, exclude, repo.BlockHourly)
+			var data model.OneCallData
+			for hour := 0; hour < 48; hour++ {
+				data.Hourly = append(data.Hourly, model.OneCallHourly{
+					Dt: forecastStart.Add(time.Duration(hour) * time.Hour).Unix(), Temp: 274.15, DewPoint: 273.65,
+				})
+			}
+			return data, nil
+		},
+	}
+
+	_, response := getFrostRisk(t, newTestFrostRiskHandler(frostForecast(), oneCall), "")
+	assert.Equal(t, model.FrostSourceOneCall, response.Source)
+	if assert.Len(t, response.Nights, 3) {
+		// Frost forming at the dew point stops plants cooling further
+		assert.Equal(t, 0.5, response.Nights[1].MinTemperature)
+		assert.Equal(t, 0.5, response.Nights[1].DewPoint)
+		assert.Equal(t, 0, response.Nights[1].HoursBelowFreezing)
+		assert.Equal(t, "Medium", response.Nights[1].RiskLevel)
+		// Beyond the hourly forecast, the 3-hour forecast is used
+		assert.Equal(t, 1.0, response.Nights[2].MinTemperature)
+	}
+
+	oneCall.FetchOneCallFunc = func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+		return model.OneCallData{}, repo.ErrInvalidAPIKey
+	}
+	rr, response := getFrostRisk(t, newTestFrostRiskHandler(frostForecast(), oneCall), "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.FrostSourceForecast, response.Source)
+}
+
+func TestFrostRiskHandler_ConfiguredScheme(t *testing.T) {
+	upTo := func(v float64) *float64 { return &v }
+	schemes, err := category.NewFrostRegistry("orchard", category.Scheme{
+		Name: "orchard", Unit: "F", Basis: category.BasisAir,
+		Bands:  []category.Band{{Name: "Damaging", UpTo: upTo(28)}, {Name: "Watch", UpTo: upTo(34)}, {Name: "Safe", UpTo: upTo(40)}, {Name: "Warm"}},
+		NoRisk: "Safe",
+	})
+	assert.NoError(t, err)
+	h := newTestFrostRiskHandler(frostForecast(), nil)
+	h.Schemes = schemes
+
+	_, response := getFrostRisk(t, h, "units=imperial")
+	assert.Equal(t, "orchard", response.Scheme)
+	if assert.Len(t, response.Nights, 3) {
+		assert.Equal(t, 27.5, response.Nights[1].MinTemperature)
+		assert.Equal(t, 5.4, response.Nights[1].RadiativeCooling)
+		assert.Equal(t, "Damaging", response.Nights[1].RiskLevel)
+		assert.Equal(t, "Watch", response.Nights[2].RiskLevel)
+	}
+	assert.Equal(t, "Frost risk tomorrow night: Damaging, low of 28°F, 13 h below 32°F", response.Summary)
+
+	// The request can still select another scheme
+	_, response = getFrostRisk(t, h, "scheme=frost")
+	assert.Equal(t, "frost", response.Scheme)
+}
+
+func TestFrostRiskHandler_Errors(t *testing.T) {
+	h := newTestFrostRiskHandler(frostForecast(), nil)
+	for query, message := range map[string]string{
+		"scheme=arctic": `unknown frost risk scheme "arctic"`,
+		"scheme=nordic": `unknown frost risk scheme "nordic"`,
+		"units=cubits":  "Invalid units",
+		"lang=xx":       "Invalid lang",
+	} {
+		rr, _ := getFrostRisk(t, h, query)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		assert.Contains(t, rr.Body.String(), message, query)
+	}
+
+	h.Forecast = &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			return model.ForecastData{}, errors.New("connection refused")
+		},
+	}
+	rr, _ := getFrostRisk(t, h, "")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestExpectedTemperature(t *testing.T) {
+	tests := []struct {
+		name     string
+		point    frostPoint
+		expected float64
+	}{
+		{"Clear And Calm", frostPoint{temperature: 2, dewPoint: -5, clouds: 0, wind: 0}, -1},
+		{"Overcast", frostPoint{temperature: 2, dewPoint: -5, clouds: 100, wind: 0}, 2},
+		{"Half Cloudy", frostPoint{temperature: 2, dewPoint: -5, clouds: 50, wind: 1}, 0.5},
+		{"Breezy", frostPoint{temperature: 2, dewPoint: -5, clouds: 0, wind: 3}, 0.5},
+		{"Windy", frostPoint{temperature: 2, dewPoint: -5, clouds: 0, wind: 5}, 2},
+		{"Humid", frostPoint{temperature: 2, dewPoint: 1, clouds: 0, wind: 0}, 1},
+		{"Saturated", frostPoint{temperature: 2, dewPoint: 2, clouds: 0, wind: 0}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, expectedTemperature(tt.point), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Limits of the history query parameters.
+const (
+	DefaultHistoryRange = 24 * time.Hour
+	MaxHistoryRange     = 366 * 24 * time.Hour
+	DefaultHistoryLimit = 168 // A week of hourly points
+	MaxHistoryLimit     = 1000
+)
+
+// historyMaxGap is the longest gap in recorded observations that is served without asking the history API
+// to fill it.
+const historyMaxGap = 3 * time.Hour
+
+// historyMaxPageSpan is the longest period a page covers, which bounds the history API calls of a request.
+const historyMaxPageSpan = 31 * 24 * time.Hour
+
+// HistoryHandler serves past observations, from the observations this service recorded and, when
+// configured, OpenWeatherMap's history API.
+type HistoryHandler struct {
+	Store  history.Store     // Recorded observations
+	Repo   repo.HistoryAPI   // Fallback for periods without recorded observations; nil to disable it
+	Config *config.AppConfig // Application configuration settings
+	now    func() time.Time  // Replaced in tests
+}
+
+// NewHistoryHandler creates a HistoryHandler. repo may be nil, in which case only recorded observations are
+// served.
+func NewHistoryHandler(store history.Store, repo repo.HistoryAPI, cfg *config.AppConfig) *HistoryHandler {
+	return &HistoryHandler{Store: store, Repo: repo, Config: cfg, now: time.Now}
+}
+
+// GetHistoryByCoordinates handles HTTP requests for past observations by coordinates, downsampled to the
+// requested interval. Long ranges are split into pages of at most limit points, linked by the next field.
+func (h *HistoryHandler) GetHistoryByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	now := h.now().UTC()
+	to := now
+	if text := query.Get("to"); text != "" {
+		if to, err = parseHistoryTime(text); err != nil {
+			http.Error(w, "Invalid query parameter: to must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.Add(-DefaultHistoryRange)
+	if text := query.Get("from"); text != "" {
+		if from, err = parseHistoryTime(text); err != nil {
+			http.Error(w, "Invalid query parameter: from must be an RFC 3339 time or a YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+	}
+	if !from.Before(to) {
+		http.Error(w, "Invalid query parameters: from must be before to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) > MaxHistoryRange {
+		http.Error(w, "Invalid query parameters: the range from from to to must not exceed 366 days", http.StatusBadRequest)
+		return
+	}
+
+	interval, err := history.ParseInterval(query.Get("interval"))
+	if err != nil {
+		http.Error(w, "Invalid interval: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit := DefaultHistoryLimit
+	if text := query.Get("limit"); text != "" {
+		limit, err = strconv.Atoi(text)
+		if err != nil || limit < 1 || limit > MaxHistoryLimit {
+			http.Error(w, "Invalid query parameter: limit must be a whole number between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The page window spans one step more than the page, so that its last point is known to be complete
+	end := from.Add(min(time.Duration(limit+1)*interval.Step(), historyMaxPageSpan))
+	if end.After(to) {
+		end = to
+	}
+
+	records, err := fetchHistoryRecords(r.Context(), h.Store, h.Repo, h.Config.HistoryURL, coords, from, minTime(end, now))
+	if err != nil {
+		handleWeatherDataError(err, w)
+		return
+	}
+	if interval == history.IntervalDaily {
+		offset := localOffset(records.observations, coords.lon)
+		for i := range records.observations {
+			records.observations[i].TimezoneOffset = offset
+		}
+	}
+	buckets := history.DownsampleWithRollups(records.observations, records.rollups, interval)
+
+	var next time.Time
+	if len(buckets) > limit || (end.Before(to) && len(buckets) > 1) {
+		cut := min(len(buckets)-1, limit)
+		next = buckets[cut].Start
+		buckets = buckets[:cut]
+	} else if end.Before(to) {
+		next = end
+	}
+
+	in, _ := units.ForSystem(units.CanonicalSystem)
+	c := converter{from: in, to: output}
+	response := model.HistoryResponse{
+		Coordinates: model.Coordinates{Lat: coords.lat, Lon: coords.lon},
+		From:        from,
+		To:          to,
+		Interval:    string(interval),
+		Source:      records.source,
+		Units: model.UnitLabels{
+			Temperature:   output.Temperature.Symbol(),
+			Speed:         output.Speed.Symbol(),
+			Pressure:      output.Pressure.Symbol(),
+			Distance:      output.Distance.Symbol(),
+			Precipitation: output.Precipitation.Symbol(),
+		},
+		Points: make([]model.HistoryPoint, len(buckets)),
+	}
+	for i, bucket := range buckets {
+		response.Points[i] = mapHistoryBucket(bucket, c)
+	}
+	if !next.IsZero() {
+		page := *r.URL
+		pageQuery := page.Query()
+		pageQuery.Set("from", next.UTC().Format(time.RFC3339))
+		pageQuery.Set("to", to.Format(time.RFC3339))
+		page.RawQuery = pageQuery.Encode()
+		response.Next = page.RequestURI()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// historyRecords are the observations and rollups of a page window, and where they came from.
+type historyRecords struct {
+	source       string
+	observations []history.Observation
+	rollups      []history.Rollup
+}
+
+// fetchHistoryRecords reads the recorded observations and rollups from from to to, completing them from the
+// history API, if there is one, when they have gaps. Recorded data is served alone when the history API
+// fails, and an error is returned only when there is none.
+func fetchHistoryRecords(ctx context.Context, store history.Store, api repo.HistoryAPI, historyURL string, coords coordinates, from, to time.Time) (historyRecords, error) {
+	records := historyRecords{source: model.HistorySourceRecorded}
+	cell := history.CellOf(coords.lat, coords.lon)
+	var err error
+	if records.observations, err = store.Range(ctx, cell, from, to); err != nil {
+		logger.WarnContext(ctx, "failed to read recorded observations", "lat", coords.latText, "lon", coords.lonText, "error", err)
+	}
+	if rollupStore, ok := store.(history.RollupStore); ok {
+		// A daily rollup may start up to a day before the range it covers
+		rollups, err := rollupStore.Rollups(ctx, cell, from.Add(-24*time.Hour), to)
+		if err != nil {
+			logger.WarnContext(ctx, "failed to read recorded rollups", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		}
+		for _, rollup := range rollups {
+			if rollup.End().After(from) {
+				records.rollups = append(records.rollups, rollup)
+			}
+		}
+	}
+	if api == nil || history.Covers(records.observations, records.rollups, from, to, historyMaxGap) {
+		return records, nil
+	}
+
+	recorded := len(records.observations) > 0 || len(records.rollups) > 0
+	data, err := api.FetchHistory(ctx, coords.latText, coords.lonText, historyURL, from, to)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to fetch history", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		if !recorded {
+			return records, err
+		}
+		return records, nil
+	}
+
+	upstream := make([]history.Observation, 0, len(data.List))
+	for _, item := range data.List {
+		o := history.FromWeatherData(coords.lat, coords.lon, item)
+		if !o.Time.Before(from) && o.Time.Before(to) && !rolledUp(records.rollups, o.Time) {
+			upstream = append(upstream, o)
+		}
+	}
+	records.source = model.HistorySourceMixed
+	if !recorded {
+		records.source = model.HistorySourceUpstream
+	}
+	records.observations = mergeObservations(records.observations, upstream)
+	return records, nil
+}
+
+// rolledUp reports whether a time falls in the period of one of the rollups.
+func rolledUp(rollups []history.Rollup, t time.Time) bool {
+	for _, rollup := range rollups {
+		if !t.Before(rollup.Start) && t.Before(rollup.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeObservations merges two time-ordered lists of observations, preferring recorded ones at equal times.
+func mergeObservations(recorded, upstream []history.Observation) []history.Observation {
+	merged := append([]history.Observation(nil), recorded...)
+	for _, o := range upstream {
+		i := sort.Search(len(recorded), func(i int) bool { return !recorded[i].Time.Before(o.Time) })
+		if i == len(recorded) || !recorded[i].Time.Equal(o.Time) {
+			merged = append(merged, o)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	return merged
+}
+
+// localOffset returns the UTC offset that days at the location are counted in: that of the newest
+// observation recorded with a timezone, since history API observations have none, or else the offset of
+// local mean solar time to the nearest hour.
+func localOffset(observations []history.Observation, lon float64) int {
+	for i := len(observations) - 1; i >= 0; i-- {
+		if observations[i].TimezoneOffset != 0 {
+			return observations[i].TimezoneOffset
+		}
+	}
+	return int(math.Round(lon/15)) * 3600
+}
+
+// mapHistoryBucket converts a bucket of canonical observations to a history point in the output units.
+func mapHistoryBucket(bucket history.Bucket, c converter) model.HistoryPoint {
+	aggregate := func(stats history.Stats, convert func(float64) float64) model.Aggregate {
+		return model.Aggregate{Min: convert(stats.Min), Max: convert(stats.Max), Mean: convert(stats.Mean)}
+	}
+	round := func(value float64) float64 { return units.Round(value, 2) }
+	return model.HistoryPoint{
+		Time:          bucket.Start,
+		Count:         bucket.Count,
+		Temperature:   aggregate(bucket.Temperature, c.temperature),
+		FeelsLike:     aggregate(bucket.FeelsLike, c.temperature),
+		Humidity:      aggregate(bucket.Humidity, round),
+		Pressure:      aggregate(bucket.Pressure, c.pressure),
+		WindSpeed:     aggregate(bucket.WindSpeed, c.speed),
+		Cloudiness:    aggregate(bucket.Clouds, round),
+		Precipitation: aggregate(bucket.Precipitation, c.precipitation),
+	}
+}
+
+// parseHistoryTime parses an RFC 3339 time or a YYYY-MM-DD date, which stands for midnight UTC.
+func parseHistoryTime(text string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(dateLayout, text)
+	if err != nil {
+		return time.Time{}, errors.New("expected an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	return t, nil
+}
+
+// minTime returns the earlier of two times.
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
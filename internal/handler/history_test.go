@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockHistoryAPI struct {
+	FetchFunc func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error)
+}
+
+func (m *MockHistoryAPI) FetchHistory(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+	return m.FetchFunc(ctx, lat, lon, openWeatherMapHistoryURL, start, end)
+}
+
+var historyStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // 09:00 in Tokyo
+
+// upstreamHistory serves hourly observations of 280.15 K for any period.
+func upstreamHistory(t *testing.T) *MockHistoryAPI {
+	return &MockHistoryAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+			assert.Equal(t, "http://example.com/history", openWeatherMapHistoryURL)
+			var data model.HistoryData
+			for at := start.Truncate(time.Hour); at.Before(end); at = at.Add(time.Hour) {
+				data.List = append(data.List, model.WeatherData{Dt: at.Unix(), Main: model.MainInfo{Temp: 280.15, Pressure: 1013}})
+			}
+			data.Cnt = len(data.List)
+			return data, nil
+		},
+	}
+}
+
+// recordedStore holds observations in Tokyo every 30 minutes for the given hours from historyStart, with
+// the temperature rising by 1 K per observation from 270 K.
+func recordedStore(hours int) *history.MemoryStore {
+	store := history.NewMemoryStore(0)
+	for i := 0; i < hours*2; i++ {
+		store.Append(context.Background(), history.Observation{
+			Time: historyStart.Add(time.Duration(i) * 30 * time.Minute), Lat: 35, Lon: 139, TimezoneOffset: 32400,
+			Temperature: 270 + float64(i), Humidity: 50, Precipitation: float64(i % 2),
+		})
+	}
+	return store
+}
+
+func getHistory(t *testing.T, store history.Store, api repo.HistoryAPI, query string) (*httptest.ResponseRecorder, model.HistoryResponse) {
+	h := NewHistoryHandler(store, api, &config.AppConfig{HistoryURL: "http://example.com/history", UnitOfMeasurement: "standard"})
+	h.now = func() time.Time { return historyStart.Add(72 * time.Hour) }
+
+	req, _ := http.NewRequest("GET", "/history?lat=35&lon=139&"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetHistoryByCoordinates(rr, req)
+
+	var response model.HistoryResponse
+	if rr.Code == http.StatusOK {
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	}
+	return rr, response
+}
+
+func TestHistoryHandler_Recorded(t *testing.T) {
+	rr, response := getHistory(t, recordedStore(24), nil, "from=2024-01-01&to=2024-01-02")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceRecorded, response.Source)
+	assert.Equal(t, "hourly", response.Interval)
+	assert.Equal(t, "K", response.Units.Temperature)
+	assert.Empty(t, response.Next)
+	if assert.Len(t, response.Points, 24) {
+		first := response.Points[0]
+		assert.Equal(t, historyStart, first.Time)
+		assert.Equal(t, 2, first.Count)
+		assert.Equal(t, model.Aggregate{Min: 270, Max: 271, Mean: 270.5}, first.Temperature)
+		assert.Equal(t, model.Aggregate{Min: 0, Max: 1, Mean: 0.5}, first.Precipitation)
+	}
+}
+
+func TestHistoryHandler_Pagination(t *testing.T) {
+	rr, response := getHistory(t, recordedStore(24), nil, "from=2024-01-01T00:15:00Z&to=2024-01-02&limit=10&units=metric")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "°C", response.Units.Temperature)
+	if assert.Len(t, response.Points, 10) {
+		// The first point only has the observation at 00:30
+		assert.Equal(t, 1, response.Points[0].Count)
+		assert.Equal(t, -2.15, response.Points[0].Temperature.Mean)
+		assert.Equal(t, historyStart.Add(9*time.Hour), response.Points[9].Time)
+	}
+
+	next, err := url.Parse(response.Next)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/history", next.Path)
+		assert.Equal(t, "2024-01-01T10:00:00Z", next.Query().Get("from"))
+		assert.Equal(t, "2024-01-02T00:00:00Z", next.Query().Get("to"))
+		assert.Equal(t, "10", next.Query().Get("limit"))
+		assert.Equal(t, "metric", next.Query().Get("units"))
+	}
+
+	// Following the links pages through the rest of the range
+	pages, points := 1, len(response.Points)
+	for response.Next != "" && pages < 10 {
+		next, _ := url.Parse(response.Next)
+		_, response = getHistory(t, recordedStore(24), nil, next.RawQuery)
+		pages, points = pages+1, points+len(response.Points)
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, 24, points)
+}
+
+func TestHistoryHandler_Upstream(t *testing.T) {
+	rr, response := getHistory(t, history.NewMemoryStore(0), upstreamHistory(t), "from=2024-01-01&to=2024-01-01T06:00:00Z&units=metric")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceUpstream, response.Source)
+	if assert.Len(t, response.Points, 6) {
+		assert.Equal(t, model.Aggregate{Min: 7, Max: 7, Mean: 7}, response.Points[5].Temperature)
+		assert.Equal(t, 1013.0, response.Points[5].Pressure.Mean)
+	}
+}
+
+func TestHistoryHandler_Mixed(t *testing.T) {
+	// Recorded observations cover the first 12 hours of the day, the history API the rest
+	rr, response := getHistory(t, recordedStore(12), upstreamHistory(t), "from=2024-01-01&to=2024-01-02")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceMixed, response.Source)
+	if assert.Len(t, response.Points, 24) {
+		assert.Equal(t, 2, response.Points[0].Count, "recorded observations are preferred at equal times")
+		assert.Equal(t, 270.5, response.Points[0].Temperature.Mean)
+		assert.Equal(t, 1, response.Points[23].Count)
+		assert.Equal(t, 280.15, response.Points[23].Temperature.Mean)
+	}
+}
+
+// rollupStore is a MemoryStore that also serves fixed rollups.
+type rollupStore struct {
+	*history.MemoryStore
+	rollups []history.Rollup
+}
+
+func (s rollupStore) Rollups(ctx context.Context, cell history.Cell, from, to time.Time) ([]history.Rollup, error) {
+	var rollups []history.Rollup
+	for _, rollup := range s.rollups {
+		if rollup.Cell == cell && !rollup.Start.Before(from) && rollup.Start.Before(to) {
+			rollups = append(rollups, rollup)
+		}
+	}
+	return rollups, nil
+}
+
+func TestHistoryHandler_Rollups(t *testing.T) {
+	// The first 12 hours of the day are rolled up, the rest is recorded as observations
+	rolledUp, _ := recordedStore(12).Range(context.Background(), history.CellOf(35, 139), historyStart, historyStart.Add(12*time.Hour))
+	store := rollupStore{MemoryStore: history.NewMemoryStore(0), rollups: history.RollUp(history.CellOf(35, 139), rolledUp, history.IntervalHourly)}
+	for i := 24; i < 48; i++ {
+		store.Append(context.Background(), history.Observation{Time: historyStart.Add(time.Duration(i) * 30 * time.Minute), Lat: 35, Lon: 139, Temperature: 280})
+	}
+	unused := &MockHistoryAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+			t.Error("The history API must not be asked for a period that rollups cover")
+			return model.HistoryData{}, nil
+		},
+	}
+
+	rr, response := getHistory(t, store, unused, "from=2024-01-01&to=2024-01-02")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceRecorded, response.Source)
+	if assert.Len(t, response.Points, 24) {
+		assert.Equal(t, 2, response.Points[0].Count)
+		assert.Equal(t, model.Aggregate{Min: 270, Max: 271, Mean: 270.5}, response.Points[0].Temperature)
+		assert.Equal(t, 280.0, response.Points[23].Temperature.Mean)
+	}
+
+	_, response = getHistory(t, store, unused, "from=2024-01-01&to=2024-01-02&interval=daily")
+	if assert.Len(t, response.Points, 2) {
+		assert.Equal(t, 30, response.Points[0].Count, "the day in Tokyo ends at 15:00 UTC")
+	}
+}
+
+func TestHistoryHandler_UpstreamErrors(t *testing.T) {
+	failing := &MockHistoryAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+			return model.HistoryData{}, repo.ErrInvalidAPIKey
+		},
+	}
+
+	rr, _ := getHistory(t, history.NewMemoryStore(0), failing, "from=2024-01-01&to=2024-01-02")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Recorded observations are still served when the history API cannot fill their gaps
+	rr, response := getHistory(t, recordedStore(12), failing, "from=2024-01-01&to=2024-01-02")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, model.HistorySourceRecorded, response.Source)
+	assert.Len(t, response.Points, 12)
+}
+
+func TestHistoryHandler_Daily(t *testing.T) {
+	rr, response := getHistory(t, recordedStore(48), nil, "from=2024-01-01&to=2024-01-03&interval=daily")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "daily", response.Interval)
+	// Days start at midnight in Tokyo, 15:00 UTC
+	if assert.Len(t, response.Points, 3) {
+		assert.True(t, response.Points[0].Time.Equal(historyStart.Add(-9*time.Hour)), "got %v", response.Points[0].Time)
+		assert.Equal(t, 30, response.Points[0].Count)
+		assert.Equal(t, model.Aggregate{Min: 270, Max: 299, Mean: 284.5}, response.Points[0].Temperature)
+		assert.Equal(t, 48, response.Points[1].Count)
+		assert.Equal(t, 18, response.Points[2].Count)
+	}
+}
+
+func TestHistoryHandler_DailyPageSpan(t *testing.T) {
+	calls := 0
+	api := upstreamHistory(t)
+	fetch := api.FetchFunc
+	api.FetchFunc = func(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+		calls++
+		assert.LessOrEqual(t, end.Sub(start), 31*24*time.Hour)
+		return fetch(ctx, lat, lon, openWeatherMapHistoryURL, start, end)
+	}
+
+	// A page of 168 days would need 25 weekly history API calls; it is cut to a month
+	rr, response := getHistory(t, history.NewMemoryStore(0), api, "from=2023-01-01&to=2024-01-01&interval=daily")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, response.Points, 31)
+	next, err := url.Parse(response.Next)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2023-01-31T15:00:00Z", next.Query().Get("from"))
+	}
+}
+
+func TestLocalOffset(t *testing.T) {
+	recorded := []history.Observation{{TimezoneOffset: 32400}, {TimezoneOffset: 0}}
+	assert.Equal(t, 32400, localOffset(recorded, 139))
+	assert.Equal(t, 9*3600, localOffset(nil, 139))
+	assert.Equal(t, -5*3600, localOffset(nil, -74))
+}
+
+func TestHistoryHandler_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		query, message string
+	}{
+		{"from=yesterday", "from must be an RFC 3339 time"},
+		{"to=2024-13-01", "to must be an RFC 3339 time"},
+		{"from=2024-01-02&to=2024-01-01", "from must be before to"},
+		{"from=2023-01-01&to=2024-01-03", "must not exceed 366 days"},
+		{"interval=weekly", "unknown interval"},
+		{"limit=0", "limit must be a whole number"},
+		{"limit=1001", "limit must be a whole number"},
+		{"units=cubits", "Invalid units"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rr, _ := getHistory(t, history.NewMemoryStore(0), nil, tt.query)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.message)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// Limits of the nowcast hours query parameter
+const (
+	DefaultNowcastHours = 3
+	MaxNowcastHours     = 12
+)
+
+const (
+	wetRate            = 0.1 // mm/h; lighter rates are trace amounts and count as dry
+	minutelyConfidence = 0.9 // Minutely data is a radar-based nowcast and carries no probability of its own
+)
+
+// intensityLevels are the upper rates in mm/h of the rain intensity classes; heavier rain is violent.
+var intensityLevels = []struct {
+	upTo  float64
+	level string
+}{
+	{2.5, "light"},
+	{7.6, "moderate"},
+	{50, "heavy"},
+}
+
+// NowcastHandler answers when precipitation will start or stop within the next hours.
+type NowcastHandler struct {
+	Forecast repo.ForecastAPI  // Interface for fetching the 3-hour forecast
+	OneCall  repo.OneCallAPI   // Source of minutely precipitation; nil when it is not enabled
+	Config   *config.AppConfig // Application configuration settings
+	now      func() time.Time  // Replaced in tests
+}
+
+// NewNowcastHandler creates a NowcastHandler. oneCall may be nil, in which case only the forecast is used.
+func NewNowcastHandler(forecast repo.ForecastAPI, oneCall repo.OneCallAPI, cfg *config.AppConfig) *NowcastHandler {
+	return &NowcastHandler{Forecast: forecast, OneCall: oneCall, Config: cfg, now: time.Now}
+}
+
+// GetNowcastByCoordinates handles HTTP requests for the precipitation nowcast by coordinates.
+func (h *NowcastHandler) GetNowcastByCoordinates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	hours := DefaultNowcastHours
+	if text := query.Get("hours"); text != "" {
+		hours, err = strconv.Atoi(text)
+		if err != nil || hours < 1 || hours > MaxNowcastHours {
+			http.Error(w, "Invalid query parameter: hours must be a whole number between 1 and 12", http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lang, err := requestLanguage(r)
+	if err != nil {
+		http.Error(w, "Invalid lang: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := i18n.NewContext(r.Context(), lang)
+
+	forecast, err := h.Forecast.FetchForecast(ctx, coords.latText, coords.lonText, h.Config.ForecastURL, units.CanonicalSystem)
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch forecast", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		handleWeatherDataError(err, w)
+		return
+	}
+
+	source := model.NowcastSourceForecast
+	spans := forecastSpans(forecast.List)
+	if h.OneCall != nil {
+		// Minutely data only refines the first hour, so the forecast alone still gives an answer without it
+		minutely, err := h.OneCall.FetchMinutely(ctx, coords.latText, coords.lonText, h.Config.OneCallURL)
+		if err != nil {
+			logger.WarnContext(r.Context(), "failed to fetch minutely precipitation, using the forecast only", "error", err)
+		} else if len(minutely) > 0 {
+			source = model.NowcastSourceMinutely
+			spans = withMinutely(spans, minutely)
+		}
+	}
+
+	from := h.now().UTC().Truncate(time.Minute)
+	response := analyzeNowcast(spans, from, from.Add(time.Duration(hours)*time.Hour), output)
+	response.Source = source
+	response.Summary = i18n.Nowcast(lang, nowcastFacts(response))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(lang))
+	json.NewEncoder(w).Encode(response)
+}
+
+// precipitationSpan is a period with a constant forecast precipitation rate.
+type precipitationSpan struct {
+	start, end  time.Time
+	rate        float64 // mm/h
+	probability float64 // Of precipitation
+	kind        string  // rain or snow
+}
+
+// forecastSpans turns 3-hour forecast steps, fetched in canonical units, into spans covering the 3 hours up
+// to each step.
+func forecastSpans(steps []model.ForecastItem) []precipitationSpan {
+	spans := make([]precipitationSpan, 0, len(steps))
+	for _, step := range steps {
+		end := time.Unix(step.Dt, 0).UTC()
+		spans = append(spans, precipitationSpan{
+			start:       end.Add(-forecastStep),
+			end:         end,
+			rate:        precipitationVolume(step) / forecastStep.Hours(),
+			probability: step.Pop,
+			kind:        precipitationKind(step.Weather),
+		})
+	}
+	return spans
+}
+
+// withMinutely replaces the forecast spans with one-minute spans where minutely data is available. The kind
+// of precipitation comes from the overlapping forecast step.
+func withMinutely(forecast []precipitationSpan, minutely []model.MinutelyPrecipitation) []precipitationSpan {
+	kindAt := func(t time.Time) string {
+		for _, span := range forecast {
+			if !t.Before(span.start) && t.Before(span.end) {
+				return span.kind
+			}
+		}
+		return "rain"
+	}
+
+	spans := make([]precipitationSpan, 0, len(minutely)+len(forecast))
+	var covered time.Time
+	for _, minute := range minutely {
+		start := time.Unix(minute.Dt, 0).UTC()
+		covered = start.Add(time.Minute)
+		// A dry minute is as confidently dry as a wet one is wet
+		probability := minutelyConfidence
+		if minute.Precipitation < wetRate {
+			probability = 1 - minutelyConfidence
+		}
+		spans = append(spans, precipitationSpan{start: start, end: covered, rate: minute.Precipitation, probability: probability, kind: kindAt(start)})
+	}
+
+	for _, span := range forecast {
+		if span.start.Before(covered) {
+			span.start = covered
+		}
+		if span.end.After(span.start) {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// analyzeNowcast finds the first precipitation spell between from and until.
+func analyzeNowcast(spans []precipitationSpan, from, until time.Time, output units.Set) model.NowcastResponse {
+	response := model.NowcastResponse{From: from, Until: until}
+
+	var window []precipitationSpan
+	for _, span := range spans {
+		if span.start.Before(from) {
+			span.start = from
+		}
+		if span.end.After(until) {
+			span.end = until
+		}
+		if span.end.After(span.start) {
+			window = append(window, span)
+		}
+	}
+	sort.Slice(window, func(i, j int) bool { return window[i].start.Before(window[j].start) })
+
+	first := -1
+	maxProbability := 0.0
+	for i, span := range window {
+		maxProbability = max(maxProbability, span.probability)
+		if first < 0 && span.rate >= wetRate {
+			first = i
+		}
+	}
+	if first < 0 {
+		response.Confidence = units.Round(1-maxProbability, 2)
+		return response
+	}
+
+	// The spell lasts until the first dry span or gap in the data
+	spell := window[first]
+	peak, end := spell, spell.end
+	for _, span := range window[first+1:] {
+		if span.rate < wetRate || span.start.After(end) {
+			break
+		}
+		if span.rate > peak.rate {
+			peak = span
+		}
+		end = span.end
+	}
+
+	response.Kind = spell.kind
+	response.Confidence = spell.probability
+	response.PrecipitationNow = spell.start.Equal(from)
+	if !response.PrecipitationNow {
+		response.PrecipitationStartsAt = &spell.start
+	}
+	if end.Before(until) {
+		response.EndsAt = &end
+	}
+
+	rate, _ := units.ConvertPrecipitation(peak.rate, units.Millimeters, output.Precipitation)
+	response.PeakIntensity = &model.Intensity{
+		Rate:  units.Round(rate, 2),
+		Unit:  output.Precipitation.Symbol() + "/h",
+		Level: intensityLevel(peak.rate),
+		At:    peak.start,
+	}
+	return response
+}
+
+// intensityLevel classifies a precipitation rate in mm/h.
+func intensityLevel(rate float64) string {
+	for _, level := range intensityLevels {
+		if rate < level.upTo {
+			return level.level
+		}
+	}
+	return "violent"
+}
+
+// precipitationKind tells snow from rain by the primary condition group.
+func precipitationKind(weather []model.WeatherCondition) string {
+	if len(weather) > 0 && taxonomy.GroupOf(weather[0].ID) == taxonomy.GroupSnow {
+		return "snow"
+	}
+	return "rain"
+}
+
+// nowcastFacts extracts what the nowcast sentence needs from the analysis.
+func nowcastFacts(response model.NowcastResponse) i18n.NowcastFacts {
+	facts := i18n.NowcastFacts{Window: response.Until.Sub(response.From), Kind: response.Kind, Raining: response.PrecipitationNow}
+	if response.PrecipitationStartsAt != nil {
+		facts.StartsIn = response.PrecipitationStartsAt.Sub(response.From)
+	}
+	if response.EndsAt != nil {
+		facts.EndsIn = response.EndsAt.Sub(response.From)
+	}
+	return facts
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockOneCallAPI implementation for testing
+type MockOneCallAPI struct {
+	FetchOneCallFunc  func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error)
+	FetchMinutelyFunc func(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error)
+}
+
+func (m *MockOneCallAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+	return m.FetchOneCallFunc(ctx, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement, exclude)
+}
+
+func (m *MockOneCallAPI) FetchMinutely(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+	return m.FetchMinutelyFunc(ctx, lat, lon, openWeatherMapOneCallURL)
+}
+
+// nowcastForecast is dry until 12:00, rains until 18:00, heavily from 15:00, and is dry again until 21:00.
+func nowcastForecast() model.ForecastData {
+	return model.ForecastData{List: []model.ForecastItem{
+		forecastItem(0, 10, 803, "Clouds", 0, 0, 0.1, 3, 180),
+		forecastItem(3, 9, 500, "Rain", 3, 0, 0.7, 3, 180),
+		forecastItem(6, 8, 502, "Rain", 30, 0, 0.9, 3, 180),
+		forecastItem(9, 8, 803, "Clouds", 0, 0, 0.2, 3, 180),
+	}}
+}
+
+func newTestNowcastHandler(now time.Time, oneCall *MockOneCallAPI) *NowcastHandler {
+	forecastAPI := &MockForecastAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+			return nowcastForecast(), nil
+		},
+	}
+	h := NewNowcastHandler(forecastAPI, nil, &config.AppConfig{UnitOfMeasurement: "metric", OneCallURL: "http://example.com/onecall"})
+	if oneCall != nil {
+		h.OneCall = oneCall
+	}
+	h.now = func() time.Time { return now }
+	return h
+}
+
+func getNowcast(t *testing.T, h *NowcastHandler, query string) model.NowcastResponse {
+	req, _ := http.NewRequest("GET", "/nowcast?lat=35&lon=139"+query, nil)
+	rr := httptest.NewRecorder()
+	h.GetNowcastByCoordinates(rr, req)
+
+	var response model.NowcastResponse
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_Forecast(t *testing.T) {
+	at := func(hour int) *time.Time {
+		moment := time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC)
+		return &moment
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		query         string
+		raining       bool
+		startsAt      *time.Time
+		endsAt        *time.Time
+		peakRate      float64
+		peakLevel     string
+		confidence    float64
+		expectSummary string
+	}{
+		{"Starts Within Window", *at(10), "", false, at(12), nil, 1, "light", 0.7, "Rain starting in 2 h"},
+		{"Whole Spell", *at(10), "&hours=12", false, at(12), at(18), 10, "heavy", 0.7, "Rain starting in 2 h, lasting 6 h"},
+		{"Continues", *at(13), "", true, nil, nil, 10, "heavy", 0.7, "Rain continuing for at least 3 h"},
+		{"Stops", *at(13), "&hours=6", true, nil, at(18), 10, "heavy", 0.7, "Rain stopping in 5 h"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			response := getNowcast(t, newTestNowcastHandler(tc.now, nil), tc.query)
+
+			assert.Equal(t, model.NowcastSourceForecast, response.Source)
+			assert.Equal(t, tc.raining, response.PrecipitationNow)
+			assert.Equal(t, "rain", response.Kind)
+			assert.Equal(t, tc.startsAt, response.PrecipitationStartsAt)
+			assert.Equal(t, tc.endsAt, response.EndsAt)
+			if assert.NotNil(t, response.PeakIntensity) {
+				assert.Equal(t, tc.peakRate, response.PeakIntensity.Rate)
+				assert.Equal(t, "mm/h", response.PeakIntensity.Unit)
+				assert.Equal(t, tc.peakLevel, response.PeakIntensity.Level)
+			}
+			assert.Equal(t, tc.confidence, response.Confidence)
+			assert.Equal(t, tc.expectSummary, response.Summary)
+		})
+	}
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_Dry(t *testing.T) {
+	h := newTestNowcastHandler(time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC), nil)
+
+	response := getNowcast(t, h, "&hours=2&lang=de")
+	assert.False(t, response.PrecipitationNow)
+	assert.Nil(t, response.PrecipitationStartsAt)
+	assert.Nil(t, response.PeakIntensity)
+	assert.Equal(t, 0.8, response.Confidence)
+	assert.Equal(t, "Kein Niederschlag in den nächsten 2 Std. erwartet", response.Summary)
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_Minutely(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	oneCall := &MockOneCallAPI{
+		FetchMinutelyFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+			assert.Equal(t, "http://example.com/onecall", openWeatherMapOneCallURL)
+			minutes := make([]model.MinutelyPrecipitation, 60)
+			for i := range minutes {
+				minutes[i].Dt = now.Add(time.Duration(i) * time.Minute).Unix()
+				if i >= 20 && i < 45 {
+					minutes[i].Precipitation = 3
+				}
+			}
+			return minutes, nil
+		},
+	}
+
+	response := getNowcast(t, newTestNowcastHandler(now, oneCall), "&hours=1&units=imperial")
+	assert.Equal(t, model.NowcastSourceMinutely, response.Source)
+	assert.Equal(t, now.Add(20*time.Minute), *response.PrecipitationStartsAt)
+	assert.Equal(t, now.Add(45*time.Minute), *response.EndsAt)
+	assert.Equal(t, &model.Intensity{Rate: 0.12, Unit: "in/h", Level: "moderate", At: now.Add(20 * time.Minute)}, response.PeakIntensity)
+	assert.Equal(t, 0.9, response.Confidence)
+	assert.Equal(t, "Rain starting in 20 min, lasting 25 min", response.Summary)
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_MinutelyDry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	oneCall := &MockOneCallAPI{
+		FetchMinutelyFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+			minutes := make([]model.MinutelyPrecipitation, 60)
+			for i := range minutes {
+				minutes[i].Dt = now.Add(time.Duration(i) * time.Minute).Unix()
+			}
+			return minutes, nil
+		},
+	}
+
+	response := getNowcast(t, newTestNowcastHandler(now, oneCall), "&hours=1")
+	assert.Equal(t, model.NowcastSourceMinutely, response.Source)
+	assert.Nil(t, response.PrecipitationStartsAt)
+	assert.Equal(t, 0.9, response.Confidence, "dry minutely data is confidently dry")
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_MinutelyUnavailable(t *testing.T) {
+	oneCall := &MockOneCallAPI{
+		FetchMinutelyFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+			return nil, errors.New("no One Call subscription")
+		},
+	}
+
+	response := getNowcast(t, newTestNowcastHandler(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), oneCall), "")
+	assert.Equal(t, model.NowcastSourceForecast, response.Source)
+	assert.Equal(t, "Rain starting in 2 h", response.Summary)
+}
+
+func TestNowcastHandler_GetNowcastByCoordinates_InvalidHours(t *testing.T) {
+	h := newTestNowcastHandler(time.Now(), nil)
+
+	req, _ := http.NewRequest("GET", "/nowcast?lat=35&lon=139&hours=13", nil)
+	rr := httptest.NewRecorder()
+	h.GetNowcastByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid query parameter: hours must be a whole number between 1 and 12\n", rr.Body.String())
+}
+
+func TestIntensityLevel(t *testing.T) {
+	tests := map[float64]string{0.2: "light", 2.5: "moderate", 7.5: "moderate", 7.6: "heavy", 49: "heavy", 50: "violent"}
+	for rate, expected := range tests {
+		assert.Equal(t, expected, intensityLevel(rate), "rate %v", rate)
+	}
+}
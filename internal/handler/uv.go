@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/astro"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+	"github.com/golang2go/demo-app/weather-service-api/internal/uv"
+)
+
+// UVHandler reports the UV index and safe sun exposure times.
+type UVHandler struct {
+	OneCall repo.OneCallAPI   // Source of forecast UV indices; nil to always estimate them
+	Config  *config.AppConfig // Application configuration settings
+	now     func() time.Time  // Replaced in tests
+}
+
+// NewUVHandler creates a UVHandler. oneCall may be nil, in which case UV indices are estimated for a clear sky.
+func NewUVHandler(oneCall repo.OneCallAPI, cfg *config.AppConfig) *UVHandler {
+	return &UVHandler{OneCall: oneCall, Config: cfg, now: time.Now}
+}
+
+// GetUVByCoordinates handles HTTP requests for the UV index by coordinates. One Call UV indices are used when
+// available; otherwise they are estimated for a clear sky from the solar elevation. A rejected API key is
+// reported rather than hidden behind the estimate.
+func (h *UVHandler) GetUVByCoordinates(w http.ResponseWriter, r *http.Request) {
+	coords, err := parseCoordinates(r.URL.Query())
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+
+	// Today at the location in local mean solar time, as for the astronomy endpoint
+	now := h.now().UTC()
+	date := now.Add(time.Duration(coords.lon / 15 * float64(time.Hour)))
+	solarNoon := astro.SunTimesFor(date, coords.lat, coords.lon).SolarNoon
+
+	response, err := h.fromOneCall(r.Context(), coords, solarNoon)
+	if errors.Is(err, repo.ErrInvalidAPIKey) {
+		handleWeatherDataError(err, w)
+		return
+	}
+	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch UV index, estimating it for a clear sky", "lat", coords.latText, "lon", coords.lonText, "error", err)
+		response = clearSkyUV(coords, now, solarNoon)
+	}
+
+	response.Coordinates = model.Coordinates{Lat: coords.lat, Lon: coords.lon}
+	response.Category = uv.Category(response.UVIndex)
+	response.Advice = uv.Advice(response.UVIndex)
+	response.Peak.Category = uv.Category(response.Peak.UVIndex)
+	response.Exposure = skinExposure(response.UVIndex, response.Peak.UVIndex)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// fromOneCall takes the current UV index and the day's peak from the One Call API. The peak is the highest
+// hourly index of the location's day; when the daily maximum is higher, the peak has passed and is placed
+// at solar noon.
+func (h *UVHandler) fromOneCall(ctx context.Context, coords coordinates, solarNoon time.Time) (model.UVResponse, error) {
+	if h.OneCall == nil {
+		return model.UVResponse{}, errors.New("One Call API is not configured")
+	}
+	data, err := h.OneCall.FetchOneCall(ctx, coords.latText, coords.lonText, h.Config.OneCallURL, units.CanonicalSystem,
+		repo.ExcludeAllBut(repo.BlockCurrent, repo.BlockHourly, repo.BlockDaily))
+	if err != nil {
+		return model.UVResponse{}, err
+	}
+	if data.Current == nil {
+		return model.UVResponse{}, errors.New("One Call response has no current conditions")
+	}
+
+	current := time.Unix(data.Current.Dt, 0).UTC()
+	response := model.UVResponse{Source: model.UVSourceOneCall, Time: current, UVIndex: data.Current.UVI}
+	response.Peak = model.UVPeak{Time: current, UVIndex: data.Current.UVI}
+	if len(data.Daily) > 0 && data.Daily[0].UVI > response.Peak.UVIndex {
+		response.Peak = model.UVPeak{Time: solarNoon, UVIndex: data.Daily[0].UVI}
+	}
+
+	zone := time.FixedZone("", data.TimezoneOffset)
+	today := current.In(zone).Format(dateLayout)
+	for _, hour := range data.Hourly {
+		at := time.Unix(hour.Dt, 0).UTC()
+		if at.In(zone).Format(dateLayout) == today && hour.UVI >= response.Peak.UVIndex {
+			response.Peak = model.UVPeak{Time: at, UVIndex: hour.UVI}
+		}
+	}
+	return response, nil
+}
+
+// clearSkyUV estimates the current UV index and the day's peak, at solar noon, for a clear sky.
+func clearSkyUV(coords coordinates, now, solarNoon time.Time) model.UVResponse {
+	estimate := func(at time.Time) float64 {
+		return units.Round(uv.ClearSky(astro.SunPosition(at, coords.lat, coords.lon).Elevation), 1)
+	}
+	return model.UVResponse{
+		Source:  model.UVSourceClearSky,
+		Time:    now,
+		UVIndex: estimate(now),
+		Peak:    model.UVPeak{Time: solarNoon, UVIndex: estimate(solarNoon)},
+	}
+}
+
+// skinExposure lists the safe exposure times of every skin type at the current and peak UV index.
+func skinExposure(current, peak float64) []model.SkinExposure {
+	minutes := func(uvi, med float64) *int {
+		if m, ok := uv.SafeMinutes(uvi, med); ok {
+			return &m
+		}
+		return nil
+	}
+
+	exposure := make([]model.SkinExposure, len(uv.SkinTypes))
+	for i, skin := range uv.SkinTypes {
+		exposure[i] = model.SkinExposure{
+			SkinType:          skin,
+			SafeMinutes:       minutes(current, skin.MED),
+			SafeMinutesAtPeak: minutes(peak, skin.MED),
+		}
+	}
+	return exposure
+}
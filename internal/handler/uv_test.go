@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/astro"
+	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/uv"
+	"github.com/stretchr/testify/assert"
+)
+
+var uvDay = time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC) // 09:00 in Tokyo
+
+// uvOneCall serves the UV indices of uvDay in Tokyo from the given hour on, with a daily maximum of 9.5.
+func uvOneCall(t *testing.T, fromHour int) *MockOneCallAPI {
+	hourly := map[int]float64{0: 5, 1: 7, 2: 9, 3: 9.5, 4: 8, 5: 6, 6: 4, 7: 3, 15: 10} // 15:00 UTC is the next day in Tokyo
+	return &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			assert.Equal(t, []repo.OneCallBlock{repo.BlockMinutely, repo.BlockAlerts}, exclude)
+			data := model.OneCallData{
+				Lat: 35, Lon: 139, TimezoneOffset: 32400,
+				Current: &model.OneCallCurrent{Dt: uvDay.Add(time.Duration(fromHour) * time.Hour).Unix(), UVI: hourly[fromHour]},
+				Daily:   []model.OneCallDaily{{Dt: uvDay.Add(3 * time.Hour).Unix(), UVI: 9.5}},
+			}
+			for hour := fromHour; hour < 16; hour++ {
+				if uvi, ok := hourly[hour]; ok {
+					data.Hourly = append(data.Hourly, model.OneCallHourly{Dt: uvDay.Add(time.Duration(hour) * time.Hour).Unix(), UVI: uvi})
+				}
+			}
+			return data, nil
+		},
+	}
+}
+
+func getUV(t *testing.T, oneCall repo.OneCallAPI, now time.Time) model.UVResponse {
+	h := NewUVHandler(oneCall, &config.AppConfig{OneCallURL: "http://example.com/onecall"})
+	h.now = func() time.Time { return now }
+
+	req, _ := http.NewRequest("GET", "/uv?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	h.GetUVByCoordinates(rr, req)
+
+	var response model.UVResponse
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func TestUVHandler_GetUVByCoordinates_OneCall(t *testing.T) {
+	response := getUV(t, uvOneCall(t, 0), uvDay)
+
+	assert.Equal(t, model.UVSourceOneCall, response.Source)
+	assert.Equal(t, model.Coordinates{Lat: 35, Lon: 139}, response.Coordinates)
+	assert.Equal(t, uvDay, response.Time)
+	assert.Equal(t, 5.0, response.UVIndex)
+	assert.Equal(t, uv.Moderate, response.Category)
+	assert.NotEmpty(t, response.Advice)
+	assert.Equal(t, model.UVPeak{Time: uvDay.Add(3 * time.Hour), UVIndex: 9.5, Category: uv.VeryHigh}, response.Peak)
+
+	if assert.Len(t, response.Exposure, 6) {
+		fair, dark := response.Exposure[0], response.Exposure[5]
+		assert.Equal(t, "I", fair.Type)
+		assert.Equal(t, 26, *fair.SafeMinutes)       // 200 J/m² at UV index 5
+		assert.Equal(t, 14, *fair.SafeMinutesAtPeak) // and at 9.5
+		assert.Equal(t, "VI", dark.Type)
+		assert.Equal(t, 70, *dark.SafeMinutesAtPeak)
+	}
+}
+
+func TestUVHandler_GetUVByCoordinates_PeakPassed(t *testing.T) {
+	// In the afternoon the hourly forecast no longer covers the peak, which the daily maximum still reports
+	response := getUV(t, uvOneCall(t, 6), uvDay.Add(6*time.Hour))
+
+	solarNoon := astro.SunTimesFor(uvDay, 35, 139).SolarNoon
+	assert.Equal(t, 4.0, response.UVIndex)
+	assert.Equal(t, model.UVPeak{Time: solarNoon, UVIndex: 9.5, Category: uv.VeryHigh}, response.Peak)
+}
+
+func TestUVHandler_GetUVByCoordinates_ClearSky(t *testing.T) {
+	unavailable := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{}, repo.ErrServiceUnavailable
+		},
+	}
+
+	for name, oneCall := range map[string]repo.OneCallAPI{"Unavailable": unavailable, "Not Configured": nil} {
+		t.Run(name, func(t *testing.T) {
+			midnight := uvDay.Add(15 * time.Hour) // In Tokyo
+			response := getUV(t, oneCall, midnight)
+
+			assert.Equal(t, model.UVSourceClearSky, response.Source)
+			assert.Equal(t, midnight, response.Time)
+			assert.Equal(t, 0.0, response.UVIndex)
+			assert.Equal(t, uv.Low, response.Category)
+			assert.Nil(t, response.Exposure[0].SafeMinutes)
+
+			// The sun stands about 78° high at noon on the solstice
+			assert.Equal(t, astro.SunTimesFor(midnight.Add(139*time.Hour/15), 35, 139).SolarNoon, response.Peak.Time)
+			assert.InDelta(t, 11.9, response.Peak.UVIndex, 0.1)
+			assert.Equal(t, uv.Extreme, response.Peak.Category)
+			assert.NotNil(t, response.Exposure[0].SafeMinutesAtPeak)
+		})
+	}
+}
+
+func TestUVHandler_GetUVByCoordinates_InvalidAPIKey(t *testing.T) {
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{}, repo.ErrInvalidAPIKey
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/uv?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	NewUVHandler(oneCall, &config.AppConfig{}).GetUVByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, "Invalid API key.\n", rr.Body.String())
+}
+
+func TestUVHandler_GetUVByCoordinates_InvalidCoordinates(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/uv?lat=95&lon=139", nil)
+	rr := httptest.NewRecorder()
+	NewUVHandler(nil, &config.AppConfig{}).GetUVByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid query parameters: lat must be a number between -90 and 90, got \"95\"\n", rr.Body.String())
+}
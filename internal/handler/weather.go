@@ -1,55 +1,162 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
 	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
 	"github.com/golang2go/demo-app/weather-service-api/internal/model"
 	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
 	"github.com/golang2go/demo-app/weather-service-api/internal/util"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+var logger = logging.Logger("handler")
+
+// builtinCategories holds the builtin category schemes, which always validate.
+var builtinCategories, _ = category.NewRegistry(category.DefaultSchemeName)
+
+// Values of the detail query parameter
+const (
+	DetailBasic = "basic" // Weather condition and temperature category only (default)
+	DetailFull  = "full"  // Also include the full current conditions
 )
 
 // WeatherHandler handles weather-related HTTP requests by fetching weather data and using the application's configuration.
 type WeatherHandler struct {
-	Repo   repo.WeatherAPI   // Interface for fetching weather data
-	Config *config.AppConfig // Application configuration settings
+	Repo       repo.WeatherAPI    // Interface for fetching weather data
+	OneCall    repo.OneCallAPI    // Used instead of Repo when Config.CurrentFromOneCall is set
+	Config     *config.AppConfig  // Application configuration settings
+	Categories *category.Registry // Temperature category schemes selectable with ?scheme=
 }
 
 // NewWeatherHandler creates a WeatherHandler with given weather data repository and configuration for easier testing.
+// It starts with the builtin category schemes; replace Categories to add configured ones.
 func NewWeatherHandler(repo repo.WeatherAPI, cfg *config.AppConfig) *WeatherHandler {
-	return &WeatherHandler{Repo: repo, Config: cfg}
+	return &WeatherHandler{Repo: repo, Config: cfg, Categories: builtinCategories}
 }
 
 // GetWeatherConditionByCoordinates handles HTTP requests for weather conditions by coordinates.
 func (h *WeatherHandler) GetWeatherConditionByCoordinates(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	query := r.URL.Query()
-	lat := query.Get("lat")
-	lon := query.Get("lon")
+	coords, err := parseCoordinates(query)
+	if err != nil {
+		writeCoordinatesError(w, err)
+		return
+	}
+	lat, lon := coords.latText, coords.lonText
+
+	detail := query.Get("detail")
+	if detail != "" && detail != DetailBasic && detail != DetailFull {
+		http.Error(w, "Invalid query parameter: detail must be 'basic' or 'full'", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := parseBool(query, "summary")
+	if err != nil {
+		http.Error(w, "Invalid query parameter: summary must be 'true' or 'false'", http.StatusBadRequest)
+		return
+	}
+
+	scheme, err := h.Categories.Lookup(query.Get("scheme"))
+	if err != nil {
+		http.Error(w, "Invalid scheme: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := parseOutputUnits(query, h.Config.UnitOfMeasurement)
+	if err != nil {
+		http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Validate query parameters
-	if lat == "" || lon == "" {
-		http.Error(w, "Missing required query parameters: lat and/or lon", http.StatusBadRequest)
+	lang, err := requestLanguage(r)
+	if err != nil {
+		http.Error(w, "Invalid lang: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	ctx := i18n.NewContext(r.Context(), lang)
 
-	// Call the OpenWeather API using the fetcher
-	weatherData, err := h.Repo.FetchWeatherData(r.Context(), lat, lon, h.Config.OpenWeatherMapAPIURL, h.Config.UnitOfMeasurement)
+	// Upstream data is always fetched in the canonical unit system and converted on output
+	weatherData, err := h.fetchCurrent(ctx, lat, lon)
 	if err != nil {
+		logger.WarnContext(r.Context(), "failed to fetch weather data", "lat", lat, "lon", lon, "error", err)
 		handleWeatherDataError(err, w)
 		return
 	}
 
 	// Map the API response to the response model
-	response := MapWeatherDataToResponse(weatherData, h.Config.UnitOfMeasurement)
+	response := MapWeatherDataToResponse(weatherData, units.CanonicalSystem)
+	response.TempCategory = categorizeWeather(weatherData, units.CanonicalSystem, scheme)
+	if detail == DetailFull {
+		details := MapWeatherDataToDetails(weatherData, units.CanonicalSystem, output)
+		response.Details = &details
+	}
+	response.Alerts = mapOneCallAlerts(weatherData.Alerts, time.Now())
+	localizeResponse(&response, lang)
+	if summary {
+		response.Summary = summarize(weatherData, response, units.CanonicalSystem, output, lang)
+	}
 
 	// Respond to the client with the weather condition and temperature category
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(lang))
 	json.NewEncoder(w).Encode(response)
 }
 
+// fetchCurrent fetches current conditions in the canonical unit system from the One Call API when configured,
+// which also reports alerts, and otherwise from the current weather API.
+func (h *WeatherHandler) fetchCurrent(ctx context.Context, lat, lon string) (model.WeatherData, error) {
+	if !h.Config.CurrentFromOneCall || h.OneCall == nil {
+		return h.Repo.FetchWeatherData(ctx, lat, lon, h.Config.OpenWeatherMapAPIURL, units.CanonicalSystem)
+	}
+
+	oneCall, err := h.OneCall.FetchOneCall(ctx, lat, lon, h.Config.OneCallURL, units.CanonicalSystem, repo.ExcludeAllBut(repo.BlockCurrent, repo.BlockAlerts))
+	if err != nil {
+		return model.WeatherData{}, err
+	}
+	data, ok := oneCall.CurrentWeatherData()
+	if !ok {
+		return data, fmt.Errorf("%w: One Call response has no current conditions", repo.ErrDecodingResponse)
+	}
+	return data, nil
+}
+
+// requestLanguage returns the language selected with the lang query parameter, or else negotiated from
+// the Accept-Language header.
+func requestLanguage(r *http.Request) (i18n.Language, error) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return i18n.Parse(lang)
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language")), nil
+}
+
+// localizeResponse translates the weather condition, temperature category and any condition descriptions
+// that OpenWeatherMap returned in English. Machine-readable fields stay in English.
+func localizeResponse(response *model.WeatherResponse, lang i18n.Language) {
+	response.WeatherCondition = i18n.Condition(lang, response.WeatherCondition)
+	response.TempCategory = i18n.Category(lang, response.TempCategory)
+
+	for i, condition := range response.Conditions {
+		if english, ok := taxonomy.Lookup(condition.Code); ok && condition.Description == english.Description {
+			response.Conditions[i].Description = i18n.Description(lang, condition.Code, condition.Description)
+		}
+	}
+}
+
 // mapWeatherDataToResponse maps data from the OpenWeather API to the custom response format.
 func MapWeatherDataToResponse(data model.WeatherData, unitOfMeasurement string) model.WeatherResponse {
 	condition := "Unknown" // Default condition if none is found
@@ -57,31 +164,312 @@ func MapWeatherDataToResponse(data model.WeatherData, unitOfMeasurement string)
 		condition = data.Weather[0].Main
 	}
 
-	tempFahrenheit := util.ConvertTempToFahrenheit(data.Main.Temp, unitOfMeasurement)
+	upstream, err := units.Upstream(unitOfMeasurement)
+	if err != nil {
+		upstream.Temperature = units.Fahrenheit // Unknown systems have always been read as Fahrenheit
+	}
+	tempFahrenheit, _ := units.ConvertTemperature(data.Main.Temp, upstream.Temperature, units.Fahrenheit)
 	tempCategory := CategorizeTemperature(tempFahrenheit)
 
 	return model.WeatherResponse{
 		WeatherCondition: condition,
 		TempCategory:     tempCategory,
+		Conditions:       mapConditions(data.Weather),
+		IsDaytime:        isDaytime(data),
+		LocalTime:        mapLocalTime(data),
 	}
 }
 
-// CategorizeTemperature categorizes the temperature into human-readable form.
-func CategorizeTemperature(tempFahrenheit float64) string {
+// isDaytime reports whether the observation falls between sunrise and sunset. Without sunrise and sunset,
+// as during polar day and night, it falls back to the day or night variant of the condition icon.
+func isDaytime(data model.WeatherData) *bool {
+	var daytime bool
 	switch {
-	case tempFahrenheit <= 32:
-		return "Freezing"
-	case tempFahrenheit > 32 && tempFahrenheit <= 50:
-		return "Cold"
-	case tempFahrenheit > 50 && tempFahrenheit <= 68:
-		return "Cool"
-	case tempFahrenheit > 68 && tempFahrenheit <= 77:
-		return "Mild"
-	case tempFahrenheit > 77 && tempFahrenheit <= 95:
-		return "Warm"
+	case data.Dt != 0 && data.Sys.Sunrise != 0 && data.Sys.Sunset != 0:
+		daytime = data.Dt >= data.Sys.Sunrise && data.Dt < data.Sys.Sunset
+	case len(data.Weather) > 0 && strings.HasSuffix(data.Weather[0].Icon, "d"):
+		daytime = true
+	case len(data.Weather) > 0 && strings.HasSuffix(data.Weather[0].Icon, "n"):
+		daytime = false
 	default:
-		return "Hot"
+		return nil
+	}
+	return &daytime
+}
+
+// mapLocalTime converts the observation, sunrise and sunset times to the location's timezone.
+func mapLocalTime(data model.WeatherData) *model.LocalTime {
+	if data.Dt == 0 {
+		return nil
+	}
+
+	zone := time.FixedZone("", data.Timezone)
+	return &model.LocalTime{
+		ObservedAt:       time.Unix(data.Dt, 0).In(zone),
+		UTCOffset:        formatUTCOffset(data.Timezone),
+		UTCOffsetSeconds: data.Timezone,
+		Sunrise:          eventTime(data.Sys.Sunrise, zone),
+		Sunset:           eventTime(data.Sys.Sunset, zone),
+	}
+}
+
+func eventTime(seconds int64, zone *time.Location) *model.EventTime {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0)
+	return &model.EventTime{UTC: t.UTC(), Local: t.In(zone)}
+}
+
+// formatUTCOffset formats an offset in seconds east of UTC as ISO-8601, e.g. "+05:30".
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// mapConditions classifies every reported condition. Entries without a condition code are skipped.
+func mapConditions(weather []model.WeatherCondition) []taxonomy.Condition {
+	var conditions []taxonomy.Condition
+	for _, w := range weather {
+		if w.ID == 0 {
+			continue
+		}
+		conditions = append(conditions, taxonomy.Classify(w.ID, w.Description, w.Icon))
+	}
+	return conditions
+}
+
+// parseOutputUnits reads the units query parameter, defaulting to defaultSystem, and applies the
+// per-quantity overrides temp, wind, pressure, distance and precip.
+func parseOutputUnits(query url.Values, defaultSystem string) (units.Set, error) {
+	system := query.Get("units")
+	if system == "" {
+		system = defaultSystem
+	}
+
+	set, err := units.ForSystem(system)
+	if err != nil {
+		return set, err
+	}
+
+	return set.WithOverrides(query.Get("temp"), query.Get("wind"), query.Get("pressure"), query.Get("distance"), query.Get("precip"))
+}
+
+// parseBool reads an optional boolean query parameter, which defaults to false.
+func parseBool(query url.Values, name string) (bool, error) {
+	if value := query.Get(name); value != "" {
+		return strconv.ParseBool(value)
+	}
+	return false, nil
+}
+
+// summarize builds the localized one-sentence summary of an already localized response. Temperatures and
+// wind speed are given in the output units, rounded to whole numbers.
+func summarize(data model.WeatherData, response model.WeatherResponse, unitOfMeasurement string, output units.Set, lang i18n.Language) string {
+	upstream, _ := units.Upstream(unitOfMeasurement)
+	c := converter{from: upstream, to: output}
+
+	facts := i18n.SummaryFacts{
+		Condition:     response.WeatherCondition,
+		Category:      response.TempCategory,
+		Temperature:   formatQuantity(c.temperature(data.Main.Temp), output.Temperature.Symbol()),
+		FeelsLike:     formatQuantity(c.temperature(data.Main.FeelsLike), output.Temperature.Symbol()),
+		WindDirection: util.CompassPoint(data.Wind.Deg),
+	}
+	if len(response.Conditions) > 0 && response.Conditions[0].Description != "" {
+		facts.Condition = response.Conditions[0].Description
+	}
+	if facts.FeelsLike == facts.Temperature {
+		facts.FeelsLike = ""
+	}
+
+	// Force 0 on the Beaufort scale is reported as calm rather than with a direction
+	if force, err := (units.Speed{Value: data.Wind.Speed, Unit: upstream.Speed}).BeaufortForce(); err == nil && force > 0 {
+		facts.WindSpeed = formatQuantity(c.speed(data.Wind.Speed), output.Speed.Symbol())
+	}
+	return i18n.Summary(lang, facts)
+}
+
+// formatQuantity formats a value rounded to a whole number with its unit symbol, e.g. "12°C" or "20 km/h".
+func formatQuantity(value float64, symbol string) string {
+	number := strconv.Itoa(int(math.Round(value)))
+	if strings.HasPrefix(symbol, "°") {
+		return number + symbol
+	}
+	return number + " " + symbol
+}
+
+// MapWeatherDataToDetails maps the full current conditions from the OpenWeather API, reported in the
+// unitOfMeasurement system, to the details response converted to the requested units.
+func MapWeatherDataToDetails(data model.WeatherData, unitOfMeasurement string, output units.Set) model.WeatherDetails {
+	upstream, _ := units.Upstream(unitOfMeasurement)
+	c := converter{from: upstream, to: output}
+
+	details := model.WeatherDetails{
+		Units: model.UnitLabels{
+			Temperature:   output.Temperature.Symbol(),
+			Speed:         output.Speed.Symbol(),
+			Pressure:      output.Pressure.Symbol(),
+			Distance:      output.Distance.Symbol(),
+			Precipitation: output.Precipitation.Symbol(),
+		},
+		Temperature: model.TemperatureDetails{
+			Current:   c.temperature(data.Main.Temp),
+			FeelsLike: c.temperature(data.Main.FeelsLike),
+			Min:       c.temperature(data.Main.TempMin),
+			Max:       c.temperature(data.Main.TempMax),
+		},
+		Pressure: model.PressureDetails{
+			Current:     c.pressure(data.Main.Pressure),
+			SeaLevel:    c.optional(data.Main.SeaLevel, c.pressure),
+			GroundLevel: c.optional(data.Main.GroundLevel, c.pressure),
+		},
+		Humidity:   data.Main.Humidity,
+		Visibility: c.optional(data.Visibility, c.distance),
+		Wind: model.WindDetails{
+			Speed:     c.speed(data.Wind.Speed),
+			Direction: data.Wind.Deg,
+			Gust:      c.optional(data.Wind.Gust, c.speed),
+		},
+		Cloudiness:     data.Clouds.All,
+		ObservedAt:     unixTime(data.Dt),
+		Sunrise:        unixTime(data.Sys.Sunrise),
+		Sunset:         unixTime(data.Sys.Sunset),
+		TimezoneOffset: data.Timezone,
+		Station:        data.Name,
+		Country:        data.Sys.Country,
+		Coordinates:    data.Coord,
+		Derived:        mapDerivedIndices(data, c),
+	}
+
+	if data.Rain != nil {
+		details.Precipitation.Rain1h = c.optional(data.Rain.OneHour, c.precipitation)
+		details.Precipitation.Rain3h = c.optional(data.Rain.ThreeHours, c.precipitation)
+	}
+	if data.Snow != nil {
+		details.Precipitation.Snow1h = c.optional(data.Snow.OneHour, c.precipitation)
+		details.Precipitation.Snow3h = c.optional(data.Snow.ThreeHours, c.precipitation)
+	}
+
+	return details
+}
+
+// mapDerivedIndices computes the derived indices from the upstream values and converts them to the output
+// temperature unit.
+func mapDerivedIndices(data model.WeatherData, c converter) model.DerivedIndices {
+	tempC, _ := units.ConvertTemperature(data.Main.Temp, c.from.Temperature, units.Celsius)
+	windSpeed, _ := units.ConvertSpeed(data.Wind.Speed, c.from.Speed, units.MetersPerSecond)
+	humidity := data.Main.Humidity
+
+	var derived model.DerivedIndices
+	if chill, ok := util.WindChill(tempC, windSpeed); ok {
+		derived.WindChill = c.celsius(chill)
+	}
+	if humidity <= 0 {
+		return derived
+	}
+
+	derived.DewPoint = c.celsius(util.DewPoint(tempC, humidity))
+	derived.HeatIndex = c.celsius(util.HeatIndex(tempC, humidity))
+	derived.ApparentTemperature = c.celsius(util.ApparentTemperature(tempC, humidity, windSpeed))
+	derived.Humidex = c.celsius(util.Humidex(tempC, humidity))
+	if wetBulb, ok := util.WetBulb(tempC, humidity); ok {
+		derived.WetBulb = c.celsius(wetBulb)
 	}
+	return derived
+}
+
+// converter converts values from the units they were fetched in to the requested output units.
+// Units are validated when the request is parsed, so conversion errors cannot occur here.
+type converter struct {
+	from, to units.Set
+}
+
+func (c converter) temperature(value float64) float64 {
+	converted, _ := units.ConvertTemperature(value, c.from.Temperature, c.to.Temperature)
+	return units.Round(converted, 2)
+}
+
+// celsius converts a computed temperature in °C to the output unit.
+func (c converter) celsius(value float64) *float64 {
+	converted, _ := units.ConvertTemperature(value, units.Celsius, c.to.Temperature)
+	converted = units.Round(converted, 2)
+	return &converted
+}
+
+func (c converter) speed(value float64) float64 {
+	converted, _ := units.ConvertSpeed(value, c.from.Speed, c.to.Speed)
+	return units.Round(converted, 2)
+}
+
+func (c converter) pressure(value float64) float64 {
+	converted, _ := units.ConvertPressure(value, c.from.Pressure, c.to.Pressure)
+	return units.Round(converted, 2)
+}
+
+func (c converter) distance(value float64) float64 {
+	converted, _ := units.ConvertDistance(value, c.from.Distance, c.to.Distance)
+	return units.Round(converted, 2)
+}
+
+func (c converter) precipitation(value float64) float64 {
+	converted, _ := units.ConvertPrecipitation(value, c.from.Precipitation, c.to.Precipitation)
+	return units.Round(converted, 2)
+}
+
+// optional converts a value that OpenWeatherMap may omit.
+func (c converter) optional(value *float64, convert func(float64) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	converted := convert(*value)
+	return &converted
+}
+
+// unixTime converts a Unix timestamp to UTC time, or nil if the timestamp was not reported.
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+// CategorizeTemperature categorizes a Fahrenheit temperature with the default scheme's six bands.
+func CategorizeTemperature(tempFahrenheit float64) string {
+	scheme, _ := builtinCategories.Lookup(category.DefaultSchemeName)
+	name, _ := scheme.Categorize(units.Temperature{Value: tempFahrenheit, Unit: units.Fahrenheit})
+	return name
+}
+
+// categorizeWeather categorizes the air or feels-like temperature, as the scheme requires, of data fetched
+// in the unitOfMeasurement system.
+func categorizeWeather(data model.WeatherData, unitOfMeasurement string, scheme category.Scheme) string {
+	upstream, _ := units.Upstream(unitOfMeasurement)
+	return categorizeTemperatures(data.Main.Temp, data.Main.FeelsLike, upstream.Temperature, scheme)
+}
+
+// categorizeTemperatures categorizes the air or feels-like temperature, as the scheme requires.
+func categorizeTemperatures(temperature, feelsLike float64, unit units.TemperatureUnit, scheme category.Scheme) string {
+	if scheme.Basis == category.BasisFeelsLike {
+		temperature = feelsLike
+	}
+	name, _ := scheme.Categorize(units.Temperature{Value: temperature, Unit: unit})
+	return name
+}
+
+// ListCategorySchemes handles HTTP requests for the available temperature category schemes.
+func (h *WeatherHandler) ListCategorySchemes(w http.ResponseWriter, r *http.Request) {
+	response := model.CategorySchemesResponse{
+		Default: h.Categories.DefaultName(),
+		Schemes: h.Categories.Schemes(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 // handleWeatherDataError handles errors returned by FetchWeatherData.
@@ -1,16 +1,27 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
 	"github.com/golang2go/demo-app/weather-service-api/internal/config"
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
+	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
 	"github.com/golang2go/demo-app/weather-service-api/internal/model"
 	"github.com/golang2go/demo-app/weather-service-api/internal/repo"
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,6 +74,12 @@ func TestWeatherHandler_GetWeatherConditionByCoordinates_MissingParams(t *testin
 		{"Missing Lat", "/weather?lon=139", http.StatusBadRequest, "Missing required query parameters: lat and/or lon\n"},
 		{"Missing Lon", "/weather?lat=35", http.StatusBadRequest, "Missing required query parameters: lat and/or lon\n"},
 		{"Missing Both", "/weather", http.StatusBadRequest, "Missing required query parameters: lat and/or lon\n"},
+		{"Lat Not A Number", "/weather?lat=north&lon=139", http.StatusBadRequest, "Invalid query parameters: lat must be a number between -90 and 90, got \"north\"\n"},
+		{"Lon Out Of Range", "/weather?lat=35&lon=200", http.StatusBadRequest, "Invalid query parameters: lon must be a number between -180 and 180, got \"200\"\n"},
+		{"Lat NaN", "/weather?lat=NaN&lon=139", http.StatusBadRequest, "Invalid query parameters: lat must be a number between -90 and 90, got \"NaN\"\n"},
+		{"Lon NaN", "/weather?lat=35&lon=nan", http.StatusBadRequest, "Invalid query parameters: lon must be a number between -180 and 180, got \"nan\"\n"},
+		{"Lat Infinite", "/weather?lat=-Inf&lon=139", http.StatusBadRequest, "Invalid query parameters: lat must be a number between -90 and 90, got \"-Inf\"\n"},
+		{"Lon Infinite", "/weather?lat=35&lon=Infinity", http.StatusBadRequest, "Invalid query parameters: lon must be a number between -180 and 180, got \"Infinity\"\n"},
 	}
 
 	for _, tc := range testCases {
@@ -258,3 +275,554 @@ func TestHandleWeatherDataError(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_LogsDoNotLeakAPIKey(t *testing.T) {
+	// Capture the log output, including the repo's debug lines with the upstream URL
+	var logOutput bytes.Buffer
+	logging.Init(&logOutput, logging.Options{})
+	logging.SetLevel("repo", slog.LevelDebug)
+	defer logging.Init(os.Stderr, logging.Options{})
+	defer logging.SetLevel("repo", slog.LevelInfo)
+
+	// A closed upstream makes the HTTP client fail with an error that embeds the request URL
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: upstream.URL,
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(repo.NewWeatherAPI(), cfg)
+
+	apiKey := "super-secret-api-key"
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.APIKeyContextKey("apiKey"), apiKey))
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.NotEmpty(t, logOutput.String(), "expected the upstream failure to be logged")
+	assert.NotContains(t, logOutput.String(), apiKey, "log output leaks the API key")
+	assert.NotContains(t, rr.Body.String(), apiKey, "response body leaks the API key")
+}
+
+// canonicalWeatherData returns upstream data as it is fetched in the canonical (standard) unit system.
+func canonicalWeatherData() model.WeatherData {
+	gust := 10.0
+	rain := 0.8
+	return model.WeatherData{
+		Coord:    model.Coordinates{Lat: 35, Lon: 139},
+		Weather:  []model.WeatherCondition{{ID: 500, Main: "Rain", Description: "light rain", Icon: "10d"}},
+		Main:     model.MainInfo{Temp: 288.15, FeelsLike: 287.15, TempMin: 285.15, TempMax: 290.15, Pressure: 1012, Humidity: 80},
+		Wind:     model.Wind{Speed: 5, Deg: 315, Gust: &gust},
+		Clouds:   model.Clouds{All: 75},
+		Rain:     &model.Precipitation{OneHour: &rain},
+		Dt:       1700000000,
+		Sys:      model.SysInfo{Country: "JP", Sunrise: 1699995600, Sunset: 1700033400},
+		Timezone: 32400,
+		Name:     "Shibuya",
+	}
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_DetailFull(t *testing.T) {
+	mockAPI := &MockWeatherAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+			if unitsOfMeasurement != "standard" {
+				t.Errorf("upstream fetched in %q units, want the canonical standard units", unitsOfMeasurement)
+			}
+			return canonicalWeatherData(), nil
+		},
+	}
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "imperial",
+	}
+	h := NewWeatherHandler(mockAPI, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&detail=full", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expected := `{
+		"weatherCondition": "Rain",
+		"tempCategory": "Cool",
+		"conditions": [
+			{"code": 500, "group": "rain", "description": "light rain", "intensity": "light", "precipitation": "rain", "thunderstorm": false, "variant": "day", "icon": "10d", "severity": 1}
+		],
+		"isDaytime": true,
+		"localTime": {
+			"observedAt": "2023-11-15T07:13:20+09:00",
+			"utcOffset": "+09:00",
+			"utcOffsetSeconds": 32400,
+			"sunrise": {"utc": "2023-11-14T21:00:00Z", "local": "2023-11-15T06:00:00+09:00"},
+			"sunset": {"utc": "2023-11-15T07:30:00Z", "local": "2023-11-15T16:30:00+09:00"}
+		},
+		"details": {
+			"units": {"temperature": "°F", "speed": "mph", "pressure": "inHg", "distance": "mi", "precipitation": "in"},
+			"temperature": {"current": 59, "feelsLike": 57.2, "min": 53.6, "max": 62.6},
+			"pressure": {"current": 29.88},
+			"humidity": 80,
+			"wind": {"speed": 11.18, "direction": 315, "gust": 22.37},
+			"cloudiness": 75,
+			"precipitation": {"rain1h": 0.03},
+			"derived": {"dewPoint": 52.84, "heatIndex": 58.36, "apparentTemperature": 53.59, "humidex": 62.67, "wetBulb": 54.91},
+			"observedAt": "2023-11-14T22:13:20Z",
+			"sunrise": "2023-11-14T21:00:00Z",
+			"sunset": "2023-11-15T07:30:00Z",
+			"timezoneOffset": 32400,
+			"station": "Shibuya",
+			"country": "JP",
+			"coordinates": {"lat": 35, "lon": 139}
+		}
+	}`
+	assert.JSONEq(t, expected, rr.Body.String())
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_PerRequestUnits(t *testing.T) {
+	mockAPI := &MockWeatherAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+			return canonicalWeatherData(), nil
+		},
+	}
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "imperial",
+	}
+	h := NewWeatherHandler(mockAPI, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&detail=full&units=metric&wind=kmh&pressure=mmHg", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response model.WeatherResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "Cool", response.TempCategory, "category must not depend on the output units")
+	assert.Equal(t, model.UnitLabels{Temperature: "°C", Speed: "km/h", Pressure: "mmHg", Distance: "m", Precipitation: "mm"}, response.Details.Units)
+	assert.Equal(t, 15.0, response.Details.Temperature.Current)
+	assert.Equal(t, 18.0, response.Details.Wind.Speed)
+	assert.Equal(t, 759.06, response.Details.Pressure.Current)
+	assert.Equal(t, 0.8, *response.Details.Precipitation.Rain1h)
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_InvalidUnits(t *testing.T) {
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+
+	for _, query := range []string{"units=nautical", "temp=R2", "wind=furlongs", "pressure=bar"} {
+		t.Run(query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&"+query, nil)
+			rr := httptest.NewRecorder()
+			h.GetWeatherConditionByCoordinates(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_InvalidDetail(t *testing.T) {
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&detail=everything", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMapDerivedIndices(t *testing.T) {
+	output, _ := units.ForSystem("metric")
+	upstream, _ := units.Upstream(units.CanonicalSystem)
+	c := converter{from: upstream, to: output}
+
+	// -5 °C with 10 m/s wind and no reported humidity: only the wind chill can be computed
+	data := model.WeatherData{Main: model.MainInfo{Temp: 268.15}, Wind: model.Wind{Speed: 10}}
+	derived := mapDerivedIndices(data, c)
+
+	if assert.NotNil(t, derived.WindChill) {
+		assert.Equal(t, -13.65, *derived.WindChill)
+	}
+	assert.Nil(t, derived.DewPoint)
+	assert.Nil(t, derived.HeatIndex)
+	assert.Nil(t, derived.ApparentTemperature)
+	assert.Nil(t, derived.Humidex)
+	assert.Nil(t, derived.WetBulb)
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_Scheme(t *testing.T) {
+	mockAPI := &MockWeatherAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+			return canonicalWeatherData(), nil // 15 °C, feeling like 14 °C
+		},
+	}
+
+	// The same threshold categorizes air and feels-like temperature differently
+	upTo := 14.5
+	bands := []category.Band{{Name: "Chilly", UpTo: &upTo}, {Name: "Fine"}}
+	categories, err := category.NewRegistry("",
+		category.Scheme{Name: "air", Unit: units.Celsius, Basis: category.BasisAir, Bands: bands},
+		category.Scheme{Name: "feels", Unit: units.Celsius, Basis: category.BasisFeelsLike, Bands: bands},
+	)
+	assert.NoError(t, err)
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "imperial",
+	}
+	h := NewWeatherHandler(mockAPI, cfg)
+	h.Categories = categories
+
+	tests := []struct {
+		scheme   string
+		expected string
+	}{
+		{"", "Cool"},
+		{"simple", "Moderate"},
+		{"nordic", "Cool"},
+		{"air", "Fine"},
+		{"feels", "Chilly"},
+	}
+
+	for _, tc := range tests {
+		req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&scheme="+tc.scheme, nil)
+		rr := httptest.NewRecorder()
+		h.GetWeatherConditionByCoordinates(rr, req)
+
+		var response model.WeatherResponse
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, tc.expected, response.TempCategory, "scheme %q", tc.scheme)
+	}
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_InvalidScheme(t *testing.T) {
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&scheme=arctic", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `unknown category scheme "arctic"`)
+}
+
+func TestWeatherHandler_ListCategorySchemes(t *testing.T) {
+	h := NewWeatherHandler(&MockWeatherAPI{}, &config.AppConfig{})
+
+	req, _ := http.NewRequest("GET", "/categories", nil)
+	rr := httptest.NewRecorder()
+	h.ListCategorySchemes(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response model.CategorySchemesResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "default", response.Default)
+	if assert.Len(t, response.Schemes, 4) {
+		assert.Equal(t, "default", response.Schemes[0].Name)
+		assert.Equal(t, "Freezing", response.Schemes[0].Bands[0].Name)
+		assert.Equal(t, 32.0, *response.Schemes[0].Bands[0].UpTo)
+		assert.Nil(t, response.Schemes[0].Bands[5].UpTo)
+		assert.Equal(t, category.BasisFeelsLike, response.Schemes[2].Basis)
+	}
+}
+
+func TestMapWeatherDataToResponse_Conditions(t *testing.T) {
+	data := model.WeatherData{
+		Main: model.MainInfo{Temp: 275.15},
+		Weather: []model.WeatherCondition{
+			{ID: 211, Main: "Thunderstorm", Description: "thunderstorm", Icon: "11n"},
+			{ID: 615, Main: "Snow", Description: "light rain and snow", Icon: "13n"},
+			{ID: 741, Main: "Fog", Description: "fog", Icon: "50n"},
+		},
+	}
+
+	response := MapWeatherDataToResponse(data, "standard")
+
+	assert.Equal(t, "Thunderstorm", response.WeatherCondition)
+	if assert.Len(t, response.Conditions, 3) {
+		assert.Equal(t, taxonomy.GroupThunderstorm, response.Conditions[0].Group)
+		assert.True(t, response.Conditions[0].Thunderstorm)
+		assert.Equal(t, 4, response.Conditions[0].Severity)
+		assert.Equal(t, taxonomy.PrecipitationRainAndSnow, response.Conditions[1].Precipitation)
+		assert.Equal(t, taxonomy.IntensityLight, response.Conditions[1].Intensity)
+		assert.Equal(t, taxonomy.GroupAtmosphere, response.Conditions[2].Group)
+		assert.Equal(t, taxonomy.VariantNight, response.Conditions[2].Variant)
+	}
+
+	// Entries without a condition code are not classified
+	response = MapWeatherDataToResponse(model.WeatherData{Weather: []model.WeatherCondition{{Main: "Clear"}}}, "standard")
+	assert.Empty(t, response.Conditions)
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_Language(t *testing.T) {
+	var fetchedLang i18n.Language
+	mockAPI := &MockWeatherAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+			fetchedLang = i18n.FromContext(ctx)
+			data := canonicalWeatherData()
+			if fetchedLang == i18n.German {
+				data.Weather[0].Description = "Leichter Regen" // OpenWeatherMap localizes descriptions itself
+			}
+			return data, nil
+		},
+	}
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "imperial",
+	}
+	h := NewWeatherHandler(mockAPI, cfg)
+
+	tests := []struct {
+		name                string
+		query               string
+		acceptLanguage      string
+		expectedLang        i18n.Language
+		expectedCondition   string
+		expectedCategory    string
+		expectedDescription string
+	}{
+		{"Default", "", "", i18n.English, "Rain", "Cool", "light rain"},
+		{"Accept-Language", "", "fr-FR, es;q=0.8", i18n.Spanish, "Lluvia", "Fresco", "lluvia ligera"},
+		{"Override", "&lang=de", "es", i18n.German, "Regen", "Kühl", "Leichter Regen"},
+		{"Japanese", "&lang=ja-JP", "", i18n.Japanese, "雨", "涼しい", "小雨"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139"+tc.query, nil)
+			if tc.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			rr := httptest.NewRecorder()
+			h.GetWeatherConditionByCoordinates(rr, req)
+
+			var response model.WeatherResponse
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedLang, fetchedLang)
+			assert.Equal(t, string(tc.expectedLang), rr.Header().Get("Content-Language"))
+			assert.Equal(t, tc.expectedCondition, response.WeatherCondition)
+			assert.Equal(t, tc.expectedCategory, response.TempCategory)
+			if assert.Len(t, response.Conditions, 1) {
+				assert.Equal(t, tc.expectedDescription, response.Conditions[0].Description)
+				assert.Equal(t, taxonomy.GroupRain, response.Conditions[0].Group)
+			}
+		})
+	}
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_InvalidLanguage(t *testing.T) {
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&lang=fr", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestIsDaytime(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     model.WeatherData
+		expected *bool
+	}{
+		{"Before sunrise", model.WeatherData{Dt: 900, Sys: model.SysInfo{Sunrise: 1000, Sunset: 2000}}, boolPtr(false)},
+		{"At sunrise", model.WeatherData{Dt: 1000, Sys: model.SysInfo{Sunrise: 1000, Sunset: 2000}}, boolPtr(true)},
+		{"At sunset", model.WeatherData{Dt: 2000, Sys: model.SysInfo{Sunrise: 1000, Sunset: 2000}}, boolPtr(false)},
+		{"Polar day from icon", model.WeatherData{Dt: 1000, Weather: []model.WeatherCondition{{Icon: "01d"}}}, boolPtr(true)},
+		{"Polar night from icon", model.WeatherData{Dt: 1000, Weather: []model.WeatherCondition{{Icon: "13n"}}}, boolPtr(false)},
+		{"Unknown", model.WeatherData{Weather: []model.WeatherCondition{{Main: "Clear"}}}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isDaytime(tc.data))
+		})
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestMapLocalTime(t *testing.T) {
+	// Kathmandu is 5:45 ahead of UTC; New York in winter 5 hours behind
+	local := mapLocalTime(model.WeatherData{Dt: 1700000000, Timezone: 20700})
+	if assert.NotNil(t, local) {
+		assert.Equal(t, "+05:45", local.UTCOffset)
+		assert.Equal(t, "2023-11-15T03:58:20+05:45", local.ObservedAt.Format(time.RFC3339))
+		assert.Nil(t, local.Sunrise)
+		assert.Nil(t, local.Sunset)
+	}
+
+	local = mapLocalTime(model.WeatherData{Dt: 1700000000, Timezone: -18000, Sys: model.SysInfo{Sunrise: 1699961400}})
+	if assert.NotNil(t, local) {
+		assert.Equal(t, "-05:00", local.UTCOffset)
+		assert.Equal(t, "2023-11-14T06:30:00-05:00", local.Sunrise.Local.Format(time.RFC3339))
+		assert.Equal(t, "2023-11-14T11:30:00Z", local.Sunrise.UTC.Format(time.RFC3339))
+	}
+
+	assert.Nil(t, mapLocalTime(model.WeatherData{}))
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_Summary(t *testing.T) {
+	mockAPI := &MockWeatherAPI{
+		FetchFunc: func(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+			return canonicalWeatherData(), nil
+		},
+	}
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "imperial",
+	}
+	h := NewWeatherHandler(mockAPI, cfg)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"Omitted By Default", "", ""},
+		{"Disabled", "&summary=false", ""},
+		{"Imperial", "&summary=true", "Light rain, cool at 59°F, feels like 57°F, winds 11 mph from the northwest"},
+		{"Metric Kilometers", "&summary=1&units=metric&wind=kmh", "Light rain, cool at 15°C, feels like 14°C, winds 18 km/h from the northwest"},
+		{"Localized", "&summary=true&units=metric&lang=es", "Lluvia ligera, fresco con 15°C, sensación térmica de 14°C, viento de 5 m/s del noroeste"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			h.GetWeatherConditionByCoordinates(rr, req)
+
+			var response model.WeatherResponse
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tc.expected, response.Summary)
+		})
+	}
+}
+
+func TestSummarize_CalmWind(t *testing.T) {
+	data := canonicalWeatherData()
+	data.Wind = model.Wind{Speed: 0.2, Deg: 90}
+	data.Main.FeelsLike = data.Main.Temp
+	response := MapWeatherDataToResponse(data, units.CanonicalSystem)
+	response.TempCategory = "Cool"
+
+	metric, _ := units.ForSystem(units.Metric)
+	summary := summarize(data, response, units.CanonicalSystem, metric, i18n.English)
+	assert.Equal(t, "Light rain, cool at 15°C, calm winds", summary)
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_InvalidSummary(t *testing.T) {
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		UnitOfMeasurement:    "standard",
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&summary=maybe", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid query parameter: summary must be 'true' or 'false'\n", rr.Body.String())
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_OneCall(t *testing.T) {
+	gust := 9.0
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			assert.Equal(t, "http://example.com/onecall", openWeatherMapOneCallURL)
+			assert.Equal(t, units.CanonicalSystem, unitsOfMeasurement)
+			assert.ElementsMatch(t, []repo.OneCallBlock{repo.BlockMinutely, repo.BlockHourly, repo.BlockDaily}, exclude)
+			return model.OneCallData{
+				Lat: 35, Lon: 139, TimezoneOffset: 32400,
+				Current: &model.OneCallCurrent{
+					Dt: 1700000000, Sunrise: 1699995600, Sunset: 1700033400,
+					Temp: 288.15, FeelsLike: 287.15, Pressure: 1012, Humidity: 80,
+					WindSpeed: 5, WindDeg: 315, WindGust: &gust,
+					Weather: []model.WeatherCondition{{ID: 502, Main: "Rain", Description: "heavy intensity rain", Icon: "10d"}},
+				},
+				Alerts: []model.OneCallAlert{{
+					SenderName: "Japan Meteorological Agency", Event: "Heavy Rain Warning",
+					Start: 1700000000, End: 1700036000, Description: "Heavy rain expected.", Tags: []string{"Rain"},
+				}},
+			}, nil
+		},
+	}
+
+	cfg := &config.AppConfig{
+		OpenWeatherMapAPIURL: "http://example.com",
+		OneCallURL:           "http://example.com/onecall",
+		UnitOfMeasurement:    "metric",
+		CurrentFromOneCall:   true,
+	}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg) // The current weather API must not be called
+	h.OneCall = oneCall
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139&detail=full", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	var response model.WeatherResponse
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "Rain", response.WeatherCondition)
+	assert.Equal(t, 15.0, response.Details.Temperature.Current)
+	assert.Equal(t, "+09:00", response.LocalTime.UTCOffset)
+	assert.Equal(t, []model.WeatherAlert{{
+		Source:      model.AlertSourceOpenWeatherMap,
+		Sender:      "Japan Meteorological Agency",
+		Event:       "Heavy Rain Warning",
+		Severity:    "Severe",
+		Urgency:     "Past",
+		Certainty:   "Likely",
+		Onset:       time.Unix(1700000000, 0).UTC(),
+		Expires:     time.Unix(1700036000, 0).UTC(),
+		Description: "Heavy rain expected.",
+		Tags:        []string{"Rain"},
+	}}, response.Alerts)
+}
+
+func TestWeatherHandler_GetWeatherConditionByCoordinates_OneCallWithoutCurrent(t *testing.T) {
+	oneCall := &MockOneCallAPI{
+		FetchOneCallFunc: func(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []repo.OneCallBlock) (model.OneCallData, error) {
+			return model.OneCallData{Lat: 35, Lon: 139}, nil
+		},
+	}
+
+	cfg := &config.AppConfig{UnitOfMeasurement: "metric", CurrentFromOneCall: true}
+	h := NewWeatherHandler(&MockWeatherAPI{}, cfg)
+	h.OneCall = oneCall
+
+	req, _ := http.NewRequest("GET", "/weather?lat=35&lon=139", nil)
+	rr := httptest.NewRecorder()
+	h.GetWeatherConditionByCoordinates(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
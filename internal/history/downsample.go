@@ -0,0 +1,196 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Interval is the spacing of the points of a history.
+type Interval string
+
+const (
+	IntervalRaw    Interval = "raw"    // Every observation
+	IntervalHourly Interval = "hourly" // One point per UTC hour
+	IntervalDaily  Interval = "daily"  // One point per calendar day at the location
+)
+
+// ParseInterval validates an interval name; the empty name selects hourly.
+func ParseInterval(name string) (Interval, error) {
+	switch interval := Interval(name); interval {
+	case "":
+		return IntervalHourly, nil
+	case IntervalRaw, IntervalHourly, IntervalDaily:
+		return interval, nil
+	}
+	return "", fmt.Errorf("unknown interval %q, expected raw, hourly or daily", name)
+}
+
+// Step returns the nominal spacing of the interval's points. Raw observations are nominally hourly, the
+// resolution of OpenWeatherMap's history.
+func (i Interval) Step() time.Duration {
+	if i == IntervalDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Stats summarizes a quantity over a bucket.
+type Stats struct {
+	Min, Max, Mean float64
+}
+
+// Bucket aggregates the observations of one interval, in canonical units.
+type Bucket struct {
+	Start         time.Time // In the location's timezone for daily buckets
+	Count         int
+	Temperature   Stats
+	FeelsLike     Stats
+	Humidity      Stats
+	Pressure      Stats
+	WindSpeed     Stats
+	Clouds        Stats
+	Precipitation Stats
+}
+
+// Downsample aggregates time-ordered observations into buckets of the interval. Raw buckets hold a single
+// observation each; daily buckets follow the timezone offset of their observations.
+func Downsample(observations []Observation, interval Interval) []Bucket {
+	return DownsampleWithRollups(observations, nil, interval)
+}
+
+// DownsampleWithRollups aggregates observations and the rollups of the periods they no longer cover into
+// buckets of the interval. A rollup coarser than the interval makes a bucket of its own.
+func DownsampleWithRollups(observations []Observation, rollups []Rollup, interval Interval) []Bucket {
+	parts := make([]part, 0, len(observations)+len(rollups))
+	for _, o := range observations {
+		parts = append(parts, observationPart(o, interval))
+	}
+	for _, r := range rollups {
+		parts = append(parts, rollupPart(r, interval))
+	}
+
+	buckets := []Bucket{}
+	for _, group := range groupParts(parts, interval) {
+		buckets = append(buckets, group.bucket())
+	}
+	return buckets
+}
+
+// bucketStart returns the start of the bucket of the interval that a time at a UTC offset falls in.
+func bucketStart(t time.Time, offset int, interval Interval) time.Time {
+	switch interval {
+	case IntervalHourly:
+		return t.Truncate(time.Hour)
+	case IntervalDaily:
+		local := t.In(time.FixedZone("", offset))
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	}
+	return t
+}
+
+// quantities is the number of quantities aggregated in a bucket: temperature, feels like, humidity,
+// pressure, wind speed, clouds and precipitation.
+const quantities = 7
+
+// part is an observation or a rollup on its way into a bucket.
+type part struct {
+	start  time.Time // Of the bucket it falls in
+	offset int
+	count  int
+	stats  [quantities]Stats
+}
+
+func observationPart(o Observation, interval Interval) part {
+	p := part{start: bucketStart(o.Time, o.TimezoneOffset, interval), offset: o.TimezoneOffset, count: 1}
+	for i, value := range []float64{o.Temperature, o.FeelsLike, o.Humidity, o.Pressure, o.WindSpeed, o.Clouds, o.Precipitation} {
+		p.stats[i] = Stats{Min: value, Max: value, Mean: value}
+	}
+	return p
+}
+
+func rollupPart(r Rollup, interval Interval) part {
+	return part{start: bucketStart(r.Start, r.TimezoneOffset, interval), offset: r.TimezoneOffset, count: r.Count, stats: r.stats()}
+}
+
+// groupParts sorts parts by bucket and accumulates those of the same bucket. Raw parts are never grouped.
+func groupParts(parts []part, interval Interval) []accumulator {
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].start.Before(parts[j].start) })
+
+	var groups []accumulator
+	for _, p := range parts {
+		if n := len(groups); n > 0 && interval != IntervalRaw && p.start.Equal(groups[n-1].start) {
+			groups[n-1].add(p)
+			continue
+		}
+		var acc accumulator
+		acc.add(p)
+		groups = append(groups, acc)
+	}
+	return groups
+}
+
+// accumulator sums the parts of a bucket.
+type accumulator struct {
+	start  time.Time
+	offset int
+	count  int
+	values [quantities]struct{ min, max, sum float64 }
+}
+
+func (a *accumulator) add(p part) {
+	if a.count == 0 {
+		a.start, a.offset = p.start, p.offset
+		for i := range a.values {
+			a.values[i].min, a.values[i].max = math.Inf(1), math.Inf(-1)
+		}
+	}
+	a.count += p.count
+	for i, stats := range p.stats {
+		v := &a.values[i]
+		v.min, v.max, v.sum = math.Min(v.min, stats.Min), math.Max(v.max, stats.Max), v.sum+stats.Mean*float64(p.count)
+	}
+}
+
+func (a *accumulator) bucket() Bucket {
+	stats := func(i int) Stats {
+		return Stats{Min: a.values[i].min, Max: a.values[i].max, Mean: a.values[i].sum / float64(a.count)}
+	}
+	return Bucket{
+		Start:         a.start,
+		Count:         a.count,
+		Temperature:   stats(0),
+		FeelsLike:     stats(1),
+		Humidity:      stats(2),
+		Pressure:      stats(3),
+		WindSpeed:     stats(4),
+		Clouds:        stats(5),
+		Precipitation: stats(6),
+	}
+}
+
+// Covers reports whether time-ordered observations and the periods of rollups cover from to to without a
+// gap longer than maxGap, counting the ends of the range.
+func Covers(observations []Observation, rollups []Rollup, from, to time.Time, maxGap time.Duration) bool {
+	type span struct{ start, end time.Time }
+	spans := make([]span, 0, len(observations)+len(rollups))
+	for _, o := range observations {
+		spans = append(spans, span{o.Time, o.Time})
+	}
+	for _, r := range rollups {
+		spans = append(spans, span{r.Start, r.End()})
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	last := from
+	for _, s := range spans {
+		if s.start.Sub(last) > maxGap {
+			return false
+		}
+		if s.end.After(last) {
+			last = s.end
+		}
+	}
+	return to.Sub(last) <= maxGap
+}
@@ -0,0 +1,82 @@
+// Package history records weather observations by location cell and time, and downsamples them for the
+// history endpoint.
+package history
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+// cellsPerDegree sets the size of a location cell: 0.01°, about 1.1 km of latitude.
+const cellsPerDegree = 100
+
+// Cell is a square of the latitude/longitude grid that observations are grouped by, so nearby requests share
+// their history.
+type Cell struct {
+	Lat, Lon int32 // Hundredths of a degree
+}
+
+// CellOf returns the cell containing a point.
+func CellOf(lat, lon float64) Cell {
+	return Cell{Lat: int32(math.Round(lat * cellsPerDegree)), Lon: int32(math.Round(lon * cellsPerDegree))}
+}
+
+// Observation is the weather at a point at one time, in canonical units: temperatures in kelvin, speeds in
+// m/s, pressure in hPa and precipitation in mm in the last hour.
+type Observation struct {
+	Time           time.Time
+	Lat, Lon       float64
+	TimezoneOffset int // Shift in seconds from UTC at the location
+	Temperature    float64
+	FeelsLike      float64
+	Humidity       float64 // %
+	Pressure       float64
+	WindSpeed      float64
+	WindDeg        float64
+	Clouds         float64 // %
+	Precipitation  float64 // Rain and snow
+	ConditionID    int     // OpenWeatherMap condition code; 0 when none was reported
+}
+
+// Cell returns the cell of the observation.
+func (o Observation) Cell() Cell {
+	return CellOf(o.Lat, o.Lon)
+}
+
+// FromWeatherData converts weather data fetched in the canonical unit system to an observation at a point.
+func FromWeatherData(lat, lon float64, data model.WeatherData) Observation {
+	o := Observation{
+		Time:           time.Unix(data.Dt, 0).UTC(),
+		Lat:            lat,
+		Lon:            lon,
+		TimezoneOffset: data.Timezone,
+		Temperature:    data.Main.Temp,
+		FeelsLike:      data.Main.FeelsLike,
+		Humidity:       data.Main.Humidity,
+		Pressure:       data.Main.Pressure,
+		WindSpeed:      data.Wind.Speed,
+		WindDeg:        data.Wind.Deg,
+		Clouds:         data.Clouds.All,
+	}
+	for _, precipitation := range []*model.Precipitation{data.Rain, data.Snow} {
+		if precipitation != nil && precipitation.OneHour != nil {
+			o.Precipitation += *precipitation.OneHour
+		}
+	}
+	if len(data.Weather) > 0 {
+		o.ConditionID = data.Weather[0].ID
+	}
+	return o
+}
+
+// Store keeps observations by cell and time.
+type Store interface {
+	// Append records observations. An observation at the same cell and time as a recorded one replaces it,
+	// so repeated fetches of unchanged upstream data are kept once.
+	Append(ctx context.Context, observations ...Observation) error
+	// Range returns the observations of a cell from from (inclusive) to to (exclusive), oldest first.
+	Range(ctx context.Context, cell Cell, from, to time.Time) ([]Observation, error)
+}
@@ -0,0 +1,207 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// observationAt builds an observation in Tokyo at minutes after start.
+func observationAt(minutes int, temperature float64) Observation {
+	return Observation{Time: start.Add(time.Duration(minutes) * time.Minute), Lat: 35.6895, Lon: 139.6917, TimezoneOffset: 32400, Temperature: temperature}
+}
+
+func TestCellOf(t *testing.T) {
+	if CellOf(35.6895, 139.6917) != CellOf(35.6912, 139.6949) {
+		t.Errorf("Expected points 300 m apart to share a cell")
+	}
+	if got := CellOf(-33.8688, 151.2093); got != (Cell{Lat: -3387, Lon: 15121}) {
+		t.Errorf("CellOf() = %+v; want {-3387 15121}", got)
+	}
+}
+
+func TestFromWeatherData(t *testing.T) {
+	rain, snow := 0.5, 0.25
+	data := model.WeatherData{
+		Dt: start.Unix(), Timezone: 32400,
+		Main:    model.MainInfo{Temp: 280, FeelsLike: 278, Humidity: 70, Pressure: 1012},
+		Wind:    model.Wind{Speed: 4, Deg: 90},
+		Clouds:  model.Clouds{All: 75},
+		Rain:    &model.Precipitation{OneHour: &rain},
+		Snow:    &model.Precipitation{OneHour: &snow},
+		Weather: []model.WeatherCondition{{ID: 615}},
+	}
+
+	o := FromWeatherData(35, 139, data)
+	expected := Observation{
+		Time: start, Lat: 35, Lon: 139, TimezoneOffset: 32400, Temperature: 280, FeelsLike: 278, Humidity: 70,
+		Pressure: 1012, WindSpeed: 4, WindDeg: 90, Clouds: 75, Precipitation: 0.75, ConditionID: 615,
+	}
+	if o != expected {
+		t.Errorf("FromWeatherData() = %+v; want %+v", o, expected)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(0)
+
+	store.Append(ctx, observationAt(20, 3), observationAt(0, 1), observationAt(10, 2))
+	store.Append(ctx, observationAt(10, 5)) // Replaces the observation at the same time
+	store.Append(ctx, Observation{Time: start, Lat: 51.5, Lon: -0.12, Temperature: 9})
+
+	observations, err := store.Range(ctx, CellOf(35.69, 139.69), start, start.Add(20*time.Minute))
+	if err != nil {
+		t.Fatalf("Range() returned an unexpected error: %v", err)
+	}
+	if len(observations) != 2 || observations[0].Temperature != 1 || observations[1].Temperature != 5 {
+		t.Errorf("Range() = %+v; want the observations at 0 and 10 minutes, the latter replaced", observations)
+	}
+
+	observations, _ = store.Range(ctx, CellOf(0, 0), start, start.Add(time.Hour))
+	if len(observations) != 0 {
+		t.Errorf("Range() of an unknown cell = %+v; want none", observations)
+	}
+}
+
+func TestMemoryStore_Retention(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Hour)
+	store.now = func() time.Time { return start.Add(90 * time.Minute) }
+
+	store.Append(ctx, observationAt(0, 1), observationAt(60, 2))
+
+	observations, _ := store.Range(ctx, CellOf(35.69, 139.69), start, start.Add(2*time.Hour))
+	if len(observations) != 1 || observations[0].Temperature != 2 {
+		t.Errorf("Range() = %+v; want only the observation within the retention period", observations)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	observations := []Observation{
+		observationAt(0, 270), observationAt(20, 272), observationAt(40, 277),
+		observationAt(60, 280),
+		observationAt(15*60, 290), // Midnight in Tokyo: the next local day
+	}
+
+	hourly := Downsample(observations, IntervalHourly)
+	if len(hourly) != 3 {
+		t.Fatalf("Downsample() returned %d hourly buckets; want 3", len(hourly))
+	}
+	if hourly[0].Start != start || hourly[0].Count != 3 || hourly[0].Temperature != (Stats{Min: 270, Max: 277, Mean: 273}) {
+		t.Errorf("First hourly bucket = %+v; want 3 observations from 270 to 277 K, mean 273 K", hourly[0])
+	}
+
+	daily := Downsample(observations, IntervalDaily)
+	if len(daily) != 2 || daily[0].Count != 4 || daily[1].Count != 1 {
+		t.Fatalf("Downsample() = %+v; want days of 4 and 1 observations", daily)
+	}
+	if daily[0].Start.Format(time.RFC3339) != "2024-01-01T00:00:00+09:00" || daily[0].Temperature.Max != 280 {
+		t.Errorf("First daily bucket = %+v; want the day starting at Tokyo midnight with a high of 280 K", daily[0])
+	}
+
+	if raw := Downsample(observations, IntervalRaw); len(raw) != 5 || raw[1].Temperature.Mean != 272 {
+		t.Errorf("Downsample() = %+v; want one raw bucket per observation", raw)
+	}
+	if empty := Downsample(nil, IntervalHourly); empty == nil || len(empty) != 0 {
+		t.Errorf("Downsample() of no observations = %#v; want an empty slice", empty)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := map[string]Interval{"": IntervalHourly, "raw": IntervalRaw, "hourly": IntervalHourly, "daily": IntervalDaily}
+	for name, expected := range tests {
+		if interval, err := ParseInterval(name); err != nil || interval != expected {
+			t.Errorf("ParseInterval(%q) = %q, %v; want %q", name, interval, err, expected)
+		}
+	}
+	if _, err := ParseInterval("weekly"); err == nil {
+		t.Errorf("Expected an error for an unknown interval")
+	}
+}
+
+func TestCovers(t *testing.T) {
+	observations := []Observation{observationAt(60, 0), observationAt(180, 0)}
+	end := start.Add(4 * time.Hour)
+
+	tests := []struct {
+		name         string
+		observations []Observation
+		rollups      []Rollup
+		maxGap       time.Duration
+		expected     bool
+	}{
+		{"Covered", observations, nil, 2 * time.Hour, true},
+		{"Gap Between", observations, nil, 90 * time.Minute, false},
+		{"Gap At The End", observations[:1], nil, 2 * time.Hour, false},
+		{"Nothing Recorded", nil, nil, 2 * time.Hour, false},
+		{"Rolled Up", nil, []Rollup{{Interval: IntervalDaily, Bucket: Bucket{Start: start.Add(-time.Hour)}}}, time.Hour, true},
+		{"Rollup Fills The Gap", observations, []Rollup{{Interval: IntervalHourly, Bucket: Bucket{Start: start.Add(90 * time.Minute)}}}, time.Hour, true},
+	}
+
+	for _, tc := range tests {
+		if got := Covers(tc.observations, tc.rollups, start, end, tc.maxGap); got != tc.expected {
+			t.Errorf("%s: Covers() = %v; want %v", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	observations := []Observation{
+		observationAt(0, 270), observationAt(20, 272), observationAt(40, 277),
+		observationAt(60, 280),
+		observationAt(15*60, 290),
+	}
+	cell := observations[0].Cell()
+
+	hourly := RollUp(cell, observations, IntervalHourly)
+	if len(hourly) != 3 || hourly[0].Cell != cell || hourly[0].Interval != IntervalHourly || hourly[0].TimezoneOffset != 32400 {
+		t.Fatalf("RollUp() = %+v; want 3 hourly rollups of the cell", hourly)
+	}
+	if !hourly[0].End().Equal(start.Add(time.Hour)) {
+		t.Errorf("End() = %v; want an hour after the start", hourly[0].End())
+	}
+
+	// Rolling the hourly rollups up into days gives the same days as the observations
+	daily := Coarsen(cell, hourly, IntervalDaily)
+	want := Downsample(observations, IntervalDaily)
+	if len(daily) != 2 {
+		t.Fatalf("Coarsen() = %+v; want 2 daily rollups", daily)
+	}
+	for i, rollup := range daily {
+		if !rollup.Start.Equal(want[i].Start) || rollup.Count != want[i].Count || rollup.Temperature != want[i].Temperature {
+			t.Errorf("Coarsen() day %d = %+v; want %+v", i, rollup.Bucket, want[i])
+		}
+	}
+
+	// A late observation of a rolled-up hour is combined with its rollup
+	late := RollUp(cell, []Observation{observationAt(30, 275)}, IntervalHourly)
+	combined := Coarsen(cell, append(late, hourly...), IntervalHourly)
+	if len(combined) != 3 || combined[0].Count != 4 || combined[0].Temperature != (Stats{Min: 270, Max: 277, Mean: 273.5}) {
+		t.Errorf("Coarsen() = %+v; want the late observation in the first hour", combined)
+	}
+}
+
+func TestDownsampleWithRollups(t *testing.T) {
+	cell := observationAt(0, 0).Cell()
+	rollups := RollUp(cell, []Observation{observationAt(0, 270), observationAt(30, 280)}, IntervalHourly)
+	observations := []Observation{observationAt(60, 290), observationAt(90, 300)}
+
+	hourly := DownsampleWithRollups(observations, rollups, IntervalHourly)
+	if len(hourly) != 2 || hourly[0].Count != 2 || hourly[0].Temperature.Mean != 275 || hourly[1].Temperature.Mean != 295 {
+		t.Errorf("DownsampleWithRollups() = %+v; want the rollup and the observations in hours of their own", hourly)
+	}
+
+	daily := DownsampleWithRollups(observations, rollups, IntervalDaily)
+	if len(daily) != 1 || daily[0].Count != 4 || daily[0].Temperature != (Stats{Min: 270, Max: 300, Mean: 285}) {
+		t.Errorf("DownsampleWithRollups() = %+v; want one day of 4 observations", daily)
+	}
+
+	if raw := DownsampleWithRollups(observations, rollups, IntervalRaw); len(raw) != 3 || raw[0].Count != 2 {
+		t.Errorf("DownsampleWithRollups() = %+v; want the rollup as one raw point", raw)
+	}
+}
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps observations in memory, for tests and deployments without persistence.
+type MemoryStore struct {
+	retention time.Duration
+	now       func() time.Time // Clock, replaceable in tests
+
+	mu    sync.RWMutex
+	cells map[Cell][]Observation // Sorted by time
+}
+
+// NewMemoryStore creates a MemoryStore that drops observations older than retention; 0 keeps them all.
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{retention: retention, now: time.Now, cells: make(map[Cell][]Observation)}
+}
+
+// Append records observations, replacing any at the same cell and time.
+func (s *MemoryStore) Append(ctx context.Context, observations ...Observation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, o := range observations {
+		cell := o.Cell()
+		recorded := s.cells[cell]
+		i := sort.Search(len(recorded), func(i int) bool { return !recorded[i].Time.Before(o.Time) })
+		if i < len(recorded) && recorded[i].Time.Equal(o.Time) {
+			recorded[i] = o
+			continue
+		}
+		recorded = append(recorded, Observation{})
+		copy(recorded[i+1:], recorded[i:])
+		recorded[i] = o
+		s.cells[cell] = s.expire(recorded)
+	}
+	return nil
+}
+
+// expire drops the observations of a cell that are past the retention period.
+func (s *MemoryStore) expire(recorded []Observation) []Observation {
+	if s.retention <= 0 {
+		return recorded
+	}
+	cutoff := s.now().Add(-s.retention)
+	i := sort.Search(len(recorded), func(i int) bool { return !recorded[i].Time.Before(cutoff) })
+	return recorded[i:]
+}
+
+// Range returns copies of the observations of a cell from from (inclusive) to to (exclusive).
+func (s *MemoryStore) Range(ctx context.Context, cell Cell, from, to time.Time) ([]Observation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	recorded := s.cells[cell]
+	start := sort.Search(len(recorded), func(i int) bool { return !recorded[i].Time.Before(from) })
+	end := sort.Search(len(recorded), func(i int) bool { return !recorded[i].Time.Before(to) })
+	if start >= end {
+		return nil, nil
+	}
+	return append([]Observation(nil), recorded[start:end]...), nil
+}
@@ -0,0 +1,59 @@
+package history
+
+import (
+	"context"
+	"time"
+)
+
+// Rollup aggregates the observations of a cell over an hour or a day. Stores keep rollups in place of
+// observations that are past their raw retention.
+type Rollup struct {
+	Cell           Cell
+	Interval       Interval // IntervalHourly or IntervalDaily
+	TimezoneOffset int      // Of the observations; daily rollups start at local midnight
+	Bucket
+}
+
+// End returns the end of the period of the rollup.
+func (r Rollup) End() time.Time {
+	return r.Start.Add(r.Interval.Step())
+}
+
+func (r Rollup) stats() [quantities]Stats {
+	return [quantities]Stats{r.Temperature, r.FeelsLike, r.Humidity, r.Pressure, r.WindSpeed, r.Clouds, r.Precipitation}
+}
+
+// RollupStore is a Store that rolls old observations up.
+type RollupStore interface {
+	Store
+	// Rollups returns the rollups of a cell that start from from (inclusive) to to (exclusive), oldest first.
+	Rollups(ctx context.Context, cell Cell, from, to time.Time) ([]Rollup, error)
+}
+
+// RollUp aggregates the observations of a cell into hourly or daily rollups, oldest first.
+func RollUp(cell Cell, observations []Observation, interval Interval) []Rollup {
+	parts := make([]part, len(observations))
+	for i, o := range observations {
+		parts[i] = observationPart(o, interval)
+	}
+	return rollupsOf(cell, parts, interval)
+}
+
+// Coarsen aggregates the rollups of a cell into rollups of the interval, oldest first. Rollups of the same
+// period, such as those of observations recorded after their period was rolled up, are combined.
+func Coarsen(cell Cell, rollups []Rollup, interval Interval) []Rollup {
+	parts := make([]part, len(rollups))
+	for i, r := range rollups {
+		parts[i] = rollupPart(r, interval)
+	}
+	return rollupsOf(cell, parts, interval)
+}
+
+func rollupsOf(cell Cell, parts []part, interval Interval) []Rollup {
+	groups := groupParts(parts, interval)
+	rollups := make([]Rollup, len(groups))
+	for i, group := range groups {
+		rollups[i] = Rollup{Cell: cell, Interval: interval, TimezoneOffset: group.offset, Bucket: group.bucket()}
+	}
+	return rollups
+}
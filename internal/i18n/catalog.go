@@ -0,0 +1,8 @@
+package i18n
+
+// catalogs maps each language to its translated messages. English needs no catalog.
+var catalogs = map[Language]map[string]string{
+	Spanish:  spanish,
+	German:   german,
+	Japanese: japanese,
+}
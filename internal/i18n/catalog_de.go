@@ -0,0 +1,134 @@
+package i18n
+
+// german is the German catalog.
+var german = map[string]string{
+	// Temperature categories of the builtin schemes
+	"category.Freezing":     "Frostig",
+	"category.Cold":         "Kalt",
+	"category.Cool":         "Kühl",
+	"category.Mild":         "Mild",
+	"category.Warm":         "Warm",
+	"category.Hot":          "Heiß",
+	"category.Moderate":     "Gemäßigt",
+	"category.Comfortable":  "Angenehm",
+	"category.Very Hot":     "Sehr heiß",
+	"category.Extreme Cold": "Extrem kalt",
+	"category.Very Cold":    "Sehr kalt",
+
+	// Frost risk levels
+	"category.Severe": "Sehr hoch",
+	"category.High":   "Hoch",
+	"category.Medium": "Mittel",
+	"category.Low":    "Gering",
+	"category.None":   "Keine",
+
+	// OpenWeatherMap condition groups
+	"condition.Thunderstorm": "Gewitter",
+	"condition.Drizzle":      "Nieselregen",
+	"condition.Rain":         "Regen",
+	"condition.Snow":         "Schnee",
+	"condition.Mist":         "Dunst",
+	"condition.Smoke":        "Rauch",
+	"condition.Haze":         "Diesig",
+	"condition.Dust":         "Staub",
+	"condition.Fog":          "Nebel",
+	"condition.Sand":         "Sand",
+	"condition.Ash":          "Asche",
+	"condition.Squall":       "Böen",
+	"condition.Tornado":      "Tornado",
+	"condition.Clear":        "Klar",
+	"condition.Clouds":       "Wolken",
+	"condition.Unknown":      "Unbekannt",
+
+	// OpenWeatherMap condition descriptions by code
+	"description.200": "Gewitter mit leichtem Regen",
+	"description.201": "Gewitter mit Regen",
+	"description.202": "Gewitter mit starkem Regen",
+	"description.210": "leichtes Gewitter",
+	"description.211": "Gewitter",
+	"description.212": "schweres Gewitter",
+	"description.221": "vereinzelte Gewitter",
+	"description.230": "Gewitter mit leichtem Nieselregen",
+	"description.231": "Gewitter mit Nieselregen",
+	"description.232": "Gewitter mit starkem Nieselregen",
+	"description.300": "leichter Nieselregen",
+	"description.301": "Nieselregen",
+	"description.302": "starker Nieselregen",
+	"description.310": "leichter Nieselregen mit Regen",
+	"description.311": "Nieselregen mit Regen",
+	"description.312": "starker Nieselregen mit Regen",
+	"description.313": "Regenschauer und Nieselregen",
+	"description.314": "starke Regenschauer und Nieselregen",
+	"description.321": "Nieselschauer",
+	"description.500": "leichter Regen",
+	"description.501": "mäßiger Regen",
+	"description.502": "starker Regen",
+	"description.503": "sehr starker Regen",
+	"description.504": "extremer Regen",
+	"description.511": "gefrierender Regen",
+	"description.520": "leichte Regenschauer",
+	"description.521": "Regenschauer",
+	"description.522": "starke Regenschauer",
+	"description.531": "vereinzelte Regenschauer",
+	"description.600": "leichter Schneefall",
+	"description.601": "Schnee",
+	"description.602": "starker Schneefall",
+	"description.611": "Schneeregen",
+	"description.612": "leichte Schneeregenschauer",
+	"description.613": "Schneeregenschauer",
+	"description.615": "leichter Regen und Schnee",
+	"description.616": "Regen und Schnee",
+	"description.620": "leichte Schneeschauer",
+	"description.621": "Schneeschauer",
+	"description.622": "starke Schneeschauer",
+	"description.701": "Dunst",
+	"description.711": "Rauch",
+	"description.721": "diesig",
+	"description.731": "Sand- und Staubwirbel",
+	"description.741": "Nebel",
+	"description.751": "Sand",
+	"description.761": "Staub",
+	"description.762": "Vulkanasche",
+	"description.771": "Sturmböen",
+	"description.781": "Tornado",
+	"description.800": "klarer Himmel",
+	"description.801": "ein paar Wolken",
+	"description.802": "mäßig bewölkt",
+	"description.803": "überwiegend bewölkt",
+	"description.804": "bedeckt",
+
+	// Weather summary fragments and compass points
+	"summary.conditions": "{condition}, {category} bei {temperature}",
+	"summary.feelsLike":  ", gefühlt {feelsLike}",
+	"summary.wind":       ", Wind mit {speed} aus {direction}",
+	"summary.calm":       ", windstill",
+	"direction.N":        "Nord",
+	"direction.NE":       "Nordost",
+	"direction.E":        "Ost",
+	"direction.SE":       "Südost",
+	"direction.S":        "Süd",
+	"direction.SW":       "Südwest",
+	"direction.W":        "West",
+	"direction.NW":       "Nordwest",
+
+	// Nowcast sentences and durations
+	"nowcast.dry":            "kein Niederschlag in den nächsten {window} erwartet",
+	"nowcast.starts":         "{kind} in {startsIn}",
+	"nowcast.startsAndStops": "{kind} in {startsIn} für {duration}",
+	"nowcast.stops":          "{kind} endet in {endsIn}",
+	"nowcast.continues":      "{kind} hält mindestens {window} an",
+	"nowcast.kind.rain":      "Regen",
+	"nowcast.kind.snow":      "Schnee",
+	"duration.minutes":       "{minutes} Min.",
+	"duration.hours":         "{hours} Std.",
+	"duration.hoursMinutes":  "{hours} Std. {minutes} Min.",
+
+	// Frost risk summaries
+	"frost.none":         "kein Frostrisiko in den nächsten {nights} Nächten",
+	"frost.noneTonight":  "kein Frostrisiko heute Nacht",
+	"frost.risk":         "Frostrisiko {night}: {level}, Tiefstwert {min}",
+	"frost.riskFreezing": "Frostrisiko {night}: {level}, Tiefstwert {min}, {hours} unter {freezing}",
+	"frost.tonight":      "heute Nacht",
+	"frost.tomorrow":     "morgen Nacht",
+	"frost.later":        "in {nights} Nächten",
+}
@@ -0,0 +1,134 @@
+package i18n
+
+// spanish is the Spanish catalog.
+var spanish = map[string]string{
+	// Temperature categories of the builtin schemes
+	"category.Freezing":     "Helado",
+	"category.Cold":         "Frío",
+	"category.Cool":         "Fresco",
+	"category.Mild":         "Templado",
+	"category.Warm":         "Cálido",
+	"category.Hot":          "Caluroso",
+	"category.Moderate":     "Moderado",
+	"category.Comfortable":  "Agradable",
+	"category.Very Hot":     "Muy caluroso",
+	"category.Extreme Cold": "Frío extremo",
+	"category.Very Cold":    "Muy frío",
+
+	// Frost risk levels
+	"category.Severe": "Muy alto",
+	"category.High":   "Alto",
+	"category.Medium": "Medio",
+	"category.Low":    "Bajo",
+	"category.None":   "Ninguno",
+
+	// OpenWeatherMap condition groups
+	"condition.Thunderstorm": "Tormenta",
+	"condition.Drizzle":      "Llovizna",
+	"condition.Rain":         "Lluvia",
+	"condition.Snow":         "Nieve",
+	"condition.Mist":         "Neblina",
+	"condition.Smoke":        "Humo",
+	"condition.Haze":         "Calima",
+	"condition.Dust":         "Polvo",
+	"condition.Fog":          "Niebla",
+	"condition.Sand":         "Arena",
+	"condition.Ash":          "Ceniza",
+	"condition.Squall":       "Turbonada",
+	"condition.Tornado":      "Tornado",
+	"condition.Clear":        "Despejado",
+	"condition.Clouds":       "Nubes",
+	"condition.Unknown":      "Desconocido",
+
+	// OpenWeatherMap condition descriptions by code
+	"description.200": "tormenta con lluvia ligera",
+	"description.201": "tormenta con lluvia",
+	"description.202": "tormenta con lluvia intensa",
+	"description.210": "tormenta ligera",
+	"description.211": "tormenta",
+	"description.212": "tormenta fuerte",
+	"description.221": "tormenta irregular",
+	"description.230": "tormenta con llovizna ligera",
+	"description.231": "tormenta con llovizna",
+	"description.232": "tormenta con llovizna intensa",
+	"description.300": "llovizna ligera",
+	"description.301": "llovizna",
+	"description.302": "llovizna intensa",
+	"description.310": "llovizna con lluvia ligera",
+	"description.311": "llovizna con lluvia",
+	"description.312": "llovizna con lluvia intensa",
+	"description.313": "chubascos de lluvia y llovizna",
+	"description.314": "chubascos fuertes de lluvia y llovizna",
+	"description.321": "chubascos de llovizna",
+	"description.500": "lluvia ligera",
+	"description.501": "lluvia moderada",
+	"description.502": "lluvia intensa",
+	"description.503": "lluvia muy intensa",
+	"description.504": "lluvia extrema",
+	"description.511": "lluvia helada",
+	"description.520": "chubascos ligeros",
+	"description.521": "chubascos",
+	"description.522": "chubascos intensos",
+	"description.531": "chubascos irregulares",
+	"description.600": "nevada ligera",
+	"description.601": "nieve",
+	"description.602": "nevada intensa",
+	"description.611": "aguanieve",
+	"description.612": "chubascos ligeros de aguanieve",
+	"description.613": "chubascos de aguanieve",
+	"description.615": "lluvia y nieve ligeras",
+	"description.616": "lluvia y nieve",
+	"description.620": "chubascos ligeros de nieve",
+	"description.621": "chubascos de nieve",
+	"description.622": "chubascos intensos de nieve",
+	"description.701": "neblina",
+	"description.711": "humo",
+	"description.721": "calima",
+	"description.731": "remolinos de arena o polvo",
+	"description.741": "niebla",
+	"description.751": "arena",
+	"description.761": "polvo",
+	"description.762": "ceniza volcánica",
+	"description.771": "turbonadas",
+	"description.781": "tornado",
+	"description.800": "cielo despejado",
+	"description.801": "algunas nubes",
+	"description.802": "nubes dispersas",
+	"description.803": "nubes fragmentadas",
+	"description.804": "nublado",
+
+	// Weather summary fragments and compass points
+	"summary.conditions": "{condition}, {category} con {temperature}",
+	"summary.feelsLike":  ", sensación térmica de {feelsLike}",
+	"summary.wind":       ", viento de {speed} del {direction}",
+	"summary.calm":       ", sin viento",
+	"direction.N":        "norte",
+	"direction.NE":       "noreste",
+	"direction.E":        "este",
+	"direction.SE":       "sureste",
+	"direction.S":        "sur",
+	"direction.SW":       "suroeste",
+	"direction.W":        "oeste",
+	"direction.NW":       "noroeste",
+
+	// Nowcast sentences and durations
+	"nowcast.dry":            "sin precipitación prevista en las próximas {window}",
+	"nowcast.starts":         "{kind} dentro de {startsIn}",
+	"nowcast.startsAndStops": "{kind} dentro de {startsIn}, durante {duration}",
+	"nowcast.stops":          "la {kind} cesará dentro de {endsIn}",
+	"nowcast.continues":      "{kind} durante al menos {window}",
+	"nowcast.kind.rain":      "lluvia",
+	"nowcast.kind.snow":      "nieve",
+	"duration.minutes":       "{minutes} min",
+	"duration.hours":         "{hours} h",
+	"duration.hoursMinutes":  "{hours} h {minutes} min",
+
+	// Frost risk summaries
+	"frost.none":         "sin riesgo de helada en las próximas {nights} noches",
+	"frost.noneTonight":  "sin riesgo de helada esta noche",
+	"frost.risk":         "riesgo de helada {night}: {level}, mínima de {min}",
+	"frost.riskFreezing": "riesgo de helada {night}: {level}, mínima de {min}, {hours} bajo {freezing}",
+	"frost.tonight":      "esta noche",
+	"frost.tomorrow":     "mañana por la noche",
+	"frost.later":        "dentro de {nights} noches",
+}
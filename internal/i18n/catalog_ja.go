@@ -0,0 +1,134 @@
+package i18n
+
+// japanese is the Japanese catalog.
+var japanese = map[string]string{
+	// Temperature categories of the builtin schemes
+	"category.Freezing":     "氷点下",
+	"category.Cold":         "寒い",
+	"category.Cool":         "涼しい",
+	"category.Mild":         "穏やか",
+	"category.Warm":         "暖かい",
+	"category.Hot":          "暑い",
+	"category.Moderate":     "適温",
+	"category.Comfortable":  "快適",
+	"category.Very Hot":     "非常に暑い",
+	"category.Extreme Cold": "極寒",
+	"category.Very Cold":    "非常に寒い",
+
+	// Frost risk levels
+	"category.Severe": "非常に高い",
+	"category.High":   "高い",
+	"category.Medium": "中程度",
+	"category.Low":    "低い",
+	"category.None":   "なし",
+
+	// OpenWeatherMap condition groups
+	"condition.Thunderstorm": "雷雨",
+	"condition.Drizzle":      "霧雨",
+	"condition.Rain":         "雨",
+	"condition.Snow":         "雪",
+	"condition.Mist":         "靄",
+	"condition.Smoke":        "煙",
+	"condition.Haze":         "煙霧",
+	"condition.Dust":         "砂塵",
+	"condition.Fog":          "霧",
+	"condition.Sand":         "砂",
+	"condition.Ash":          "火山灰",
+	"condition.Squall":       "スコール",
+	"condition.Tornado":      "竜巻",
+	"condition.Clear":        "晴れ",
+	"condition.Clouds":       "曇り",
+	"condition.Unknown":      "不明",
+
+	// OpenWeatherMap condition descriptions by code
+	"description.200": "小雨を伴う雷雨",
+	"description.201": "雨を伴う雷雨",
+	"description.202": "大雨を伴う雷雨",
+	"description.210": "弱い雷雨",
+	"description.211": "雷雨",
+	"description.212": "激しい雷雨",
+	"description.221": "散発的な雷雨",
+	"description.230": "弱い霧雨を伴う雷雨",
+	"description.231": "霧雨を伴う雷雨",
+	"description.232": "強い霧雨を伴う雷雨",
+	"description.300": "弱い霧雨",
+	"description.301": "霧雨",
+	"description.302": "強い霧雨",
+	"description.310": "弱い霧雨と雨",
+	"description.311": "霧雨と雨",
+	"description.312": "強い霧雨と雨",
+	"description.313": "にわか雨と霧雨",
+	"description.314": "強いにわか雨と霧雨",
+	"description.321": "にわか霧雨",
+	"description.500": "小雨",
+	"description.501": "適度な雨",
+	"description.502": "強い雨",
+	"description.503": "非常に強い雨",
+	"description.504": "猛烈な雨",
+	"description.511": "着氷性の雨",
+	"description.520": "弱いにわか雨",
+	"description.521": "にわか雨",
+	"description.522": "強いにわか雨",
+	"description.531": "散発的なにわか雨",
+	"description.600": "小雪",
+	"description.601": "雪",
+	"description.602": "大雪",
+	"description.611": "みぞれ",
+	"description.612": "弱いにわかみぞれ",
+	"description.613": "にわかみぞれ",
+	"description.615": "弱い雨と雪",
+	"description.616": "雨と雪",
+	"description.620": "弱いにわか雪",
+	"description.621": "にわか雪",
+	"description.622": "強いにわか雪",
+	"description.701": "靄",
+	"description.711": "煙",
+	"description.721": "煙霧",
+	"description.731": "砂塵旋風",
+	"description.741": "霧",
+	"description.751": "砂",
+	"description.761": "塵",
+	"description.762": "火山灰",
+	"description.771": "スコール",
+	"description.781": "竜巻",
+	"description.800": "晴天",
+	"description.801": "薄い雲",
+	"description.802": "千切れ雲",
+	"description.803": "曇りがち",
+	"description.804": "厚い雲",
+
+	// Weather summary fragments and compass points
+	"summary.conditions": "{condition}、{category}、気温{temperature}",
+	"summary.feelsLike":  "（体感{feelsLike}）",
+	"summary.wind":       "、{direction}の風 {speed}",
+	"summary.calm":       "、風は穏やか",
+	"direction.N":        "北",
+	"direction.NE":       "北東",
+	"direction.E":        "東",
+	"direction.SE":       "南東",
+	"direction.S":        "南",
+	"direction.SW":       "南西",
+	"direction.W":        "西",
+	"direction.NW":       "北西",
+
+	// Nowcast sentences and durations
+	"nowcast.dry":            "今後{window}は降水の見込みなし",
+	"nowcast.starts":         "{startsIn}後に{kind}が降り始める見込み",
+	"nowcast.startsAndStops": "{startsIn}後に{kind}が降り始め、{duration}続く見込み",
+	"nowcast.stops":          "{kind}は{endsIn}後にやむ見込み",
+	"nowcast.continues":      "{kind}は少なくとも{window}続く見込み",
+	"nowcast.kind.rain":      "雨",
+	"nowcast.kind.snow":      "雪",
+	"duration.minutes":       "{minutes}分",
+	"duration.hours":         "{hours}時間",
+	"duration.hoursMinutes":  "{hours}時間{minutes}分",
+
+	// Frost risk summaries
+	"frost.none":         "今後{nights}晩は霜のリスクなし",
+	"frost.noneTonight":  "今夜は霜のリスクなし",
+	"frost.risk":         "{night}の霜のリスク：{level}、最低{min}",
+	"frost.riskFreezing": "{night}の霜のリスク：{level}、最低{min}、{freezing}未満が{hours}",
+	"frost.tonight":      "今夜",
+	"frost.tomorrow":     "明晩",
+	"frost.later":        "{nights}晩後",
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frostMessages are the English frost risk sentences and their parts, keyed by message ID.
+var frostMessages = map[string]string{
+	"frost.none":         "no frost risk over the next {nights} nights",
+	"frost.noneTonight":  "no frost risk tonight",
+	"frost.risk":         "frost risk {night}: {level}, low of {min}",
+	"frost.riskFreezing": "frost risk {night}: {level}, low of {min}, {hours} below {freezing}",
+	"frost.tonight":      "tonight",
+	"frost.tomorrow":     "tomorrow night",
+	"frost.later":        "in {nights} nights",
+}
+
+// FrostFacts describe the riskiest of the upcoming nights. Temperatures are already formatted.
+type FrostFacts struct {
+	Nights             int    // Nights assessed
+	AtRisk             bool   // Some night has a frost risk; the other facts are ignored otherwise
+	Night              int    // Of the riskiest night: 0 for tonight, 1 for tomorrow night, and so on
+	Level              string // Risk level, a category label such as "High"
+	Min                string // Expected minimum, e.g. "-1°C"
+	HoursBelowFreezing int
+	Freezing           string // e.g. "0°C"
+}
+
+// Frost builds a one-sentence frost outlook short enough for a push notification, such as
+// "Frost risk tonight: High, low of -1°C, 3 h below 0°C".
+func Frost(lang Language, facts FrostFacts) string {
+	message := func(id string) string { return text(lang, id, frostMessages[id]) }
+
+	var id string
+	switch {
+	case !facts.AtRisk && facts.Nights <= 1:
+		id = "frost.noneTonight"
+	case !facts.AtRisk:
+		id = "frost.none"
+	case facts.HoursBelowFreezing > 0:
+		id = "frost.riskFreezing"
+	default:
+		id = "frost.risk"
+	}
+
+	night := message("frost.later")
+	switch facts.Night {
+	case 0:
+		night = message("frost.tonight")
+	case 1:
+		night = message("frost.tomorrow")
+	}
+
+	return capitalize(strings.NewReplacer(
+		"{night}", strings.ReplaceAll(night, "{nights}", strconv.Itoa(facts.Night)),
+		"{nights}", strconv.Itoa(facts.Nights),
+		"{level}", Category(lang, facts.Level),
+		"{min}", facts.Min,
+		"{hours}", formatDuration(lang, time.Duration(facts.HoursBelowFreezing)*time.Hour),
+		"{freezing}", facts.Freezing,
+	).Replace(message(id)))
+}
@@ -0,0 +1,111 @@
+// Package i18n selects the response language and translates category labels and condition descriptions.
+// English is the source language: every message's English text is its fallback when a catalog lacks it.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported response language, as an ISO 639-1 code.
+type Language string
+
+const (
+	English  Language = "en"
+	Spanish  Language = "es"
+	German   Language = "de"
+	Japanese Language = "ja"
+)
+
+// Languages lists every supported language, English first.
+var Languages = []Language{English, Spanish, German, Japanese}
+
+// Parse returns the supported language of a language tag such as "de" or "es-MX".
+func Parse(tag string) (Language, error) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	for _, lang := range Languages {
+		if strings.EqualFold(string(lang), primary) {
+			return lang, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language %q", tag)
+}
+
+// Negotiate picks the supported language the client prefers most from an Accept-Language header,
+// falling back to English.
+func Negotiate(acceptLanguage string) Language {
+	type preference struct {
+		lang    Language
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		lang, err := Parse(tag)
+		if err != nil || quality <= 0 {
+			continue
+		}
+		preferences = append(preferences, preference{lang, quality})
+	}
+
+	// Stable, so equally weighted languages keep the client's order
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+	if len(preferences) == 0 {
+		return English
+	}
+	return preferences[0].lang
+}
+
+// languageContextKey is the context key under which the request's language is stored.
+type languageContextKey struct{}
+
+// NewContext returns a context carrying the request's language.
+func NewContext(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// FromContext returns the request's language, or English when none was set.
+func FromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(languageContextKey{}).(Language); ok {
+		return lang
+	}
+	return English
+}
+
+// text returns the translation of a message, or its English text when there is none.
+func text(lang Language, id, english string) string {
+	if translated, ok := catalogs[lang][id]; ok {
+		return translated
+	}
+	return english
+}
+
+// Category translates a temperature category label such as "Cold". Labels of configured schemes that
+// have no translation are returned unchanged.
+func Category(lang Language, label string) string {
+	return text(lang, "category."+label, label)
+}
+
+// Condition translates an OpenWeatherMap condition group name such as "Rain".
+func Condition(lang Language, main string) string {
+	return text(lang, "condition."+main, main)
+}
+
+// Description translates the English description of an OpenWeatherMap condition code.
+func Description(lang Language, code int, english string) string {
+	return text(lang, "description."+strconv.Itoa(code), english)
+}
@@ -0,0 +1,203 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/category"
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected Language
+	}{
+		{"en", English},
+		{"ES", Spanish},
+		{"de-AT", German},
+		{"ja_JP", Japanese},
+	}
+
+	for _, tc := range tests {
+		if lang, err := Parse(tc.tag); err != nil || lang != tc.expected {
+			t.Errorf("Parse(%q) = %q, %v; want %q", tc.tag, lang, err, tc.expected)
+		}
+	}
+
+	if _, err := Parse("fr"); err == nil {
+		t.Errorf("expected an error for an unsupported language")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected Language
+	}{
+		{"", English},
+		{"de-DE,de;q=0.9,en;q=0.8", German},
+		{"fr-FR, es;q=0.7, en;q=0.5", Spanish},
+		{"en;q=0.4, ja;q=0.9", Japanese},
+		{"es, de", Spanish},
+		{"fr, it", English},
+		{"de;q=0, ja;q=bogus", English},
+		{"*", English},
+	}
+
+	for _, tc := range tests {
+		if lang := Negotiate(tc.header); lang != tc.expected {
+			t.Errorf("Negotiate(%q) = %q; want %q", tc.header, lang, tc.expected)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if lang := FromContext(context.Background()); lang != English {
+		t.Errorf("FromContext() = %q; want English by default", lang)
+	}
+	if lang := FromContext(NewContext(context.Background(), Japanese)); lang != Japanese {
+		t.Errorf("FromContext() = %q; want Japanese", lang)
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	if label := Category(Spanish, "Cold"); label != "Frío" {
+		t.Errorf("Category(es, Cold) = %q; want Frío", label)
+	}
+	if label := Condition(German, "Rain"); label != "Regen" {
+		t.Errorf("Condition(de, Rain) = %q; want Regen", label)
+	}
+	if label := Description(Japanese, 500, "light rain"); label != "小雨" {
+		t.Errorf("Description(ja, 500) = %q; want 小雨", label)
+	}
+
+	// Missing translations fall back to English
+	if label := Category(German, "Balmy"); label != "Balmy" {
+		t.Errorf("Category(de, Balmy) = %q; want the English label", label)
+	}
+	if label := Description(English, 500, "light rain"); label != "light rain" {
+		t.Errorf("Description(en, 500) = %q; want the English description", label)
+	}
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for _, scheme := range append(category.Builtin(), category.BuiltinFrost()...) {
+			for _, band := range scheme.Bands {
+				if _, ok := catalog["category."+band.Name]; !ok {
+					t.Errorf("%s catalog lacks category %q", lang, band.Name)
+				}
+			}
+		}
+
+		for code := 200; code < 900; code++ {
+			if _, ok := taxonomy.Lookup(code); !ok {
+				continue
+			}
+			if _, ok := catalog[fmt.Sprintf("description.%d", code)]; !ok {
+				t.Errorf("%s catalog lacks the description of code %d", lang, code)
+			}
+		}
+
+		for id := range summaryMessages {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf("%s catalog lacks summary message %q", lang, id)
+			}
+		}
+		for id := range nowcastMessages {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf("%s catalog lacks nowcast message %q", lang, id)
+			}
+		}
+		for id := range frostMessages {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf("%s catalog lacks frost message %q", lang, id)
+			}
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	facts := SummaryFacts{
+		Condition:     "light rain",
+		Category:      "Cool",
+		Temperature:   "12°C",
+		FeelsLike:     "9°C",
+		WindSpeed:     "20 km/h",
+		WindDirection: "NW",
+	}
+	calm := SummaryFacts{Condition: "clear sky", Category: "Hot", Temperature: "95°F", WindDirection: "N"}
+
+	tests := []struct {
+		lang     Language
+		facts    SummaryFacts
+		expected string
+	}{
+		{English, facts, "Light rain, cool at 12°C, feels like 9°C, winds 20 km/h from the northwest"},
+		{English, calm, "Clear sky, hot at 95°F, calm winds"},
+		{Spanish, SummaryFacts{Condition: "lluvia ligera", Category: "Fresco", Temperature: "12°C", FeelsLike: "9°C", WindSpeed: "20 km/h", WindDirection: "NW"},
+			"Lluvia ligera, fresco con 12°C, sensación térmica de 9°C, viento de 20 km/h del noroeste"},
+		{German, SummaryFacts{Condition: "leichter Regen", Category: "Kühl", Temperature: "12°C", WindSpeed: "20 km/h", WindDirection: "NW"},
+			"Leichter Regen, kühl bei 12°C, Wind mit 20 km/h aus Nordwest"},
+		{Japanese, SummaryFacts{Condition: "小雨", Category: "涼しい", Temperature: "12°C", FeelsLike: "9°C", WindSpeed: "20 km/h", WindDirection: "NW"},
+			"小雨、涼しい、気温12°C（体感9°C）、北西の風 20 km/h"},
+	}
+
+	for _, tc := range tests {
+		if summary := Summary(tc.lang, tc.facts); summary != tc.expected {
+			t.Errorf("Summary(%s) = %q; want %q", tc.lang, summary, tc.expected)
+		}
+	}
+}
+
+func TestNowcast(t *testing.T) {
+	window := 3 * time.Hour
+	tests := []struct {
+		lang     Language
+		facts    NowcastFacts
+		expected string
+	}{
+		{English, NowcastFacts{Window: window}, "No precipitation expected in the next 3 h"},
+		{English, NowcastFacts{Window: window, Kind: "rain", StartsIn: 20 * time.Minute, EndsIn: 65 * time.Minute}, "Rain starting in 20 min, lasting 45 min"},
+		{English, NowcastFacts{Window: window, Kind: "snow", StartsIn: 90 * time.Minute}, "Snow starting in 1 h 30 min"},
+		{English, NowcastFacts{Window: window, Kind: "rain", Raining: true, EndsIn: 40 * time.Minute}, "Rain stopping in 40 min"},
+		{English, NowcastFacts{Window: window, Kind: "rain", Raining: true}, "Rain continuing for at least 3 h"},
+		{Spanish, NowcastFacts{Window: window, Kind: "rain", Raining: true, EndsIn: 40 * time.Minute}, "La lluvia cesará dentro de 40 min"},
+		{German, NowcastFacts{Window: window}, "Kein Niederschlag in den nächsten 3 Std. erwartet"},
+		{Japanese, NowcastFacts{Window: window, Kind: "rain", StartsIn: 20 * time.Minute}, "20分後に雨が降り始める見込み"},
+	}
+
+	for _, tc := range tests {
+		if sentence := Nowcast(tc.lang, tc.facts); sentence != tc.expected {
+			t.Errorf("Nowcast(%s, %+v) = %q; want %q", tc.lang, tc.facts, sentence, tc.expected)
+		}
+	}
+}
+
+func TestFrost(t *testing.T) {
+	risk := FrostFacts{Nights: 5, AtRisk: true, Level: "High", Min: "-1°C", HoursBelowFreezing: 3, Freezing: "0°C"}
+	later := risk
+	later.Night, later.HoursBelowFreezing = 3, 0
+	tests := []struct {
+		lang     Language
+		facts    FrostFacts
+		expected string
+	}{
+		{English, FrostFacts{Nights: 5}, "No frost risk over the next 5 nights"},
+		{English, FrostFacts{Nights: 1}, "No frost risk tonight"},
+		{English, risk, "Frost risk tonight: High, low of -1°C, 3 h below 0°C"},
+		{English, later, "Frost risk in 3 nights: High, low of -1°C"},
+		{German, risk, "Frostrisiko heute Nacht: Hoch, Tiefstwert -1°C, 3 Std. unter 0°C"},
+		{Spanish, later, "Riesgo de helada dentro de 3 noches: Alto, mínima de -1°C"},
+		{Japanese, risk, "今夜の霜のリスク：高い、最低-1°C、0°C未満が3時間"},
+	}
+
+	for _, tc := range tests {
+		if sentence := Frost(tc.lang, tc.facts); sentence != tc.expected {
+			t.Errorf("Frost(%s, %+v) = %q; want %q", tc.lang, tc.facts, sentence, tc.expected)
+		}
+	}
+}
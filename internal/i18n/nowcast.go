@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nowcastMessages are the English nowcast sentences and their parts, keyed by message ID.
+var nowcastMessages = map[string]string{
+	"nowcast.dry":            "no precipitation expected in the next {window}",
+	"nowcast.starts":         "{kind} starting in {startsIn}",
+	"nowcast.startsAndStops": "{kind} starting in {startsIn}, lasting {duration}",
+	"nowcast.stops":          "{kind} stopping in {endsIn}",
+	"nowcast.continues":      "{kind} continuing for at least {window}",
+	"nowcast.kind.rain":      "rain",
+	"nowcast.kind.snow":      "snow",
+
+	"duration.minutes":      "{minutes} min",
+	"duration.hours":        "{hours} h",
+	"duration.hoursMinutes": "{hours} h {minutes} min",
+}
+
+// NowcastFacts describe the next precipitation spell within a nowcast window.
+type NowcastFacts struct {
+	Window   time.Duration // How far ahead the nowcast looks
+	Kind     string        // "rain" or "snow"; empty when no precipitation is expected
+	Raining  bool          // Precipitation is falling at the start of the window
+	StartsIn time.Duration // Until precipitation starts, when it is not already falling
+	EndsIn   time.Duration // Until precipitation stops; 0 when it lasts beyond the window
+}
+
+// Nowcast builds a one-sentence answer to "will it rain soon", such as "Rain starting in 20 min, lasting 45 min".
+func Nowcast(lang Language, facts NowcastFacts) string {
+	message := func(id string) string { return text(lang, id, nowcastMessages[id]) }
+
+	var id string
+	switch {
+	case facts.Kind == "":
+		id = "nowcast.dry"
+	case facts.Raining && facts.EndsIn > 0:
+		id = "nowcast.stops"
+	case facts.Raining:
+		id = "nowcast.continues"
+	case facts.EndsIn > 0:
+		id = "nowcast.startsAndStops"
+	default:
+		id = "nowcast.starts"
+	}
+
+	return capitalize(strings.NewReplacer(
+		"{kind}", message("nowcast.kind."+facts.Kind),
+		"{window}", formatDuration(lang, facts.Window),
+		"{startsIn}", formatDuration(lang, facts.StartsIn),
+		"{endsIn}", formatDuration(lang, facts.EndsIn),
+		"{duration}", formatDuration(lang, facts.EndsIn-facts.StartsIn),
+	).Replace(message(id)))
+}
+
+// formatDuration formats a duration in whole minutes, or hours and minutes from an hour on.
+func formatDuration(lang Language, d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	hours, minutes := minutes/60, minutes%60
+
+	id := "duration.hoursMinutes"
+	switch {
+	case hours == 0:
+		id = "duration.minutes"
+	case minutes == 0:
+		id = "duration.hours"
+	}
+	return strings.NewReplacer("{hours}", strconv.Itoa(hours), "{minutes}", strconv.Itoa(minutes)).
+		Replace(text(lang, id, nowcastMessages[id]))
+}
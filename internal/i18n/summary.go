@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// summaryMessages are the English sentence fragments of a weather summary, keyed by message ID. The
+// catalogs translate each one; placeholders in braces are filled in by Summary.
+var summaryMessages = map[string]string{
+	"summary.conditions": "{condition}, {category} at {temperature}",
+	"summary.feelsLike":  ", feels like {feelsLike}",
+	"summary.wind":       ", winds {speed} from the {direction}",
+	"summary.calm":       ", calm winds",
+
+	// Compass points the wind blows from
+	"direction.N":  "north",
+	"direction.NE": "northeast",
+	"direction.E":  "east",
+	"direction.SE": "southeast",
+	"direction.S":  "south",
+	"direction.SW": "southwest",
+	"direction.W":  "west",
+	"direction.NW": "northwest",
+}
+
+// SummaryFacts are the already localized and formatted values a summary is built from.
+type SummaryFacts struct {
+	Condition     string // Condition description, e.g. "light rain"
+	Category      string // Temperature category, e.g. "Cool"
+	Temperature   string // e.g. "12°C"
+	FeelsLike     string // Omitted from the summary when empty
+	WindSpeed     string // e.g. "20 km/h"; empty for calm winds
+	WindDirection string // Compass point such as "NW"
+}
+
+// Summary builds a one-sentence description of the weather, such as
+// "Light rain, cool at 12°C, feels like 9°C, winds 20 km/h from the northwest".
+func Summary(lang Language, facts SummaryFacts) string {
+	message := func(id string) string { return text(lang, id, summaryMessages[id]) }
+
+	var sentence strings.Builder
+	sentence.WriteString(message("summary.conditions"))
+	if facts.FeelsLike != "" {
+		sentence.WriteString(message("summary.feelsLike"))
+	}
+	if facts.WindSpeed != "" {
+		sentence.WriteString(message("summary.wind"))
+	} else {
+		sentence.WriteString(message("summary.calm"))
+	}
+
+	return strings.NewReplacer(
+		"{condition}", capitalize(facts.Condition),
+		"{category}", strings.ToLower(facts.Category),
+		"{temperature}", facts.Temperature,
+		"{feelsLike}", facts.FeelsLike,
+		"{speed}", facts.WindSpeed,
+		"{direction}", message("direction."+facts.WindDirection),
+	).Replace(sentence.String())
+}
+
+// capitalize upper-cases the first letter of s, as OpenWeatherMap descriptions are lower case.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(first)) + s[size:]
+}
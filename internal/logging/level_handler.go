@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// LevelsResponse is the body returned by LevelHandler.
+type LevelsResponse struct {
+	Default    string            `json:"default"`    // Level of components without a level of their own
+	Components map[string]string `json:"components"` // Current level per component
+}
+
+// LevelHandler reports the log levels on GET and changes them on PUT or POST, e.g.
+// PUT /loglevel?component=repo&level=debug. Omitting component changes the default level.
+func LevelHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(r.URL.Query().Get("level"))); err != nil {
+			http.Error(w, "Invalid or missing 'level' query parameter. Use debug, info, warn or error.", http.StatusBadRequest)
+			return
+		}
+		SetLevel(r.URL.Query().Get("component"), level)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defaultLevel, components := Levels()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LevelsResponse{Default: defaultLevel, Components: components})
+}
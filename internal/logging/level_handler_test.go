@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelHandler(t *testing.T) {
+	Logger("test-admin")
+	defer SetLevel("test-admin", slog.LevelInfo)
+
+	testCases := []struct {
+		name       string
+		method     string
+		query      string
+		wantStatus int
+		wantLevel  string
+	}{
+		{"Get Levels", "GET", "", http.StatusOK, "INFO"},
+		{"Set Debug", "PUT", "?component=test-admin&level=debug", http.StatusOK, "DEBUG"},
+		{"Invalid Level", "PUT", "?component=test-admin&level=loud", http.StatusBadRequest, ""},
+		{"Method Not Allowed", "DELETE", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.method, "/loglevel"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			LevelHandler(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantLevel == "" {
+				return
+			}
+
+			var response LevelsResponse
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tc.wantLevel, response.Components["test-admin"])
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/util"
+)
+
+// Options configures the shared output of all component loggers.
+type Options struct {
+	Format   string         // "text" (default) or "json"
+	Redactor *util.Redactor // Redacts sensitive values from every record; defaults to util.NewRedactor(nil, nil)
+}
+
+// component holds the runtime-adjustable settings of one named logger.
+type component struct {
+	level    slog.LevelVar
+	explicit bool                    // Level was set for this component rather than inherited from the default
+	sampler  atomic.Pointer[sampler] // Optional sampling of Info and lower records
+}
+
+var (
+	// output is the handler every component logger writes through; it is swapped by Init.
+	output atomic.Pointer[slog.Handler]
+
+	mu           sync.Mutex
+	defaultLevel = slog.LevelInfo
+	components   = make(map[string]*component)
+)
+
+func init() {
+	Init(os.Stderr, Options{})
+}
+
+// Init directs all component loggers, including those created earlier, to w using the given options.
+func Init(w io.Writer, opts Options) {
+	redactor := opts.Redactor
+	if redactor == nil {
+		redactor = util.NewRedactor(nil, nil)
+	}
+
+	// The component level decides what is logged, so the output handler accepts every level
+	handlerOpts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: redactor.ReplaceAttr}
+
+	var handler slog.Handler
+	if strings.EqualFold(opts.Format, "json") {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOpts)
+	}
+	output.Store(&handler)
+}
+
+// Logger returns the logger for the named component, e.g. "repo" or "access".
+func Logger(name string) *slog.Logger {
+	return slog.New(&componentHandler{
+		settings: lookup(name),
+		ops: []func(slog.Handler) slog.Handler{
+			func(h slog.Handler) slog.Handler { return h.WithAttrs([]slog.Attr{slog.String("component", name)}) },
+		},
+	})
+}
+
+// lookup returns the settings for the named component, registering it with the default level if needed.
+func lookup(name string) *component {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, ok := components[name]
+	if !ok {
+		c = &component{}
+		c.level.Set(defaultLevel)
+		components[name] = c
+	}
+	return c
+}
+
+// SetLevel changes the level of the named component at runtime. An empty name or "*" changes the default
+// level, which applies to every component that has no level of its own.
+func SetLevel(name string, level slog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" || name == "*" {
+		defaultLevel = level
+		for _, c := range components {
+			if !c.explicit {
+				c.level.Set(level)
+			}
+		}
+		return
+	}
+
+	c, ok := components[name]
+	if !ok {
+		c = &component{}
+		components[name] = c
+	}
+	c.level.Set(level)
+	c.explicit = true
+}
+
+// SetSampling limits the named component to the first initial Info-or-lower records per second and every
+// thereafter-th record after that. Warnings and errors are never sampled. An initial of 0 disables sampling.
+func SetSampling(name string, initial, thereafter int) {
+	c := lookup(name)
+	if initial <= 0 {
+		c.sampler.Store(nil)
+		return
+	}
+	c.sampler.Store(newSampler(initial, thereafter))
+}
+
+// Levels reports the default level and the current level of every registered component.
+func Levels() (string, map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	levels := make(map[string]string, len(components))
+	for name, c := range components {
+		levels[name] = c.level.Level().String()
+	}
+	return defaultLevel.String(), levels
+}
+
+// ApplyLevels applies a level specification such as "info,repo=debug,access=warn": a bare level sets the
+// default and name=level pairs set individual components.
+func ApplyLevels(spec string) error {
+	type assignment struct {
+		name  string
+		level slog.Level
+	}
+	var assignments []assignment
+
+	// Parse everything first so an invalid specification changes nothing
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, levelText, found := strings.Cut(item, "=")
+		if !found {
+			name, levelText = "", item
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(levelText))); err != nil {
+			return fmt.Errorf("invalid log level %q: %v", item, err)
+		}
+		assignments = append(assignments, assignment{strings.TrimSpace(name), level})
+	}
+
+	// Apply the default first so it does not matter where it appears in the specification
+	sort.SliceStable(assignments, func(i, j int) bool { return assignments[i].name == "" && assignments[j].name != "" })
+	for _, a := range assignments {
+		SetLevel(a.name, a.level)
+	}
+	return nil
+}
+
+// componentHandler filters records by its component's level and sampling, then writes them to the shared output.
+type componentHandler struct {
+	settings *component
+	ops      []func(slog.Handler) slog.Handler // WithAttrs/WithGroup calls replayed onto the current output
+	derived  atomic.Pointer[derivedHandler]    // ops applied to the output they were last replayed onto
+}
+
+// derivedHandler is an output handler with a component's WithAttrs/WithGroup calls applied.
+type derivedHandler struct {
+	base    *slog.Handler
+	handler slog.Handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.settings.level.Level()
+}
+
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	if s := h.settings.sampler.Load(); s != nil && record.Level <= slog.LevelInfo && !s.allow(record.Time) {
+		return nil
+	}
+
+	return h.handler().Handle(ctx, record)
+}
+
+// handler returns the output with the component's ops applied, replaying them only after Init swapped the output.
+func (h *componentHandler) handler() slog.Handler {
+	base := output.Load()
+	if d := h.derived.Load(); d != nil && d.base == base {
+		return d.handler
+	}
+
+	handler := *base
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	h.derived.Store(&derivedHandler{base: base, handler: handler})
+	return handler
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := append(append([]func(slog.Handler) slog.Handler{}, h.ops...), op)
+	return &componentHandler{settings: h.settings, ops: ops}
+}
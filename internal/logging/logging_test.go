@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureOutput directs all loggers to a buffer until the test finishes.
+func captureOutput(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	Init(&buf, Options{})
+	t.Cleanup(func() { Init(os.Stderr, Options{}) })
+	return &buf
+}
+
+func TestLogger_PerComponentLevels(t *testing.T) {
+	buf := captureOutput(t)
+
+	repoLogger := Logger("test-repo")
+	middlewareLogger := Logger("test-middleware")
+
+	SetLevel("test-repo", slog.LevelDebug)
+	defer SetLevel("test-repo", slog.LevelInfo)
+
+	repoLogger.Debug("upstream timing")
+	middlewareLogger.Debug("middleware detail")
+
+	assert.Contains(t, buf.String(), "upstream timing")
+	assert.Contains(t, buf.String(), "component=test-repo")
+	assert.NotContains(t, buf.String(), "middleware detail")
+}
+
+func TestLogger_InheritsDefaultLevel(t *testing.T) {
+	buf := captureOutput(t)
+	logger := Logger("test-inherit")
+
+	SetLevel("", slog.LevelWarn)
+	defer SetLevel("", slog.LevelInfo)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+func TestLogger_RedactsAPIKey(t *testing.T) {
+	buf := captureOutput(t)
+
+	Logger("test-redact").Info("upstream request", "url", "https://example.com/weather?appid=secret-key&lat=1")
+
+	assert.NotContains(t, buf.String(), "secret-key")
+	assert.Contains(t, buf.String(), "appid=REDACTED")
+}
+
+func TestLogger_WithAttrsSurvivesInit(t *testing.T) {
+	logger := Logger("test-with").With("requestId", "abc")
+
+	// Re-initializing the output must apply to loggers derived before it
+	buf := captureOutput(t)
+	logger.WithGroup("upstream").Info("request", "status", 200)
+
+	assert.Contains(t, buf.String(), "requestId=abc")
+	assert.Contains(t, buf.String(), "upstream.status=200")
+}
+
+func TestLogger_ReplaysOpsOnlyAfterInit(t *testing.T) {
+	buf := captureOutput(t)
+
+	replays := 0
+	handler := &componentHandler{settings: lookup("test-replay"), ops: []func(slog.Handler) slog.Handler{
+		func(h slog.Handler) slog.Handler {
+			replays++
+			return h.WithAttrs([]slog.Attr{slog.String("requestId", "abc")})
+		},
+	}}
+	logger := slog.New(handler)
+
+	logger.Info("first")
+	logger.Info("second")
+	assert.Equal(t, 1, replays)
+	assert.Equal(t, 2, strings.Count(buf.String(), "requestId=abc"))
+
+	// A new output invalidates the cached handler
+	buf = captureOutput(t)
+	logger.Info("third")
+	assert.Equal(t, 2, replays)
+	assert.Contains(t, buf.String(), "requestId=abc")
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	Init(&buf, Options{Format: "json"})
+	defer Init(os.Stderr, Options{})
+
+	Logger("test-json").Info("hello")
+
+	assert.True(t, strings.HasPrefix(buf.String(), "{"), "expected JSON output, got %s", buf.String())
+	assert.Contains(t, buf.String(), `"component":"test-json"`)
+}
+
+func TestApplyLevels(t *testing.T) {
+	defer SetLevel("", slog.LevelInfo)
+	defer SetLevel("test-apply-repo", slog.LevelInfo)
+
+	err := ApplyLevels("test-apply-repo=debug, warn")
+	assert.NoError(t, err)
+
+	defaultLevel, components := Levels()
+	assert.Equal(t, "WARN", defaultLevel)
+	assert.Equal(t, "DEBUG", components["test-apply-repo"])
+
+	assert.Error(t, ApplyLevels("repo=loud"))
+}
+
+func TestSetSampling(t *testing.T) {
+	buf := captureOutput(t)
+	logger := Logger("test-sampled")
+
+	SetSampling("test-sampled", 2, 3)
+	defer SetSampling("test-sampled", 0, 0)
+
+	for i := 0; i < 8; i++ {
+		logger.Info("access")
+	}
+	logger.Error("never sampled")
+
+	assert.LessOrEqual(t, strings.Count(buf.String(), "msg=access"), 4)
+	assert.GreaterOrEqual(t, strings.Count(buf.String(), "msg=access"), 2)
+	assert.Contains(t, buf.String(), "never sampled")
+}
+
+func TestSampler(t *testing.T) {
+	s := newSampler(2, 3)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var allowed []bool
+	for i := 0; i < 8; i++ {
+		allowed = append(allowed, s.allow(now))
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, allowed)
+
+	// A new one-second window starts counting again
+	assert.True(t, s.allow(now.Add(time.Second)))
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// sampler lets through the first initial records of every one-second window and every thereafter-th
+// record after that, so bursts of high-volume lines such as access logs stay bounded.
+type sampler struct {
+	initial    int
+	thereafter int
+
+	mu     sync.Mutex
+	window time.Time // Start of the current one-second window
+	count  int       // Records seen in the current window
+}
+
+func newSampler(initial, thereafter int) *sampler {
+	return &sampler{initial: initial, thereafter: thereafter}
+}
+
+// allow reports whether a record logged at t should be written.
+func (s *sampler) allow(t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if window := t.Truncate(time.Second); !window.Equal(s.window) {
+		s.window = window
+		s.count = 0
+	}
+	s.count++
+
+	if s.count <= s.initial {
+		return true
+	}
+	if s.thereafter <= 0 {
+		return false
+	}
+	return (s.count-s.initial)%s.thereafter == 0
+}
@@ -1,20 +1,27 @@
 package middleware
 
 import (
-	"log"
+	"io"
 	"net/http"
 	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/accesslog"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
 )
 
+// accessLogger writes one line per request; it is sampled under load, see logging.SetSampling.
+var accessLogger = logging.Logger("access")
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	size       int // Bytes written to the body
 }
 
-// NewResponseWriter creates a new response writer to capture the status code.
+// NewResponseWriter creates a new response writer to capture the status code and response size.
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
 	// Default the status code to 200 for cases where WriteHeader is not explicitly called.
-	return &responseWriter{w, http.StatusOK}
+	return &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -22,7 +29,26 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += n
+	return n, err
+}
+
+// LoggingMiddleware logs every request to the application log.
 func LoggingMiddleware(next http.Handler) http.Handler {
+	return LoggingMiddlewareWithAccessLog(nil)(next)
+}
+
+// LoggingMiddlewareWithAccessLog logs every request to the application log and, when accessLog is not nil,
+// also writes it to accessLog as an NCSA Combined Log Format line for Apache-style tooling.
+func LoggingMiddlewareWithAccessLog(accessLog io.Writer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return loggingHandler(next, accessLog)
+	}
+}
+
+func loggingHandler(next http.Handler, accessLog io.Writer) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
@@ -33,7 +59,16 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(wrappedWriter, r)
 
 		// Log the request details including the status code
-		log.Printf("Method: %s, URI: %s, Status: %d, Duration: %v",
-			r.Method, r.URL.Path, wrappedWriter.statusCode, time.Since(start))
+		accessLogger.InfoContext(r.Context(), "request",
+			"requestId", RequestIDFromContext(r.Context()),
+			"method", r.Method,
+			"uri", r.URL.Path,
+			"status", wrappedWriter.statusCode,
+			"duration", time.Since(start))
+
+		// The combined log is a complete record, so unlike the application log it is never sampled
+		if accessLog != nil {
+			io.WriteString(accessLog, accesslog.FormatCombined(r, wrappedWriter.statusCode, wrappedWriter.size, start))
+		}
 	})
 }
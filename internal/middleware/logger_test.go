@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -30,3 +32,50 @@ func TestLoggingMiddleware(t *testing.T) {
 	// Note: This test does not verify the log output, as capturing log output from the log package requires
 	// redirecting the output to a buffer and parsing it, which is beyond the scope of this basic test.
 }
+
+func TestLoggingMiddlewareWithAccessLog_Panic(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	// The order of the server: recovery inside the access log, so recovered panics are logged
+	var accessLog bytes.Buffer
+	middlewareHandler := LoggingMiddlewareWithAccessLog(&accessLog)(RecoveryMiddleware(testHandler))
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	middlewareHandler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusInternalServerError)
+	}
+	if line := accessLog.String(); !strings.Contains(line, `"GET /panic HTTP/1.1" 500 `) {
+		t.Errorf("access log lacks the 500 response of the panicking handler: %q", line)
+	}
+}
+
+func TestLoggingMiddlewareWithAccessLog(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+
+	var accessLog bytes.Buffer
+	middlewareHandler := LoggingMiddlewareWithAccessLog(&accessLog)(testHandler)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testpath?lat=1", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("User-Agent", "test-agent")
+
+	middlewareHandler.ServeHTTP(rr, req)
+
+	line := accessLog.String()
+	if !strings.HasPrefix(line, "192.0.2.1 - - [") {
+		t.Errorf("access log line does not start with the client host: %q", line)
+	}
+	if !strings.Contains(line, `"GET /testpath?lat=1 HTTP/1.1" 201 5 "-" "test-agent"`) {
+		t.Errorf("access log line is not in Combined Log Format: %q", line)
+	}
+}
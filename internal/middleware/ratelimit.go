@@ -11,8 +11,8 @@ import (
 func RateLimitMiddleware(rateLimitPerSecond int) func(http.Handler) http.Handler {
 	// Define a mutex to protect lastRequestTime
 	var mutex sync.Mutex
-	// Initialize lastRequestTime to the current time
-	lastRequestTime := time.Now()
+	// Start with the zero time so the very first request is never rejected
+	var lastRequestTime time.Time
 
 	// Calculate the limit duration based on the rate limit per second
 	limitDuration := time.Second / time.Duration(rateLimitPerSecond)
@@ -51,3 +51,20 @@ func TestRateLimitMiddleware(t *testing.T) {
 		t.Errorf("Request after rate limit duration was unexpectedly blocked")
 	}
 }
+
+func TestRateLimitMiddleware_FirstRequest(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// A request arriving right after startup must not be charged against a request that never happened
+	middlewareHandler := RateLimitMiddleware(1)(testHandler)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	middlewareHandler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Request right after startup was rate limited: got status %d", status)
+	}
+}
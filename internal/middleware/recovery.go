@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+var logger = logging.Logger("middleware")
+
+// PanicsRecovered counts handler panics caught by RecoveryMiddleware, published as an expvar.
+var PanicsRecovered = expvar.NewInt("panics_recovered_total")
+
+// recoveryWriter records whether the response has been started, in which case a clean 500 is no longer possible.
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoveryWriter) WriteHeader(code int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoveryWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// RecoveryMiddleware recovers panics from downstream handlers, logs them with their stack and request ID,
+// and responds with a problem-style 500 instead of dropping the connection.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wrappedWriter := &recoveryWriter{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses this sentinel to abort a response silently; it must keep propagating
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			PanicsRecovered.Add(1)
+			requestID := RequestIDFromContext(r.Context())
+			logger.ErrorContext(r.Context(), "recovered from panic",
+				"requestId", requestID,
+				"method", r.Method,
+				"uri", r.URL.Path,
+				"panic", fmt.Sprint(recovered),
+				"stack", string(debug.Stack()))
+
+			// Part of the response is already on the wire, so the only safe option left is to abort it
+			if wrappedWriter.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+
+			problem := model.ProblemDetails{
+				Type:      "about:blank",
+				Title:     http.StatusText(http.StatusInternalServerError),
+				Status:    http.StatusInternalServerError,
+				Detail:    "An unexpected error occurred.",
+				Instance:  r.URL.Path,
+				RequestID: requestID,
+			}
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(problem)
+		}()
+
+		next.ServeHTTP(wrappedWriter, r)
+	})
+}
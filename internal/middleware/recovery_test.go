@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	// Capture the log output to verify the panic is logged with its request ID and stack
+	var logOutput bytes.Buffer
+	logging.Init(&logOutput, logging.Options{})
+	defer logging.Init(os.Stderr, logging.Options{})
+
+	panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data map[string]string
+		data["boom"] = "nil map" // Panics with an assignment to a nil map
+	})
+
+	middlewareHandler := RequestIDMiddleware(RecoveryMiddleware(panicHandler))
+
+	before := PanicsRecovered.Value()
+
+	req, _ := http.NewRequest("GET", "/api/v1/weather", nil)
+	req.Header.Set(RequestIDHeader, "panic-request")
+	rr := httptest.NewRecorder()
+	middlewareHandler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("handler returned wrong content type: got %q", contentType)
+	}
+
+	var problem model.ProblemDetails
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("response is not a problem document: %v", err)
+	}
+	if problem.Status != http.StatusInternalServerError || problem.RequestID != "panic-request" || problem.Instance != "/api/v1/weather" {
+		t.Errorf("unexpected problem document: %+v", problem)
+	}
+	if strings.Contains(rr.Body.String(), "nil map") {
+		t.Errorf("response leaks the panic value: %s", rr.Body.String())
+	}
+
+	if got := PanicsRecovered.Value() - before; got != 1 {
+		t.Errorf("panic counter increased by %d, want 1", got)
+	}
+	if !strings.Contains(logOutput.String(), "panic-request") || !strings.Contains(logOutput.String(), "goroutine") {
+		t.Errorf("log output is missing the request ID or stack trace:\n%s", logOutput.String())
+	}
+}
+
+func TestRecoveryMiddleware_RepanicsErrAbortHandler(t *testing.T) {
+	abortHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to propagate, got %v", recovered)
+		}
+	}()
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	RecoveryMiddleware(abortHandler).ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRecoveryMiddleware_NoPanic(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	rr := httptest.NewRecorder()
+	RecoveryMiddleware(testHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seenID string
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = RequestIDFromContext(r.Context())
+	})
+
+	middlewareHandler := RequestIDMiddleware(testHandler)
+
+	t.Run("Generates ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		rr := httptest.NewRecorder()
+		middlewareHandler.ServeHTTP(rr, req)
+
+		if seenID == "" {
+			t.Fatalf("expected a generated request ID in the context")
+		}
+		if header := rr.Header().Get(RequestIDHeader); header != seenID {
+			t.Errorf("response header %s = %q, want %q", RequestIDHeader, header, seenID)
+		}
+	})
+
+	t.Run("Reuses Client ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "client-id-123")
+		rr := httptest.NewRecorder()
+		middlewareHandler.ServeHTTP(rr, req)
+
+		if seenID != "client-id-123" {
+			t.Errorf("request ID = %q, want client-id-123", seenID)
+		}
+	})
+
+	t.Run("Replaces Malformed Client ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+		rr := httptest.NewRecorder()
+		middlewareHandler.ServeHTTP(rr, req)
+
+		if seenID == "bad id\nwith newline" || seenID == "" {
+			t.Errorf("malformed request ID was not replaced, got %q", seenID)
+		}
+	})
+}
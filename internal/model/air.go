@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/airquality"
+)
+
+// AirPollutionData is the response of OpenWeatherMap's air pollution API, current or forecast.
+type AirPollutionData struct {
+	Coord Coordinates        `json:"coord"`
+	List  []AirPollutionItem `json:"list"`
+}
+
+// AirPollutionItem is the air pollution of one hour.
+type AirPollutionItem struct {
+	Dt   int64 `json:"dt"` // Unix, UTC
+	Main struct {
+		AQI int `json:"aqi"` // OpenWeatherMap's own index, 1 (good) to 5 (very poor)
+	} `json:"main"`
+	Components AirComponents `json:"components"`
+}
+
+// AirComponents are pollutant concentrations in μg/m³.
+type AirComponents struct {
+	CO   float64 `json:"co"`
+	NO   float64 `json:"no"`
+	NO2  float64 `json:"no2"`
+	O3   float64 `json:"o3"`
+	SO2  float64 `json:"so2"`
+	PM25 float64 `json:"pm2_5"`
+	PM10 float64 `json:"pm10"`
+	NH3  float64 `json:"nh3"`
+}
+
+// Concentrations returns the concentrations of the pollutants the air quality indices cover.
+func (c AirComponents) Concentrations() airquality.Concentrations {
+	return airquality.Concentrations{PM25: c.PM25, PM10: c.PM10, O3: c.O3, NO2: c.NO2, SO2: c.SO2, CO: c.CO}
+}
+
+// AirQualityResponse describes the current air quality at a location and its hourly forecast.
+type AirQualityResponse struct {
+	Coordinates Coordinates  `json:"coordinates"`
+	Current     AirQuality   `json:"current"`
+	Forecast    []AirQuality `json:"forecast"`
+}
+
+// AirQuality is the air quality of one hour.
+type AirQuality struct {
+	Time       time.Time        `json:"time"`
+	Components AirComponents    `json:"components"` // μg/m³
+	USAQI      airquality.Index `json:"usAqi"`
+	CAQI       airquality.Index `json:"caqi"`
+	OWMIndex   int              `json:"owmIndex"` // OpenWeatherMap's 1-5 index, for comparison
+}
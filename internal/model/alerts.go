@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// Alert sources
+const (
+	AlertSourceOpenWeatherMap = "owm" // Relayed by the One Call API
+	AlertSourceNWS            = "nws" // US National Weather Service
+)
+
+// WeatherAlert is an official weather warning issued for the location. Severity, urgency and certainty use the
+// Common Alerting Protocol values; for alerts relayed by OpenWeatherMap, which reports none, they are derived
+// from the event name and times.
+type WeatherAlert struct {
+	ID          string    `json:"id,omitempty"`
+	Source      string    `json:"source"`
+	Sender      string    `json:"sender"`
+	Event       string    `json:"event"`
+	Headline    string    `json:"headline,omitempty"`
+	Severity    string    `json:"severity"`  // Extreme, Severe, Moderate, Minor or Unknown
+	Urgency     string    `json:"urgency"`   // Immediate, Expected, Future, Past or Unknown
+	Certainty   string    `json:"certainty"` // Observed, Likely, Possible, Unlikely or Unknown
+	Onset       time.Time `json:"onset"`
+	Expires     time.Time `json:"expires"`
+	Description string    `json:"description"`
+	Instruction string    `json:"instruction,omitempty"`
+	Area        string    `json:"area,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+}
+
+// AlertsResponse lists the active alerts for a point, most severe first.
+type AlertsResponse struct {
+	Coordinates Coordinates    `json:"coordinates"`
+	Source      string         `json:"source"`
+	Alerts      []WeatherAlert `json:"alerts"`
+}
+
+// NWSAlertCollection is the GeoJSON response of the api.weather.gov active alerts endpoint.
+type NWSAlertCollection struct {
+	Features []struct {
+		Properties NWSAlert `json:"properties"`
+	} `json:"features"`
+}
+
+// NWSAlert is the CAP-based description of one National Weather Service alert.
+type NWSAlert struct {
+	ID          string     `json:"id"`
+	AreaDesc    string     `json:"areaDesc"`
+	Sent        *time.Time `json:"sent"`
+	Effective   *time.Time `json:"effective"`
+	Onset       *time.Time `json:"onset"`
+	Expires     *time.Time `json:"expires"`
+	Ends        *time.Time `json:"ends"`
+	Status      string     `json:"status"`
+	MessageType string     `json:"messageType"`
+	Category    string     `json:"category"`
+	Severity    string     `json:"severity"`
+	Certainty   string     `json:"certainty"`
+	Urgency     string     `json:"urgency"`
+	Event       string     `json:"event"`
+	SenderName  string     `json:"senderName"`
+	Headline    string     `json:"headline"`
+	Description string     `json:"description"`
+	Instruction string     `json:"instruction"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/astro"
+)
+
+// AstronomyResponse describes the sun and moon at a location on a date.
+type AstronomyResponse struct {
+	Date       string         `json:"date"` // YYYY-MM-DD in local mean solar time
+	Latitude   float64        `json:"lat"`
+	Longitude  float64        `json:"lon"`
+	ComputedAt time.Time      `json:"computedAt"` // Instant of the sun position and moon phase
+	Sun        SunInfo        `json:"sun"`
+	Moon       astro.MoonInfo `json:"moon"`
+}
+
+// SunInfo combines the solar events of the date with the sun's position at ComputedAt.
+type SunInfo struct {
+	astro.SunTimes
+	Position astro.Position `json:"position"`
+}
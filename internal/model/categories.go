@@ -0,0 +1,9 @@
+package model
+
+import "github.com/golang2go/demo-app/weather-service-api/internal/category"
+
+// CategorySchemesResponse lists the temperature category schemes a client can select with ?scheme=.
+type CategorySchemesResponse struct {
+	Default string            `json:"default"`
+	Schemes []category.Scheme `json:"schemes"`
+}
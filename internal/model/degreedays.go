@@ -0,0 +1,42 @@
+package model
+
+// DegreeDaysResponse holds the heating, cooling and growing degree-days of each day of a date range at a
+// location, with their running totals.
+type DegreeDaysResponse struct {
+	Coordinates Coordinates  `json:"coordinates"`
+	From        string       `json:"from"` // First day, YYYY-MM-DD in local time
+	To          string       `json:"to"`   // Last day, inclusive
+	Method      string       `json:"method"`
+	Unit        string       `json:"unit"` // Temperature unit of the thresholds and degree-days
+	Base        float64      `json:"base"` // Base of heating and cooling degree-days
+	Growing     GrowingBase  `json:"growing"`
+	Source      string       `json:"source"`
+	Days        []DegreeDay  `json:"days"`
+	Total       DegreeTotals `json:"total"`
+	MissingDays []string     `json:"missingDays"` // Days without enough observations, left out of the series
+}
+
+// GrowingBase holds the thresholds of growing degree-days.
+type GrowingBase struct {
+	Crop    string   `json:"crop,omitempty"` // Omitted when both thresholds were given
+	Base    float64  `json:"base"`
+	Ceiling *float64 `json:"ceiling"` // Temperatures above it count as the ceiling; null for none
+}
+
+// DegreeDay holds the degree-days of one day.
+type DegreeDay struct {
+	Date       string       `json:"date"`
+	Min        float64      `json:"min"`
+	Max        float64      `json:"max"`
+	Heating    float64      `json:"heating"`
+	Cooling    float64      `json:"cooling"`
+	Growing    float64      `json:"growing"`
+	Cumulative DegreeTotals `json:"cumulative"` // Totals from the first day up to and including this one
+}
+
+// DegreeTotals are sums of heating, cooling and growing degree-days.
+type DegreeTotals struct {
+	Heating float64 `json:"heating"`
+	Cooling float64 `json:"cooling"`
+	Growing float64 `json:"growing"`
+}
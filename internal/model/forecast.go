@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// ForecastData is OpenWeatherMap's 5 day forecast in 3-hour steps.
+type ForecastData struct {
+	List []ForecastItem `json:"list"`
+	City ForecastCity   `json:"city"`
+}
+
+// ForecastItem is one 3-hour step of the forecast.
+type ForecastItem struct {
+	Dt         int64              `json:"dt"` // Time of the forecast step (Unix, UTC)
+	Main       MainInfo           `json:"main"`
+	Weather    []WeatherCondition `json:"weather"`
+	Clouds     Clouds             `json:"clouds"`
+	Wind       Wind               `json:"wind"`
+	Visibility *float64           `json:"visibility,omitempty"`
+	Pop        float64            `json:"pop"`            // Probability of precipitation, 0-1
+	Rain       *Precipitation     `json:"rain,omitempty"` // Volume for the 3 hours up to Dt
+	Snow       *Precipitation     `json:"snow,omitempty"` // Volume for the 3 hours up to Dt
+}
+
+type ForecastCity struct {
+	ID       int64       `json:"id"`
+	Name     string      `json:"name"`
+	Coord    Coordinates `json:"coord"`
+	Country  string      `json:"country"`
+	Timezone int         `json:"timezone"` // Shift in seconds from UTC
+	Sunrise  int64       `json:"sunrise"`
+	Sunset   int64       `json:"sunset"`
+}
+
+// ForecastResponse is the hourly and daily forecast for a location.
+type ForecastResponse struct {
+	Location       string         `json:"location"` // City name reported by OpenWeatherMap
+	Country        string         `json:"country"`
+	Coordinates    Coordinates    `json:"coordinates"`
+	TimezoneOffset int            `json:"timezoneOffset"` // Shift in seconds from UTC
+	Units          UnitLabels     `json:"units"`
+	Hourly         []ForecastHour `json:"hourly"`
+	Daily          []ForecastDay  `json:"daily"`
+}
+
+// ForecastHour is the forecast for one hour, interpolated between OpenWeatherMap's 3-hour steps.
+type ForecastHour struct {
+	Time             time.Time `json:"time"`
+	Temperature      float64   `json:"temperature"`
+	FeelsLike        float64   `json:"feelsLike"`
+	Humidity         float64   `json:"humidity"`
+	Pressure         float64   `json:"pressure"`
+	WindSpeed        float64   `json:"windSpeed"`
+	WindDirection    float64   `json:"windDirection"`
+	Cloudiness       float64   `json:"cloudiness"`
+	Precipitation    float64   `json:"precipitation"` // Rain and snow in the hour up to Time
+	Pop              float64   `json:"pop"`           // Probability of precipitation of the enclosing 3-hour step
+	WeatherCondition string    `json:"weatherCondition"`
+	TempCategory     string    `json:"tempCategory"`
+	Interpolated     bool      `json:"interpolated"` // False for hours that are OpenWeatherMap steps
+}
+
+// ForecastDay aggregates the 3-hour steps of one local calendar day.
+type ForecastDay struct {
+	Date             string  `json:"date"` // YYYY-MM-DD in the location's timezone
+	TempMin          float64 `json:"tempMin"`
+	TempMax          float64 `json:"tempMax"`
+	WeatherCondition string  `json:"weatherCondition"` // Most frequent condition, the more severe on a tie
+	TempCategory     string  `json:"tempCategory"`     // Category of the day's high
+	Precipitation    float64 `json:"precipitation"`    // Total rain and snow
+	MaxPop           float64 `json:"maxPop"`           // Highest probability of precipitation
+	Steps            int     `json:"steps"`            // 3-hour steps covered; fewer than 8 for partial days
+}
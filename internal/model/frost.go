@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Frost risk sources
+const (
+	FrostSourceForecast = "forecast" // OpenWeatherMap's 5 day forecast in 3-hour steps
+	FrostSourceOneCall  = "onecall"  // One Call hourly forecast for the next 48 hours, then the 5 day forecast
+)
+
+// FrostRiskResponse assesses the frost risk of each upcoming night at a location.
+type FrostRiskResponse struct {
+	Coordinates    Coordinates  `json:"coordinates"`
+	TimezoneOffset int          `json:"timezoneOffset"` // Shift in seconds from UTC
+	Source         string       `json:"source"`
+	Scheme         string       `json:"scheme"` // Category scheme of the risk levels
+	Units          UnitLabels   `json:"units"`
+	Summary        string       `json:"summary"` // One sentence on the riskiest night, short enough for a push notification
+	Nights         []FrostNight `json:"nights"`
+}
+
+// FrostNight is the frost risk of one night, from 18:00 to 09:00 local time.
+type FrostNight struct {
+	Date                 string    `json:"date"` // YYYY-MM-DD of the evening the night starts on
+	Start                time.Time `json:"start"`
+	End                  time.Time `json:"end"`
+	MinTemperature       float64   `json:"minTemperature"` // Expected minimum at plant level
+	MinTime              time.Time `json:"minTime"`
+	AirTemperature       float64   `json:"airTemperature"`       // Forecast air temperature at MinTime
+	RadiativeCooling     float64   `json:"radiativeCooling"`     // How far plants are expected to cool below the air
+	DewPoint             float64   `json:"dewPoint"`             // At MinTime
+	Cloudiness           float64   `json:"cloudiness"`           // At MinTime
+	WindSpeed            float64   `json:"windSpeed"`            // At MinTime
+	HoursBelowFreezing   int       `json:"hoursBelowFreezing"`   // Hours expected below 0 °C
+	HoursBelowHardFreeze int       `json:"hoursBelowHardFreeze"` // Hours expected below -2 °C
+	RiskLevel            string    `json:"riskLevel"`            // Band of the scheme the expected minimum falls into
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// History sources
+const (
+	HistorySourceRecorded = "recorded" // Observations this service recorded from its own upstream fetches
+	HistorySourceUpstream = "upstream" // OpenWeatherMap's history API
+	HistorySourceMixed    = "mixed"    // Recorded observations completed from the history API
+)
+
+// HistoryData is the response of OpenWeatherMap's hourly history API. Its items have the shape of current
+// weather data, without coordinates or timezone.
+type HistoryData struct {
+	Cnt  int           `json:"cnt"`
+	List []WeatherData `json:"list"`
+}
+
+// HistoryResponse is one page of past observations at a location.
+type HistoryResponse struct {
+	Coordinates Coordinates    `json:"coordinates"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	Interval    string         `json:"interval"`
+	Source      string         `json:"source"`
+	Units       UnitLabels     `json:"units"`
+	Points      []HistoryPoint `json:"points"`
+	Next        string         `json:"next,omitempty"` // Request for the next page; omitted on the last page
+}
+
+// HistoryPoint aggregates the observations of one interval.
+type HistoryPoint struct {
+	Time          time.Time `json:"time"`  // Start of the interval, or the time of a raw observation
+	Count         int       `json:"count"` // Observations aggregated
+	Temperature   Aggregate `json:"temperature"`
+	FeelsLike     Aggregate `json:"feelsLike"`
+	Humidity      Aggregate `json:"humidity"`
+	Pressure      Aggregate `json:"pressure"`
+	WindSpeed     Aggregate `json:"windSpeed"`
+	Cloudiness    Aggregate `json:"cloudiness"`
+	Precipitation Aggregate `json:"precipitation"` // Per hour
+}
+
+// Aggregate is the minimum, maximum and mean of a quantity over an interval.
+type Aggregate struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Nowcast data sources
+const (
+	NowcastSourceForecast = "forecast" // 3-hour forecast steps only
+	NowcastSourceMinutely = "minutely" // One Call minutely data for the first hour, then forecast steps
+)
+
+// NowcastResponse answers when precipitation will start or stop within the next hours.
+type NowcastResponse struct {
+	Source                string     `json:"source"`
+	From                  time.Time  `json:"from"`
+	Until                 time.Time  `json:"until"`
+	PrecipitationNow      bool       `json:"precipitationNow"`
+	Kind                  string     `json:"kind,omitempty"`                  // rain or snow
+	PrecipitationStartsAt *time.Time `json:"precipitationStartsAt,omitempty"` // Omitted when it is already falling or not expected
+	EndsAt                *time.Time `json:"endsAt,omitempty"`                // Omitted when it lasts beyond Until or is not expected
+	PeakIntensity         *Intensity `json:"peakIntensity,omitempty"`
+	Confidence            float64    `json:"confidence"` // Probability of the answer, 0-1
+	Summary               string     `json:"summary"`
+}
+
+// Intensity is a precipitation rate and its class.
+type Intensity struct {
+	Rate  float64   `json:"rate"`
+	Unit  string    `json:"unit"`  // e.g. "mm/h"
+	Level string    `json:"level"` // light, moderate, heavy or violent
+	At    time.Time `json:"at"`
+}
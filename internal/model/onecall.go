@@ -0,0 +1,143 @@
+package model
+
+// OneCallData is the response of OpenWeatherMap's One Call API. Blocks excluded from the request are empty.
+type OneCallData struct {
+	Lat            float64                 `json:"lat"`
+	Lon            float64                 `json:"lon"`
+	Timezone       string                  `json:"timezone"`        // IANA timezone name
+	TimezoneOffset int                     `json:"timezone_offset"` // Shift in seconds from UTC
+	Current        *OneCallCurrent         `json:"current,omitempty"`
+	Minutely       []MinutelyPrecipitation `json:"minutely,omitempty"`
+	Hourly         []OneCallHourly         `json:"hourly,omitempty"`
+	Daily          []OneCallDaily          `json:"daily,omitempty"`
+	Alerts         []OneCallAlert          `json:"alerts,omitempty"`
+}
+
+// OneCallCurrent is the current conditions block of the One Call API.
+type OneCallCurrent struct {
+	Dt         int64              `json:"dt"`      // Time of data calculation (Unix, UTC)
+	Sunrise    int64              `json:"sunrise"` // Unix, UTC; absent during polar day and night
+	Sunset     int64              `json:"sunset"`
+	Temp       float64            `json:"temp"`
+	FeelsLike  float64            `json:"feels_like"`
+	Pressure   float64            `json:"pressure"` // Sea level pressure (hPa)
+	Humidity   float64            `json:"humidity"`
+	DewPoint   float64            `json:"dew_point"`
+	Clouds     float64            `json:"clouds"` // Cloudiness (%)
+	UVI        float64            `json:"uvi"`
+	Visibility *float64           `json:"visibility,omitempty"`
+	WindSpeed  float64            `json:"wind_speed"`
+	WindDeg    float64            `json:"wind_deg"`
+	WindGust   *float64           `json:"wind_gust,omitempty"`
+	Rain       *Precipitation     `json:"rain,omitempty"` // Volume for the last hour
+	Snow       *Precipitation     `json:"snow,omitempty"`
+	Weather    []WeatherCondition `json:"weather"`
+}
+
+// MinutelyPrecipitation is the precipitation forecast for one minute of the coming hour.
+type MinutelyPrecipitation struct {
+	Dt            int64   `json:"dt"`            // Start of the minute (Unix, UTC)
+	Precipitation float64 `json:"precipitation"` // Precipitation rate (mm/h)
+}
+
+// OneCallHourly is the forecast for one of the next 48 hours.
+type OneCallHourly struct {
+	Dt         int64              `json:"dt"`
+	Temp       float64            `json:"temp"`
+	FeelsLike  float64            `json:"feels_like"`
+	Pressure   float64            `json:"pressure"`
+	Humidity   float64            `json:"humidity"`
+	DewPoint   float64            `json:"dew_point"`
+	Clouds     float64            `json:"clouds"`
+	UVI        float64            `json:"uvi"`
+	Visibility *float64           `json:"visibility,omitempty"`
+	WindSpeed  float64            `json:"wind_speed"`
+	WindDeg    float64            `json:"wind_deg"`
+	WindGust   *float64           `json:"wind_gust,omitempty"`
+	Pop        float64            `json:"pop"`            // Probability of precipitation, 0-1
+	Rain       *Precipitation     `json:"rain,omitempty"` // Volume for the hour
+	Snow       *Precipitation     `json:"snow,omitempty"`
+	Weather    []WeatherCondition `json:"weather"`
+}
+
+// OneCallDaily is the forecast for one of the next 8 days.
+type OneCallDaily struct {
+	Dt        int64              `json:"dt"` // Midday of the day (Unix, UTC)
+	Sunrise   int64              `json:"sunrise"`
+	Sunset    int64              `json:"sunset"`
+	Moonrise  int64              `json:"moonrise"`
+	Moonset   int64              `json:"moonset"`
+	MoonPhase float64            `json:"moon_phase"` // 0 and 1 new moon, 0.5 full moon
+	Summary   string             `json:"summary"`    // Human-readable description of the day
+	Temp      DailyTemperature   `json:"temp"`
+	FeelsLike DailyFeelsLike     `json:"feels_like"`
+	Pressure  float64            `json:"pressure"`
+	Humidity  float64            `json:"humidity"`
+	DewPoint  float64            `json:"dew_point"`
+	WindSpeed float64            `json:"wind_speed"`
+	WindDeg   float64            `json:"wind_deg"`
+	WindGust  *float64           `json:"wind_gust,omitempty"`
+	Clouds    float64            `json:"clouds"`
+	UVI       float64            `json:"uvi"`
+	Pop       float64            `json:"pop"`
+	Rain      *float64           `json:"rain,omitempty"` // Volume for the day (mm)
+	Snow      *float64           `json:"snow,omitempty"`
+	Weather   []WeatherCondition `json:"weather"`
+}
+
+type DailyTemperature struct {
+	Morn  float64 `json:"morn"`
+	Day   float64 `json:"day"`
+	Eve   float64 `json:"eve"`
+	Night float64 `json:"night"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+type DailyFeelsLike struct {
+	Morn  float64 `json:"morn"`
+	Day   float64 `json:"day"`
+	Eve   float64 `json:"eve"`
+	Night float64 `json:"night"`
+}
+
+// OneCallAlert is a national weather alert as relayed by OpenWeatherMap.
+type OneCallAlert struct {
+	SenderName  string   `json:"sender_name"`
+	Event       string   `json:"event"`
+	Start       int64    `json:"start"` // Unix, UTC
+	End         int64    `json:"end"`   // Unix, UTC
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"` // Type of severe weather, e.g. "Flood"
+}
+
+// CurrentWeatherData converts the current conditions and alerts of a One Call response to the current weather
+// model, so both APIs can serve the weather endpoint. It returns false when the current block was excluded.
+func (d OneCallData) CurrentWeatherData() (WeatherData, bool) {
+	current := d.Current
+	if current == nil {
+		return WeatherData{}, false
+	}
+
+	return WeatherData{
+		Coord:   Coordinates{Lat: d.Lat, Lon: d.Lon},
+		Weather: current.Weather,
+		Main: MainInfo{
+			Temp:      current.Temp,
+			FeelsLike: current.FeelsLike,
+			TempMin:   current.Temp, // One Call reports no spread of current temperatures
+			TempMax:   current.Temp,
+			Pressure:  current.Pressure,
+			Humidity:  current.Humidity,
+		},
+		Visibility: current.Visibility,
+		Wind:       Wind{Speed: current.WindSpeed, Deg: current.WindDeg, Gust: current.WindGust},
+		Clouds:     Clouds{All: current.Clouds},
+		Rain:       current.Rain,
+		Snow:       current.Snow,
+		Dt:         current.Dt,
+		Sys:        SysInfo{Sunrise: current.Sunrise, Sunset: current.Sunset},
+		Timezone:   d.TimezoneOffset,
+		Alerts:     d.Alerts,
+	}, true
+}
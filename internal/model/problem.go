@@ -0,0 +1,11 @@
+package model
+
+// ProblemDetails is an RFC 7807 (application/problem+json) error response.
+type ProblemDetails struct {
+	Type      string `json:"type"`                // URI reference identifying the problem type
+	Title     string `json:"title"`               // Short, human-readable summary of the problem type
+	Status    int    `json:"status"`              // HTTP status code
+	Detail    string `json:"detail,omitempty"`    // Human-readable explanation of this occurrence
+	Instance  string `json:"instance,omitempty"`  // URI reference identifying this occurrence
+	RequestID string `json:"requestId,omitempty"` // Request ID to quote when reporting the problem
+}
@@ -1,19 +1,161 @@
 package model
 
+import (
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/taxonomy"
+)
+
+type Coordinates struct {
+	Lat float64 `json:"lat"` // Latitude of the location
+	Lon float64 `json:"lon"` // Longitude of the location
+}
+
 type MainInfo struct {
-	Temp float64 `json:"temp"` // Temperature (based on unit of measurement param)
+	Temp        float64  `json:"temp"`                 // Temperature (based on unit of measurement param)
+	FeelsLike   float64  `json:"feels_like"`           // Temperature accounting for human perception
+	TempMin     float64  `json:"temp_min"`             // Minimum temperature currently observed within the area
+	TempMax     float64  `json:"temp_max"`             // Maximum temperature currently observed within the area
+	Pressure    float64  `json:"pressure"`             // Atmospheric pressure (hPa)
+	Humidity    float64  `json:"humidity"`             // Relative humidity (%)
+	SeaLevel    *float64 `json:"sea_level,omitempty"`  // Atmospheric pressure at sea level (hPa)
+	GroundLevel *float64 `json:"grnd_level,omitempty"` // Atmospheric pressure at ground level (hPa)
 }
 
 type WeatherCondition struct {
-	Main string `json:"main"` // Main weather condition (e.g., Clear, Clouds, Rain)
+	ID          int    `json:"id"`          // OpenWeatherMap condition ID (2xx-8xx)
+	Main        string `json:"main"`        // Main weather condition (e.g., Clear, Clouds, Rain)
+	Description string `json:"description"` // Condition within the group (e.g., light rain)
+	Icon        string `json:"icon"`        // Icon ID, suffixed d (day) or n (night)
+}
+
+type Wind struct {
+	Speed float64  `json:"speed"`          // Wind speed (m/s for standard/metric, mph for imperial)
+	Deg   float64  `json:"deg"`            // Wind direction (meteorological degrees)
+	Gust  *float64 `json:"gust,omitempty"` // Wind gust (same unit as Speed)
+}
+
+type Clouds struct {
+	All float64 `json:"all"` // Cloudiness (%)
+}
+
+type Precipitation struct {
+	OneHour    *float64 `json:"1h,omitempty"` // Volume for the last hour (mm)
+	ThreeHours *float64 `json:"3h,omitempty"` // Volume for the last three hours (mm)
+}
+
+type SysInfo struct {
+	Country string `json:"country"` // Country code (e.g., US)
+	Sunrise int64  `json:"sunrise"` // Sunrise time (Unix, UTC)
+	Sunset  int64  `json:"sunset"`  // Sunset time (Unix, UTC)
 }
 
 type WeatherData struct {
-	Main    MainInfo           `json:"main"`
-	Weather []WeatherCondition `json:"weather"`
+	Coord      Coordinates        `json:"coord"`
+	Weather    []WeatherCondition `json:"weather"`
+	Base       string             `json:"base"`
+	Main       MainInfo           `json:"main"`
+	Visibility *float64           `json:"visibility,omitempty"` // Visibility (m), at most 10 km
+	Wind       Wind               `json:"wind"`
+	Clouds     Clouds             `json:"clouds"`
+	Rain       *Precipitation     `json:"rain,omitempty"`
+	Snow       *Precipitation     `json:"snow,omitempty"`
+	Dt         int64              `json:"dt"`               // Time of data calculation (Unix, UTC)
+	Sys        SysInfo            `json:"sys"`              // Country, sunrise and sunset
+	Timezone   int                `json:"timezone"`         // Shift in seconds from UTC
+	ID         int64              `json:"id"`               // City ID
+	Name       string             `json:"name"`             // City or station name
+	Alerts     []OneCallAlert     `json:"alerts,omitempty"` // Only reported when fetched through the One Call API
 }
 
 type WeatherResponse struct {
-	WeatherCondition string `json:"weatherCondition"` // Current weather condition
-	TempCategory     string `json:"tempCategory"`     // Temperature category (hot, cold, moderate)
+	WeatherCondition string               `json:"weatherCondition"`     // Current weather condition
+	TempCategory     string               `json:"tempCategory"`         // Temperature category (hot, cold, moderate)
+	Conditions       []taxonomy.Condition `json:"conditions,omitempty"` // Every concurrent condition, primary first
+	IsDaytime        *bool                `json:"isDaytime,omitempty"`  // Omitted when it cannot be determined
+	LocalTime        *LocalTime           `json:"localTime,omitempty"`  // Omitted when no observation time is reported
+	Details          *WeatherDetails      `json:"details,omitempty"`    // Full current conditions, only with ?detail=full
+	Summary          string               `json:"summary,omitempty"`    // Localized sentence, only with ?summary=true
+	Alerts           []WeatherAlert       `json:"alerts,omitempty"`     // Only when current conditions come from One Call
+}
+
+// EventTime is an instant in both UTC and the location's local time.
+type EventTime struct {
+	UTC   time.Time `json:"utc"`
+	Local time.Time `json:"local"` // ISO-8601 with the location's UTC offset
+}
+
+// LocalTime places the observation in the location's timezone.
+type LocalTime struct {
+	ObservedAt       time.Time  `json:"observedAt"` // ISO-8601 with the location's UTC offset
+	UTCOffset        string     `json:"utcOffset"`  // e.g. "+09:00"
+	UTCOffsetSeconds int        `json:"utcOffsetSeconds"`
+	Sunrise          *EventTime `json:"sunrise,omitempty"` // Omitted during polar day and night
+	Sunset           *EventTime `json:"sunset,omitempty"`
+}
+
+// UnitLabels names the unit each kind of value in WeatherDetails is expressed in.
+type UnitLabels struct {
+	Temperature   string `json:"temperature"`
+	Speed         string `json:"speed"`
+	Pressure      string `json:"pressure"`
+	Distance      string `json:"distance"`
+	Precipitation string `json:"precipitation"`
+}
+
+type TemperatureDetails struct {
+	Current   float64 `json:"current"`
+	FeelsLike float64 `json:"feelsLike"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+}
+
+type PressureDetails struct {
+	Current     float64  `json:"current"`
+	SeaLevel    *float64 `json:"seaLevel,omitempty"`
+	GroundLevel *float64 `json:"groundLevel,omitempty"`
+}
+
+type WindDetails struct {
+	Speed     float64  `json:"speed"`
+	Direction float64  `json:"direction"` // Degrees the wind blows from
+	Gust      *float64 `json:"gust,omitempty"`
+}
+
+type PrecipitationDetails struct {
+	Rain1h *float64 `json:"rain1h,omitempty"`
+	Rain3h *float64 `json:"rain3h,omitempty"`
+	Snow1h *float64 `json:"snow1h,omitempty"`
+	Snow3h *float64 `json:"snow3h,omitempty"`
+}
+
+// DerivedIndices are computed from temperature, humidity and wind, in the requested temperature unit.
+// Indices outside their defined range, or needing a humidity that was not reported, are omitted.
+type DerivedIndices struct {
+	DewPoint            *float64 `json:"dewPoint,omitempty"`
+	HeatIndex           *float64 `json:"heatIndex,omitempty"`
+	WindChill           *float64 `json:"windChill,omitempty"`
+	ApparentTemperature *float64 `json:"apparentTemperature,omitempty"`
+	Humidex             *float64 `json:"humidex,omitempty"`
+	WetBulb             *float64 `json:"wetBulb,omitempty"`
+}
+
+// WeatherDetails carries the full current conditions in the requested units.
+type WeatherDetails struct {
+	Units          UnitLabels           `json:"units"`
+	Temperature    TemperatureDetails   `json:"temperature"`
+	Pressure       PressureDetails      `json:"pressure"`
+	Humidity       float64              `json:"humidity"`             // %
+	Visibility     *float64             `json:"visibility,omitempty"` // Omitted when not reported
+	Wind           WindDetails          `json:"wind"`
+	Cloudiness     float64              `json:"cloudiness"` // %
+	Precipitation  PrecipitationDetails `json:"precipitation"`
+	Derived        DerivedIndices       `json:"derived"`
+	ObservedAt     *time.Time           `json:"observedAt,omitempty"`
+	Sunrise        *time.Time           `json:"sunrise,omitempty"`
+	Sunset         *time.Time           `json:"sunset,omitempty"`
+	TimezoneOffset int                  `json:"timezoneOffset"` // Seconds east of UTC
+	Station        string               `json:"station,omitempty"`
+	Country        string               `json:"country,omitempty"`
+	Coordinates    Coordinates          `json:"coordinates"`
 }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/uv"
+)
+
+// UV index sources
+const (
+	UVSourceOneCall  = "onecall"  // OpenWeatherMap's One Call API
+	UVSourceClearSky = "clearSky" // Estimated from the solar elevation
+)
+
+// UVResponse describes the UV index at a location now and at its peak of the day.
+type UVResponse struct {
+	Coordinates Coordinates    `json:"coordinates"`
+	Source      string         `json:"source"`
+	Time        time.Time      `json:"time"`
+	UVIndex     float64        `json:"uvIndex"`
+	Category    string         `json:"category"` // WHO category
+	Advice      string         `json:"advice"`
+	Peak        UVPeak         `json:"peak"`
+	Exposure    []SkinExposure `json:"exposure"`
+}
+
+// UVPeak is the highest UV index of the day at the location.
+type UVPeak struct {
+	Time     time.Time `json:"time"`
+	UVIndex  float64   `json:"uvIndex"`
+	Category string    `json:"category"`
+}
+
+// SkinExposure is the approximate time unprotected skin of a Fitzpatrick type can spend in the sun before
+// burning. Times are omitted when there is no meaningful UV.
+type SkinExposure struct {
+	uv.SkinType
+	SafeMinutes       *int `json:"safeMinutes,omitempty"`       // At the current UV index
+	SafeMinutesAtPeak *int `json:"safeMinutesAtPeak,omitempty"` // At the peak UV index
+}
@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+// ttlCache is a size-bounded in-memory cache whose entries expire after a fixed time to live.
+type ttlCache[V any] struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time // Clock, replaceable in tests
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration, maxEntries int) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, maxEntries: maxEntries, now: time.Now, entries: make(map[string]cacheEntry[V])}
+}
+
+// get returns the cached value for key if it has not expired.
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set stores value under key, evicting expired entries and then the soonest to expire when the cache is full.
+func (c *ttlCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for len(c.entries) >= c.maxEntries {
+			var oldestKey string
+			var oldest time.Time
+			for k, entry := range c.entries {
+				if oldestKey == "" || entry.expires.Before(oldest) {
+					oldestKey, oldest = k, entry.expires
+				}
+			}
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	c.entries[key] = cacheEntry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+// cacheKey builds a cache key from its parts. The API key is part of every key, hashed so it is not kept
+// in memory, because serving data fetched with one customer's key to a request with an invalid key would
+// bypass OpenWeatherMap's authentication.
+func cacheKey(ctx context.Context, parts ...string) string {
+	apiKey, _ := ctx.Value(middleware.APIKeyContextKey("apiKey")).(string)
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8]) + "|" + strings.Join(parts, "|")
+}
+
+type cachedWeatherAPI struct {
+	WeatherAPI
+	cache *ttlCache[model.WeatherData]
+}
+
+// NewCachedWeatherAPI wraps api so that successful responses are reused for ttl. Because the handlers always
+// fetch in the canonical unit system, one entry serves every output unit a client can ask for.
+func NewCachedWeatherAPI(api WeatherAPI, ttl time.Duration, maxEntries int) WeatherAPI {
+	return &cachedWeatherAPI{WeatherAPI: api, cache: newTTLCache[model.WeatherData](ttl, maxEntries)}
+}
+
+// FetchWeatherData returns cached weather data when available and otherwise fetches and caches it.
+func (api *cachedWeatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+	key := cacheKey(ctx, "weather", lat, lon, openWeatherMapAPIURL, unitsOfMeasurement, string(i18n.FromContext(ctx)))
+	if data, ok := api.cache.get(key); ok {
+		return data, nil
+	}
+
+	data, err := api.WeatherAPI.FetchWeatherData(ctx, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement)
+	if err != nil {
+		return data, err
+	}
+
+	api.cache.set(key, data)
+	return data, nil
+}
+
+type cachedForecastAPI struct {
+	ForecastAPI
+	cache *ttlCache[model.ForecastData]
+}
+
+// NewCachedForecastAPI wraps api so that successful forecasts are reused for ttl.
+func NewCachedForecastAPI(api ForecastAPI, ttl time.Duration, maxEntries int) ForecastAPI {
+	return &cachedForecastAPI{ForecastAPI: api, cache: newTTLCache[model.ForecastData](ttl, maxEntries)}
+}
+
+// FetchForecast returns a cached forecast when available and otherwise fetches and caches it.
+func (api *cachedForecastAPI) FetchForecast(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+	key := cacheKey(ctx, "forecast", lat, lon, openWeatherMapForecastURL, unitsOfMeasurement, string(i18n.FromContext(ctx)))
+	if data, ok := api.cache.get(key); ok {
+		return data, nil
+	}
+
+	data, err := api.ForecastAPI.FetchForecast(ctx, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement)
+	if err != nil {
+		return data, err
+	}
+
+	api.cache.set(key, data)
+	return data, nil
+}
+
+type cachedOneCallAPI struct {
+	OneCallAPI
+	cache *ttlCache[model.OneCallData]
+}
+
+// NewCachedOneCallAPI wraps api so that successful One Call responses are reused for ttl. Minutely data is
+// always fetched fresh, as it changes from minute to minute.
+func NewCachedOneCallAPI(api OneCallAPI, ttl time.Duration, maxEntries int) OneCallAPI {
+	return &cachedOneCallAPI{OneCallAPI: api, cache: newTTLCache[model.OneCallData](ttl, maxEntries)}
+}
+
+// FetchOneCall returns a cached One Call response when available and otherwise fetches and caches it.
+func (api *cachedOneCallAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error) {
+	excluded := make([]string, len(exclude))
+	for i, block := range exclude {
+		excluded[i] = string(block)
+	}
+	slices.Sort(excluded)
+
+	key := cacheKey(ctx, "onecall", lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement, string(i18n.FromContext(ctx)), strings.Join(excluded, ","))
+	if data, ok := api.cache.get(key); ok {
+		return data, nil
+	}
+
+	data, err := api.OneCallAPI.FetchOneCall(ctx, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement, exclude)
+	if err != nil {
+		return data, err
+	}
+
+	api.cache.set(key, data)
+	return data, nil
+}
+
+type cachedAirPollutionAPI struct {
+	AirPollutionAPI
+	cache *ttlCache[model.AirPollutionData]
+}
+
+// NewCachedAirPollutionAPI wraps api so that successful current and forecast air pollution responses are
+// reused for ttl.
+func NewCachedAirPollutionAPI(api AirPollutionAPI, ttl time.Duration, maxEntries int) AirPollutionAPI {
+	return &cachedAirPollutionAPI{AirPollutionAPI: api, cache: newTTLCache[model.AirPollutionData](ttl, maxEntries)}
+}
+
+// FetchAirPollution returns cached air pollution when available and otherwise fetches and caches it.
+func (api *cachedAirPollutionAPI) FetchAirPollution(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	return api.cached(ctx, "air", lat, lon, openWeatherMapAirPollutionURL, api.AirPollutionAPI.FetchAirPollution)
+}
+
+// FetchAirPollutionForecast returns a cached air pollution forecast when available and otherwise fetches and
+// caches it.
+func (api *cachedAirPollutionAPI) FetchAirPollutionForecast(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	return api.cached(ctx, "airforecast", lat, lon, openWeatherMapAirPollutionURL, api.AirPollutionAPI.FetchAirPollutionForecast)
+}
+
+// cached looks up or fetches one kind of air pollution data. Neither kind depends on the language.
+func (api *cachedAirPollutionAPI) cached(ctx context.Context, kind, lat, lon, airPollutionURL string,
+	fetch func(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error)) (model.AirPollutionData, error) {
+	key := cacheKey(ctx, kind, lat, lon, airPollutionURL)
+	if data, ok := api.cache.get(key); ok {
+		return data, nil
+	}
+
+	data, err := fetch(ctx, lat, lon, airPollutionURL)
+	if err != nil {
+		return data, err
+	}
+
+	api.cache.set(key, data)
+	return data, nil
+}
@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+// countingWeatherAPI counts upstream fetches for cache tests.
+type countingWeatherAPI struct {
+	calls int
+	err   error
+}
+
+func (api *countingWeatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+	api.calls++
+	return model.WeatherData{Main: model.MainInfo{Temp: 280}}, api.err
+}
+
+func TestCachedWeatherAPI(t *testing.T) {
+	upstream := &countingWeatherAPI{}
+	api := NewCachedWeatherAPI(upstream, time.Minute, 10).(*cachedWeatherAPI)
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	api.cache.now = func() time.Time { return clock }
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key-one")
+	otherCtx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key-two")
+
+	api.FetchWeatherData(ctx, "35", "139", "http://example.com", "standard")
+	api.FetchWeatherData(ctx, "35", "139", "http://example.com", "standard")
+	if upstream.calls != 1 {
+		t.Errorf("expected the second request to be served from cache, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchWeatherData(otherCtx, "35", "139", "http://example.com", "standard")
+	if upstream.calls != 2 {
+		t.Errorf("expected a different API key not to share the cache entry, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchWeatherData(i18n.NewContext(ctx, i18n.German), "35", "139", "http://example.com", "standard")
+	if upstream.calls != 3 {
+		t.Errorf("expected a different language not to share the cache entry, upstream called %d times", upstream.calls)
+	}
+
+	clock = clock.Add(time.Minute)
+	api.FetchWeatherData(ctx, "35", "139", "http://example.com", "standard")
+	if upstream.calls != 4 {
+		t.Errorf("expected an expired entry to be refetched, upstream called %d times", upstream.calls)
+	}
+}
+
+// countingForecastAPI counts upstream forecast fetches for cache tests.
+type countingForecastAPI struct {
+	calls int
+}
+
+func (api *countingForecastAPI) FetchForecast(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+	api.calls++
+	return model.ForecastData{City: model.ForecastCity{Name: "Shibuya"}}, nil
+}
+
+func TestCachedForecastAPI(t *testing.T) {
+	upstream := &countingForecastAPI{}
+	api := NewCachedForecastAPI(upstream, time.Minute, 10)
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key")
+
+	api.FetchForecast(ctx, "35", "139", "http://example.com/forecast", "standard")
+	data, _ := api.FetchForecast(ctx, "35", "139", "http://example.com/forecast", "standard")
+	if upstream.calls != 1 || data.City.Name != "Shibuya" {
+		t.Errorf("expected the second forecast to be served from cache, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchForecast(ctx, "36", "139", "http://example.com/forecast", "standard")
+	if upstream.calls != 2 {
+		t.Errorf("expected another location not to share the cache entry, upstream called %d times", upstream.calls)
+	}
+}
+
+// countingOneCallAPI counts upstream One Call fetches for cache tests.
+type countingOneCallAPI struct {
+	calls, minutelyCalls int
+}
+
+func (api *countingOneCallAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error) {
+	api.calls++
+	return model.OneCallData{Lat: 35}, nil
+}
+
+func (api *countingOneCallAPI) FetchMinutely(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+	api.minutelyCalls++
+	return nil, nil
+}
+
+func TestCachedOneCallAPI(t *testing.T) {
+	upstream := &countingOneCallAPI{}
+	api := NewCachedOneCallAPI(upstream, time.Minute, 10)
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key")
+
+	api.FetchOneCall(ctx, "35", "139", "http://example.com/onecall", "standard", []OneCallBlock{BlockHourly, BlockDaily})
+	api.FetchOneCall(ctx, "35", "139", "http://example.com/onecall", "standard", []OneCallBlock{BlockDaily, BlockHourly})
+	if upstream.calls != 1 {
+		t.Errorf("expected the same exclusions in another order to share the cache entry, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchOneCall(ctx, "35", "139", "http://example.com/onecall", "standard", []OneCallBlock{BlockDaily})
+	if upstream.calls != 2 {
+		t.Errorf("expected different exclusions not to share the cache entry, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchMinutely(ctx, "35", "139", "http://example.com/onecall")
+	api.FetchMinutely(ctx, "35", "139", "http://example.com/onecall")
+	if upstream.minutelyCalls != 2 {
+		t.Errorf("expected minutely data not to be cached, upstream called %d times", upstream.minutelyCalls)
+	}
+}
+
+type countingAirPollutionAPI struct {
+	calls, forecastCalls int
+}
+
+func (api *countingAirPollutionAPI) FetchAirPollution(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	api.calls++
+	return model.AirPollutionData{}, nil
+}
+
+func (api *countingAirPollutionAPI) FetchAirPollutionForecast(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	api.forecastCalls++
+	return model.AirPollutionData{}, nil
+}
+
+func TestCachedAirPollutionAPI(t *testing.T) {
+	upstream := &countingAirPollutionAPI{}
+	api := NewCachedAirPollutionAPI(upstream, time.Minute, 10)
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key")
+	germanCtx := i18n.NewContext(ctx, i18n.German)
+
+	api.FetchAirPollution(ctx, "35", "139", "http://example.com/air")
+	api.FetchAirPollution(germanCtx, "35", "139", "http://example.com/air")
+	if upstream.calls != 1 {
+		t.Errorf("expected air pollution to be shared between languages, upstream called %d times", upstream.calls)
+	}
+
+	api.FetchAirPollutionForecast(ctx, "35", "139", "http://example.com/air")
+	api.FetchAirPollutionForecast(ctx, "35", "139", "http://example.com/air")
+	if upstream.calls != 1 || upstream.forecastCalls != 1 {
+		t.Errorf("expected the forecast to be cached apart from current data, upstream called %d and %d times", upstream.calls, upstream.forecastCalls)
+	}
+}
+
+func TestCachedWeatherAPI_ErrorsAreNotCached(t *testing.T) {
+	upstream := &countingWeatherAPI{err: ErrServiceUnavailable}
+	api := NewCachedWeatherAPI(upstream, time.Minute, 10)
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "key")
+
+	for i := 0; i < 2; i++ {
+		if _, err := api.FetchWeatherData(ctx, "35", "139", "http://example.com", "standard"); !errors.Is(err, ErrServiceUnavailable) {
+			t.Fatalf("Expected ErrServiceUnavailable, got %v", err)
+		}
+	}
+	if upstream.calls != 2 {
+		t.Errorf("expected errors not to be cached, upstream called %d times", upstream.calls)
+	}
+}
+
+func TestTTLCache_Eviction(t *testing.T) {
+	cache := newTTLCache[int](time.Minute, 2)
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return clock }
+
+	cache.set("a", 1)
+	clock = clock.Add(time.Second)
+	cache.set("b", 2)
+	clock = clock.Add(time.Second)
+	cache.set("c", 3) // Full: evicts "a", which expires first
+
+	if _, ok := cache.get("a"); ok {
+		t.Errorf("expected the oldest entry to be evicted")
+	}
+	if value, ok := cache.get("c"); !ok || value != 3 {
+		t.Errorf("expected the new entry to be cached")
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("cache holds %d entries, want at most 2", len(cache.entries))
+	}
+}
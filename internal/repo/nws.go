@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+// ErrNWSUnavailable reports that the National Weather Service alerts API could not be used. Callers fall
+// back to other alert sources, so its causes are not told apart.
+var ErrNWSUnavailable = errors.New("National Weather Service alerts API is unavailable")
+
+// NWSAlertsAPI fetches active alerts from the US National Weather Service (api.weather.gov), which covers
+// only US states and territories and needs no API key.
+type NWSAlertsAPI interface {
+	FetchActiveAlerts(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error)
+}
+
+type nwsAlertsAPI struct {
+	url       string
+	userAgent string
+}
+
+// NewNWSAlertsAPI creates an NWSAlertsAPI for the active alerts endpoint at alertsURL. NWS requires every
+// request to identify the application in its User-Agent.
+func NewNWSAlertsAPI(alertsURL, userAgent string) NWSAlertsAPI {
+	return &nwsAlertsAPI{url: alertsURL, userAgent: userAgent}
+}
+
+// FetchActiveAlerts requests the actual (not test or exercise) alerts in effect at a point.
+func (api *nwsAlertsAPI) FetchActiveAlerts(ctx context.Context, lat, lon float64) ([]model.NWSAlert, error) {
+	u, err := url.Parse(api.url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNWSUnavailable, err)
+	}
+	query := u.Query()
+	// NWS redirects points with more than 4 decimals to the rounded point
+	query.Set("point", strconv.FormatFloat(lat, 'f', 4, 64)+","+strconv.FormatFloat(lon, 'f', 4, 64))
+	query.Set("status", "actual")
+	u.RawQuery = query.Encode()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(timeoutCtx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNWSUnavailable, err)
+	}
+	request.Header.Set("User-Agent", api.userAgent)
+	request.Header.Set("Accept", "application/geo+json")
+
+	start := time.Now()
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		logger.DebugContext(ctx, "upstream request failed", "url", u.String(), "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrNWSUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	logger.DebugContext(ctx, "upstream request", "url", u.String(), "status", response.StatusCode, "duration", time.Since(start))
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrNWSUnavailable, response.StatusCode)
+	}
+
+	var collection model.NWSAlertCollection
+	if err := json.NewDecoder(response.Body).Decode(&collection); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNWSUnavailable, err)
+	}
+
+	alerts := make([]model.NWSAlert, len(collection.Features))
+	for i, feature := range collection.Features {
+		alerts[i] = feature.Properties
+	}
+	return alerts, nil
+}
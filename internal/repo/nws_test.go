@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestFetchActiveAlerts_Success(t *testing.T) {
+	var query url.Values
+	var userAgent string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"type": "FeatureCollection", "features": [{"properties": {
+			"id": "urn:oid:2.49.0.1.840.0.1", "areaDesc": "Travis, TX", "onset": "2024-01-01T12:00:00-06:00",
+			"expires": "2024-01-01T18:00:00-06:00", "severity": "Severe", "certainty": "Likely", "urgency": "Immediate",
+			"event": "Flash Flood Warning", "senderName": "NWS Austin/San Antonio TX"}}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewNWSAlertsAPI(mockServer.URL, "test-agent (ops@example.com)")
+	alerts, err := api.FetchActiveAlerts(context.Background(), 30.267153, -97.743061)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("point") != "30.2672,-97.7431" || query.Get("status") != "actual" {
+		t.Errorf("Unexpected upstream query %v", query)
+	}
+	if userAgent != "test-agent (ops@example.com)" {
+		t.Errorf("Expected the configured User-Agent, got %q", userAgent)
+	}
+	if len(alerts) != 1 || alerts[0].Event != "Flash Flood Warning" || alerts[0].Onset.UTC().Hour() != 18 {
+		t.Errorf("Unexpected alerts %+v", alerts)
+	}
+}
+
+func TestFetchActiveAlerts_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		statusCode int
+	}{
+		{"Server Error", `{"title": "Unexpected Problem"}`, http.StatusInternalServerError},
+		{"Invalid JSON", `{"features": [`, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockServer := setupMockServer(tc.response, tc.statusCode)
+			defer mockServer.Close()
+
+			_, err := NewNWSAlertsAPI(mockServer.URL, "test-agent").FetchActiveAlerts(context.Background(), 30, -97)
+			if !errors.Is(err, ErrNWSUnavailable) {
+				t.Errorf("Expected ErrNWSUnavailable, got %v", err)
+			}
+		})
+	}
+}
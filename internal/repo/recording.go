@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+	"github.com/golang2go/demo-app/weather-service-api/internal/util/units"
+)
+
+// The recording decorators keep every observation fetched from upstream in a history store, so the history
+// endpoint can serve it later. Wrap them in the cache decorators so only actual upstream fetches are recorded.
+// Recording failures are logged and never fail the fetch.
+
+type recordingWeatherAPI struct {
+	WeatherAPI
+	store history.Store
+}
+
+// NewRecordingWeatherAPI wraps api so that current weather fetched in the canonical unit system is recorded
+// in store.
+func NewRecordingWeatherAPI(api WeatherAPI, store history.Store) WeatherAPI {
+	return &recordingWeatherAPI{WeatherAPI: api, store: store}
+}
+
+// FetchWeatherData fetches weather data and records it.
+func (api *recordingWeatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+	data, err := api.WeatherAPI.FetchWeatherData(ctx, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement)
+	if err == nil && unitsOfMeasurement == units.CanonicalSystem {
+		record(ctx, api.store, history.FromWeatherData(data.Coord.Lat, data.Coord.Lon, data))
+	}
+	return data, err
+}
+
+type recordingOneCallAPI struct {
+	OneCallAPI
+	store history.Store
+}
+
+// NewRecordingOneCallAPI wraps api so that current conditions fetched from One Call in the canonical unit
+// system are recorded in store.
+func NewRecordingOneCallAPI(api OneCallAPI, store history.Store) OneCallAPI {
+	return &recordingOneCallAPI{OneCallAPI: api, store: store}
+}
+
+// FetchOneCall fetches a One Call response and records its current conditions, if requested.
+func (api *recordingOneCallAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error) {
+	data, err := api.OneCallAPI.FetchOneCall(ctx, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement, exclude)
+	if err == nil && unitsOfMeasurement == units.CanonicalSystem {
+		if current, ok := data.CurrentWeatherData(); ok {
+			record(ctx, api.store, history.FromWeatherData(data.Lat, data.Lon, current))
+		}
+	}
+	return data, err
+}
+
+type recordingHistoryAPI struct {
+	HistoryAPI
+	store history.Store
+}
+
+// NewRecordingHistoryAPI wraps api so that fetched history is recorded in store and served from it next time.
+func NewRecordingHistoryAPI(api HistoryAPI, store history.Store) HistoryAPI {
+	return &recordingHistoryAPI{HistoryAPI: api, store: store}
+}
+
+// FetchHistory fetches past observations and records them at the requested point, which the history API
+// does not echo.
+func (api *recordingHistoryAPI) FetchHistory(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+	data, err := api.HistoryAPI.FetchHistory(ctx, lat, lon, openWeatherMapHistoryURL, start, end)
+	if err != nil {
+		return data, err
+	}
+
+	latitude, latErr := strconv.ParseFloat(lat, 64)
+	longitude, lonErr := strconv.ParseFloat(lon, 64)
+	if latErr != nil || lonErr != nil {
+		return data, nil
+	}
+	observations := make([]history.Observation, len(data.List))
+	for i, item := range data.List {
+		observations[i] = history.FromWeatherData(latitude, longitude, item)
+	}
+	record(ctx, api.store, observations...)
+	return data, nil
+}
+
+// record appends observations to the store, logging failures.
+func record(ctx context.Context, store history.Store, observations ...history.Observation) {
+	if len(observations) == 0 {
+		return
+	}
+	if err := store.Append(ctx, observations...); err != nil {
+		logger.WarnContext(ctx, "failed to record observations", "count", len(observations), "error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/model"
+)
+
+// stubWeatherAPI returns fixed current weather.
+type stubWeatherAPI struct{}
+
+func (stubWeatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
+	return model.WeatherData{Coord: model.Coordinates{Lat: 35, Lon: 139}, Dt: 1704110400, Main: model.MainInfo{Temp: 280.15}}, nil
+}
+
+// stubOneCallAPI returns fixed current conditions.
+type stubOneCallAPI struct{}
+
+func (stubOneCallAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error) {
+	return model.OneCallData{Lat: 35, Lon: 139, TimezoneOffset: 32400, Current: &model.OneCallCurrent{Dt: 1704110400, Temp: 280.15}}, nil
+}
+
+func (stubOneCallAPI) FetchMinutely(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+	return nil, nil
+}
+
+// stubHistoryAPI returns one observation per hour of the requested period.
+type stubHistoryAPI struct{}
+
+func (stubHistoryAPI) FetchHistory(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+	var data model.HistoryData
+	for at := start; at.Before(end); at = at.Add(time.Hour) {
+		data.List = append(data.List, model.WeatherData{Dt: at.Unix(), Main: model.MainInfo{Temp: 280}})
+	}
+	data.Cnt = len(data.List)
+	return data, nil
+}
+
+func TestRecordingWeatherAPI(t *testing.T) {
+	store := history.NewMemoryStore(0)
+	api := NewRecordingWeatherAPI(stubWeatherAPI{}, store)
+	ctx := context.Background()
+	at := time.Unix(1704110400, 0)
+
+	api.FetchWeatherData(ctx, "35", "139", "http://example.com", "metric")
+	if recorded, _ := store.Range(ctx, history.CellOf(35, 139), at, at.Add(time.Second)); len(recorded) != 0 {
+		t.Errorf("expected weather in non-canonical units not to be recorded, got %+v", recorded)
+	}
+
+	api.FetchWeatherData(ctx, "35", "139", "http://example.com", "standard")
+	recorded, _ := store.Range(ctx, history.CellOf(35, 139), at, at.Add(time.Second))
+	if len(recorded) != 1 || recorded[0].Temperature != 280.15 {
+		t.Errorf("expected the observation to be recorded, got %+v", recorded)
+	}
+}
+
+func TestRecordingOneCallAPI(t *testing.T) {
+	store := history.NewMemoryStore(0)
+	api := NewRecordingOneCallAPI(stubOneCallAPI{}, store)
+	ctx := context.Background()
+	at := time.Unix(1704110400, 0)
+
+	api.FetchOneCall(ctx, "35", "139", "http://example.com/onecall", "standard", nil)
+	recorded, _ := store.Range(ctx, history.CellOf(35, 139), at, at.Add(time.Second))
+	if len(recorded) != 1 || recorded[0].Temperature != 280.15 || recorded[0].TimezoneOffset != 32400 {
+		t.Errorf("expected the current conditions to be recorded, got %+v", recorded)
+	}
+}
+
+func TestRecordingHistoryAPI(t *testing.T) {
+	store := history.NewMemoryStore(0)
+	api := NewRecordingHistoryAPI(stubHistoryAPI{}, store)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data, err := api.FetchHistory(ctx, "35.001", "139.002", "http://example.com/history", start, start.Add(3*time.Hour))
+	if err != nil || data.Cnt != 3 {
+		t.Fatalf("expected three observations, got %+v and error %v", data, err)
+	}
+	recorded, _ := store.Range(ctx, history.CellOf(35, 139), start, start.Add(3*time.Hour))
+	if len(recorded) != 3 || recorded[0].Lat != 35.001 || !recorded[2].Time.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected the history to be recorded at the requested point, got %+v", recorded)
+	}
+}
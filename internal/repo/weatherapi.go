@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"time"
 
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
 	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
 	"github.com/golang2go/demo-app/weather-service-api/internal/model"
 	"github.com/golang2go/demo-app/weather-service-api/internal/util"
@@ -22,31 +26,216 @@ var (
 	ErrTimeout              = errors.New("request to OpenWeather API timed out")
 )
 
+// logger reports upstream timings, status codes and redacted URLs at debug level.
+var logger = logging.Logger("repo")
+
 type WeatherAPI interface {
 	FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error)
 }
 
-type weatherAPI struct{}
+// OneCallAPI fetches data from OpenWeatherMap's One Call API, which needs a One Call subscription.
+type OneCallAPI interface {
+	// FetchOneCall fetches every block of the One Call response except the excluded ones.
+	FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error)
+	// FetchMinutely fetches only the minute-by-minute precipitation forecast for the next hour.
+	FetchMinutely(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error)
+}
+
+// OneCallBlock names a block of the One Call response that can be excluded from a request.
+type OneCallBlock string
+
+const (
+	BlockCurrent  OneCallBlock = "current"
+	BlockMinutely OneCallBlock = "minutely"
+	BlockHourly   OneCallBlock = "hourly"
+	BlockDaily    OneCallBlock = "daily"
+	BlockAlerts   OneCallBlock = "alerts"
+)
+
+// OneCallBlocks lists every block of the One Call response.
+var OneCallBlocks = []OneCallBlock{BlockCurrent, BlockMinutely, BlockHourly, BlockDaily, BlockAlerts}
+
+// ExcludeAllBut returns the exclude list that requests only the given blocks.
+func ExcludeAllBut(keep ...OneCallBlock) []OneCallBlock {
+	var exclude []OneCallBlock
+	for _, block := range OneCallBlocks {
+		if !slices.Contains(keep, block) {
+			exclude = append(exclude, block)
+		}
+	}
+	return exclude
+}
+
+// ForecastAPI fetches OpenWeatherMap's 5 day forecast in 3-hour steps.
+type ForecastAPI interface {
+	FetchForecast(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error)
+}
+
+// AirPollutionAPI fetches OpenWeatherMap's air pollution data, with concentrations in μg/m³.
+type AirPollutionAPI interface {
+	// FetchAirPollution fetches the current air pollution.
+	FetchAirPollution(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error)
+	// FetchAirPollutionForecast fetches the hourly air pollution forecast for the next 4 days.
+	FetchAirPollutionForecast(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error)
+}
+
+// HistoryAPI fetches past hourly observations from OpenWeatherMap's history API, which needs a history
+// subscription. Data is always in standard units.
+type HistoryAPI interface {
+	FetchHistory(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error)
+}
 
-func NewWeatherAPI() WeatherAPI {
-	return &weatherAPI{}
+type weatherAPI struct {
+	redactor *util.Redactor // Strips the API key from errors that carry the upstream URL
+}
+
+// Option configures optional behaviour of the WeatherAPI returned by NewWeatherAPI.
+type Option func(*weatherAPI)
+
+// WithRedactor sets the Redactor used to scrub upstream URLs from errors, e.g. one with extra sensitive parameters.
+func WithRedactor(redactor *util.Redactor) Option {
+	return func(api *weatherAPI) {
+		api.redactor = redactor
+	}
+}
+
+func NewWeatherAPI(opts ...Option) WeatherAPI {
+	return newWeatherAPI(opts...)
+}
+
+// NewOneCallAPI creates a OneCallAPI with the same options as NewWeatherAPI.
+func NewOneCallAPI(opts ...Option) OneCallAPI {
+	return newWeatherAPI(opts...)
+}
+
+// NewForecastAPI creates a ForecastAPI with the same options as NewWeatherAPI.
+func NewForecastAPI(opts ...Option) ForecastAPI {
+	return newWeatherAPI(opts...)
+}
+
+// NewAirPollutionAPI creates an AirPollutionAPI with the same options as NewWeatherAPI.
+func NewAirPollutionAPI(opts ...Option) AirPollutionAPI {
+	return newWeatherAPI(opts...)
+}
+
+// NewHistoryAPI creates a HistoryAPI with the same options as NewWeatherAPI.
+func NewHistoryAPI(opts ...Option) HistoryAPI {
+	return newWeatherAPI(opts...)
+}
+
+func newWeatherAPI(opts ...Option) *weatherAPI {
+	api := &weatherAPI{redactor: util.NewRedactor(nil, nil)}
+	for _, opt := range opts {
+		opt(api)
+	}
+	return api
 }
 
 // Define a constant for the timeout duration
 const requestTimeout = 5 * time.Second
 
+// historyChunk is the longest period OpenWeatherMap's history API returns in one request.
+const historyChunk = 7 * 24 * time.Hour
+
 // FetchWeatherData makes an HTTP request to the OpenWeather API to get weather data for a specific latitude and longitude.
 func (api *weatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeatherMapAPIURL, unitsOfMeasurement string) (model.WeatherData, error) {
 	var data model.WeatherData
+	err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+		return util.BuildOpenWeatherMapURL(openWeatherMapAPIURL, apiKey, lat, lon, unitsOfMeasurement, lang)
+	}, &data)
+	return data, err
+}
+
+// FetchForecast makes an HTTP request to the OpenWeather forecast API for a specific latitude and longitude.
+func (api *weatherAPI) FetchForecast(ctx context.Context, lat, lon, openWeatherMapForecastURL, unitsOfMeasurement string) (model.ForecastData, error) {
+	var data model.ForecastData
+	err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+		return util.BuildOpenWeatherMapURL(openWeatherMapForecastURL, apiKey, lat, lon, unitsOfMeasurement, lang)
+	}, &data)
+	return data, err
+}
+
+// FetchOneCall makes an HTTP request to the OpenWeather One Call API, leaving out the excluded blocks.
+func (api *weatherAPI) FetchOneCall(ctx context.Context, lat, lon, openWeatherMapOneCallURL, unitsOfMeasurement string, exclude []OneCallBlock) (model.OneCallData, error) {
+	var data model.OneCallData
+
+	excluded := make([]string, 0, len(exclude))
+	for _, block := range exclude {
+		if !slices.Contains(OneCallBlocks, block) {
+			return data, fmt.Errorf("%w: unknown One Call block %q", ErrBadRequest, block)
+		}
+		excluded = append(excluded, string(block))
+	}
+
+	err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+		return util.BuildOneCallURL(openWeatherMapOneCallURL, apiKey, lat, lon, unitsOfMeasurement, lang, excluded)
+	}, &data)
+	return data, err
+}
+
+// FetchAirPollution makes an HTTP request to the OpenWeather air pollution API for a specific latitude and longitude.
+func (api *weatherAPI) FetchAirPollution(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	var data model.AirPollutionData
+	err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+		return util.BuildOpenWeatherMapURL(openWeatherMapAirPollutionURL, apiKey, lat, lon, "", "")
+	}, &data)
+	return data, err
+}
+
+// FetchAirPollutionForecast makes an HTTP request to the forecast below the OpenWeather air pollution API.
+func (api *weatherAPI) FetchAirPollutionForecast(ctx context.Context, lat, lon, openWeatherMapAirPollutionURL string) (model.AirPollutionData, error) {
+	var data model.AirPollutionData
+	err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+		forecastURL, err := url.JoinPath(openWeatherMapAirPollutionURL, "forecast")
+		if err != nil {
+			return "", err
+		}
+		return util.BuildOpenWeatherMapURL(forecastURL, apiKey, lat, lon, "", "")
+	}, &data)
+	return data, err
+}
+
+// FetchHistory makes HTTP requests to the OpenWeather history API for the hourly observations from start to
+// end, one request per week of the period.
+func (api *weatherAPI) FetchHistory(ctx context.Context, lat, lon, openWeatherMapHistoryURL string, start, end time.Time) (model.HistoryData, error) {
+	var history model.HistoryData
+	for chunkStart := start; chunkStart.Before(end); chunkStart = chunkStart.Add(historyChunk) {
+		chunkEnd := chunkStart.Add(historyChunk)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		var data model.HistoryData
+		err := api.fetch(ctx, func(apiKey, lang string) (string, error) {
+			return util.BuildHistoryURL(openWeatherMapHistoryURL, apiKey, lat, lon, chunkStart.Unix(), chunkEnd.Unix())
+		}, &data)
+		if err != nil {
+			return history, err
+		}
+		history.List = append(history.List, data.List...)
+	}
+	history.Cnt = len(history.List)
+	return history, nil
+}
 
+// FetchMinutely requests the minute-by-minute precipitation forecast for the next hour from the One Call API.
+// Precipitation rates are always in mm/h, whatever the unit system.
+func (api *weatherAPI) FetchMinutely(ctx context.Context, lat, lon, openWeatherMapOneCallURL string) ([]model.MinutelyPrecipitation, error) {
+	data, err := api.FetchOneCall(ctx, lat, lon, openWeatherMapOneCallURL, "standard", ExcludeAllBut(BlockMinutely))
+	return data.Minutely, err
+}
+
+// fetch requests the OpenWeatherMap URL built for the request's API key and language and decodes its JSON
+// response into data.
+func (api *weatherAPI) fetch(ctx context.Context, buildURL func(apiKey, lang string) (string, error), data any) error {
 	apiKey, ok := ctx.Value(middleware.APIKeyContextKey("apiKey")).(string)
 	if !ok || apiKey == "" {
-		return data, fmt.Errorf("%w: API key not found in context", ErrBadRequest)
+		return fmt.Errorf("%w: API key not found in context", ErrBadRequest)
 	}
 
-	finalURL, err := util.BuildOpenWeatherMapURL(openWeatherMapAPIURL, apiKey, lat, lon, unitsOfMeasurement)
+	finalURL, err := buildURL(apiKey, upstreamLanguage(ctx))
 	if err != nil {
-		return data, err
+		return api.redactor.Error(err)
 	}
 
 	// Create a new context with a timeout
@@ -55,35 +244,52 @@ func (api *weatherAPI) FetchWeatherData(ctx context.Context, lat, lon, openWeath
 
 	request, err := http.NewRequestWithContext(timeoutCtx, "GET", finalURL, nil)
 	if err != nil {
-		return data, fmt.Errorf("%w: %v", ErrBadRequest, err)
+		return fmt.Errorf("%w: %v", ErrBadRequest, api.redactor.Error(err))
 	}
 
+	start := time.Now()
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
+		logger.DebugContext(ctx, "upstream request failed",
+			"url", api.redactor.URL(finalURL), "duration", time.Since(start), "error", api.redactor.Error(err))
+
 		// Check if the error is a timeout
 		if errors.Is(err, context.DeadlineExceeded) {
-			return data, ErrTimeout
+			return ErrTimeout
 		}
-		return data, fmt.Errorf("%w: %v", ErrServiceUnavailable, err)
+		// The transport error embeds the request URL, including the appid parameter
+		return fmt.Errorf("%w: %v", ErrServiceUnavailable, api.redactor.Error(err))
 	}
 	defer response.Body.Close()
 
+	logger.DebugContext(ctx, "upstream request",
+		"url", api.redactor.URL(finalURL), "status", response.StatusCode, "duration", time.Since(start))
+
 	if response.StatusCode != http.StatusOK {
 		switch response.StatusCode {
 		case http.StatusUnauthorized:
-			return data, ErrInvalidAPIKey
+			return ErrInvalidAPIKey
 		case http.StatusBadRequest:
-			return data, ErrBadRequest
+			return ErrBadRequest
 		case http.StatusServiceUnavailable:
-			return data, ErrServiceUnavailable
+			return ErrServiceUnavailable
 		default:
-			return data, fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, response.StatusCode)
+			return fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, response.StatusCode)
 		}
 	}
 
-	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
-		return data, fmt.Errorf("%w: %v", ErrDecodingResponse, err)
+	if err := json.NewDecoder(response.Body).Decode(data); err != nil {
+		return fmt.Errorf("%w: %v", ErrDecodingResponse, err)
 	}
 
-	return data, nil
+	return nil
+}
+
+// upstreamLanguage returns the OpenWeatherMap lang parameter for the request's language. English is
+// OpenWeatherMap's default and is not sent.
+func upstreamLanguage(ctx context.Context) string {
+	if lang := i18n.FromContext(ctx); lang != i18n.English {
+		return string(lang)
+	}
+	return ""
 }
@@ -7,9 +7,12 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang2go/demo-app/weather-service-api/internal/i18n"
 	"github.com/golang2go/demo-app/weather-service-api/internal/middleware"
 	"github.com/golang2go/demo-app/weather-service-api/internal/model"
 )
@@ -200,3 +203,306 @@ func TestFetchWeatherData_UnexpectedStatusCode(t *testing.T) {
 		t.Errorf("Expected ErrUnexpectedStatusCode, got %v", err)
 	}
 }
+
+func TestFetchWeatherData_ErrorDoesNotLeakAPIKey(t *testing.T) {
+	api := NewWeatherAPI()
+
+	mockServer := setupMockServer("", http.StatusOK)
+	mockServer.Close() // Close the server so the transport error carries the full request URL
+
+	apiKey := "super-secret-api-key"
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), apiKey)
+	_, err := api.FetchWeatherData(ctx, "35", "139", mockServer.URL, "metric")
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Fatalf("Expected ErrServiceUnavailable, got %v", err)
+	}
+
+	for unwrapped := err; unwrapped != nil; unwrapped = errors.Unwrap(unwrapped) {
+		if strings.Contains(unwrapped.Error(), apiKey) {
+			t.Errorf("Error leaks the API key: %v", unwrapped)
+		}
+	}
+}
+
+func TestFetchWeatherData_FullPayload(t *testing.T) {
+	mockResponse := `{
+		"coord": {"lon": 10.99, "lat": 44.34},
+		"weather": [{"id": 501, "main": "Rain", "description": "moderate rain", "icon": "10d"}],
+		"base": "stations",
+		"main": {"temp": 298.48, "feels_like": 298.74, "temp_min": 297.56, "temp_max": 300.05,
+			"pressure": 1015, "humidity": 64, "sea_level": 1015, "grnd_level": 933},
+		"visibility": 10000,
+		"wind": {"speed": 0.62, "deg": 349, "gust": 1.18},
+		"rain": {"1h": 3.16},
+		"clouds": {"all": 100},
+		"dt": 1661870592,
+		"sys": {"type": 2, "id": 2075663, "country": "IT", "sunrise": 1661834187, "sunset": 1661882248},
+		"timezone": 7200,
+		"id": 3163858,
+		"name": "Zocca",
+		"cod": 200
+	}`
+	mockServer := setupMockServer(mockResponse, http.StatusOK)
+	defer mockServer.Close()
+
+	api := NewWeatherAPI()
+
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	data, err := api.FetchWeatherData(ctx, "44.34", "10.99", mockServer.URL, "standard")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if data.Main.FeelsLike != 298.74 || data.Main.Humidity != 64 || *data.Main.GroundLevel != 933 {
+		t.Errorf("Main block not decoded: %+v", data.Main)
+	}
+	if data.Weather[0].ID != 501 || data.Weather[0].Description != "moderate rain" || data.Weather[0].Icon != "10d" {
+		t.Errorf("Weather condition not decoded: %+v", data.Weather[0])
+	}
+	if data.Wind.Deg != 349 || data.Wind.Gust == nil || *data.Wind.Gust != 1.18 {
+		t.Errorf("Wind not decoded: %+v", data.Wind)
+	}
+	if data.Rain == nil || *data.Rain.OneHour != 3.16 || data.Snow != nil {
+		t.Errorf("Precipitation not decoded: rain=%+v snow=%+v", data.Rain, data.Snow)
+	}
+	if *data.Visibility != 10000 || data.Clouds.All != 100 {
+		t.Errorf("Visibility or clouds not decoded: %v, %v", data.Visibility, data.Clouds)
+	}
+	if data.Sys.Sunrise != 1661834187 || data.Timezone != 7200 || data.Name != "Zocca" || data.Coord.Lat != 44.34 {
+		t.Errorf("Location and sun times not decoded: %+v", data)
+	}
+}
+
+func TestFetchWeatherData_ForwardsLanguage(t *testing.T) {
+	var langs []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		langs = append(langs, r.URL.Query().Get("lang"))
+		w.Write([]byte(`{"weather": [{"id": 500, "main": "Rain", "description": "leichter Regen", "icon": "10d"}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewWeatherAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	data, err := api.FetchWeatherData(i18n.NewContext(ctx, i18n.German), "35", "139", mockServer.URL, "standard")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data.Weather[0].Description != "leichter Regen" {
+		t.Errorf("Expected the localized description, got %q", data.Weather[0].Description)
+	}
+
+	// English is OpenWeatherMap's default and is not sent
+	if _, err := api.FetchWeatherData(i18n.NewContext(ctx, i18n.English), "35", "139", mockServer.URL, "standard"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(langs) != 2 || langs[0] != "de" || langs[1] != "" {
+		t.Errorf("Expected lang parameters [de, none], got %q", langs)
+	}
+}
+
+func TestFetchForecast_Success(t *testing.T) {
+	var query url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{
+			"list": [
+				{"dt": 1704110400, "main": {"temp": 280.15}, "weather": [{"id": 800, "main": "Clear"}], "pop": 0},
+				{"dt": 1704121200, "main": {"temp": 283.15}, "weather": [{"id": 500, "main": "Rain"}], "pop": 0.6, "rain": {"3h": 1.5}}
+			],
+			"city": {"name": "Shibuya", "country": "JP", "coord": {"lat": 35, "lon": 139}, "timezone": 32400}
+		}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewForecastAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	data, err := api.FetchForecast(ctx, "35", "139", mockServer.URL, "standard")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("lat") != "35" || query.Get("lon") != "139" || query.Get("units") != "standard" || query.Get("appid") != "valid-api-key" {
+		t.Errorf("Unexpected upstream query %v", query)
+	}
+	if len(data.List) != 2 || data.List[1].Pop != 0.6 || *data.List[1].Rain.ThreeHours != 1.5 {
+		t.Errorf("Unexpected forecast steps %+v", data.List)
+	}
+	if data.City.Name != "Shibuya" || data.City.Timezone != 32400 {
+		t.Errorf("Unexpected forecast city %+v", data.City)
+	}
+}
+
+func TestFetchForecast_InvalidAPIKey(t *testing.T) {
+	mockServer := setupMockServer(`{"cod":401, "message":"Invalid API key"}`, http.StatusUnauthorized)
+	defer mockServer.Close()
+
+	api := NewForecastAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "invalid-api-key")
+
+	if _, err := api.FetchForecast(ctx, "35", "139", mockServer.URL, "standard"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestFetchMinutely_Success(t *testing.T) {
+	var query url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"lat": 35, "lon": 139, "timezone": "Asia/Tokyo", "timezone_offset": 32400,
+			"minutely": [{"dt": 1704110400, "precipitation": 0}, {"dt": 1704110460, "precipitation": 1.2}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewOneCallAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	minutely, err := api.FetchMinutely(ctx, "35", "139", mockServer.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("exclude") != "current,hourly,daily,alerts" {
+		t.Errorf("Expected every block but minutely to be excluded, got exclude=%q", query.Get("exclude"))
+	}
+	if len(minutely) != 2 || minutely[1].Dt != 1704110460 || minutely[1].Precipitation != 1.2 {
+		t.Errorf("Unexpected minutely data %+v", minutely)
+	}
+}
+
+func TestFetchOneCall_Success(t *testing.T) {
+	var query url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{
+			"lat": 35, "lon": 139, "timezone": "Asia/Tokyo", "timezone_offset": 32400,
+			"current": {"dt": 1704110400, "temp": 280.15, "feels_like": 278.15, "wind_speed": 4, "wind_gust": 7,
+				"rain": {"1h": 0.4}, "weather": [{"id": 500, "main": "Rain"}]},
+			"daily": [{"dt": 1704124800, "temp": {"min": 276.15, "max": 283.15}, "rain": 3.2, "pop": 0.8, "moon_phase": 0.75}],
+			"alerts": [{"sender_name": "JMA", "event": "Flood Warning", "start": 1704110400, "end": 1704153600, "tags": ["Flood"]}]
+		}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewOneCallAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	data, err := api.FetchOneCall(ctx, "35", "139", mockServer.URL, "standard", []OneCallBlock{BlockMinutely, BlockHourly})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("exclude") != "minutely,hourly" || query.Get("units") != "standard" {
+		t.Errorf("Unexpected upstream query %v", query)
+	}
+	if data.Current == nil || data.Current.FeelsLike != 278.15 || *data.Current.WindGust != 7 || *data.Current.Rain.OneHour != 0.4 {
+		t.Errorf("Unexpected current conditions %+v", data.Current)
+	}
+	if len(data.Daily) != 1 || data.Daily[0].Temp.Max != 283.15 || *data.Daily[0].Rain != 3.2 || data.Daily[0].MoonPhase != 0.75 {
+		t.Errorf("Unexpected daily forecast %+v", data.Daily)
+	}
+	if len(data.Alerts) != 1 || data.Alerts[0].SenderName != "JMA" || data.Alerts[0].Tags[0] != "Flood" {
+		t.Errorf("Unexpected alerts %+v", data.Alerts)
+	}
+
+	current, ok := data.CurrentWeatherData()
+	if !ok || current.Main.Temp != 280.15 || current.Timezone != 32400 || len(current.Alerts) != 1 {
+		t.Errorf("Unexpected current weather %+v", current)
+	}
+}
+
+func TestFetchOneCall_UnknownBlock(t *testing.T) {
+	api := NewOneCallAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	if _, err := api.FetchOneCall(ctx, "35", "139", "http://example.com", "standard", []OneCallBlock{"weekly"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for an unknown block, got %v", err)
+	}
+}
+
+func TestExcludeAllBut(t *testing.T) {
+	exclude := ExcludeAllBut(BlockCurrent, BlockAlerts)
+	expected := []OneCallBlock{BlockMinutely, BlockHourly, BlockDaily}
+	if len(exclude) != len(expected) {
+		t.Fatalf("ExcludeAllBut() = %v; want %v", exclude, expected)
+	}
+	for i := range expected {
+		if exclude[i] != expected[i] {
+			t.Errorf("ExcludeAllBut() = %v; want %v", exclude, expected)
+		}
+	}
+}
+
+func TestFetchAirPollution(t *testing.T) {
+	var paths []string
+	var query url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		query = r.URL.Query()
+		w.Write([]byte(`{"coord": {"lat": 35, "lon": 139}, "list": [{"dt": 1704110400, "main": {"aqi": 2},
+			"components": {"co": 230.31, "no2": 14.74, "o3": 60.08, "so2": 4.23, "pm2_5": 8.51, "pm10": 12.33}}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewAirPollutionAPI()
+	ctx := i18n.NewContext(context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key"), i18n.German)
+
+	data, err := api.FetchAirPollution(ctx, "35", "139", mockServer.URL+"/data/2.5/air_pollution")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(data.List) != 1 || data.List[0].Main.AQI != 2 || data.List[0].Components.PM25 != 8.51 {
+		t.Errorf("Unexpected air pollution %+v", data)
+	}
+	if query.Has("units") || query.Has("lang") {
+		t.Errorf("Expected no units or lang parameters, got %v", query)
+	}
+
+	if _, err := api.FetchAirPollutionForecast(ctx, "35", "139", mockServer.URL+"/data/2.5/air_pollution"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/data/2.5/air_pollution" || paths[1] != "/data/2.5/air_pollution/forecast" {
+		t.Errorf("Unexpected upstream paths %v", paths)
+	}
+}
+
+func TestFetchHistory_ChunksByWeek(t *testing.T) {
+	var starts, ends []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		starts = append(starts, query.Get("start"))
+		ends = append(ends, query.Get("end"))
+		w.Write([]byte(`{"cnt": 1, "list": [{"dt": ` + query.Get("start") + `, "main": {"temp": 280.15}}]}`))
+	}))
+	defer mockServer.Close()
+
+	api := NewHistoryAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "valid-api-key")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+	data, err := api.FetchHistory(ctx, "35", "139", mockServer.URL, start, end)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(starts) != 2 || starts[0] != "1704067200" || ends[0] != "1704672000" || starts[1] != "1704672000" || ends[1] != "1704931200" {
+		t.Errorf("Expected two weekly requests, got starts %v and ends %v", starts, ends)
+	}
+	if data.Cnt != 2 || len(data.List) != 2 || data.List[1].Dt != 1704672000 {
+		t.Errorf("Unexpected history %+v", data)
+	}
+}
+
+func TestFetchHistory_Error(t *testing.T) {
+	mockServer := setupMockServer(`{"cod":401, "message":"Invalid API key"}`, http.StatusUnauthorized)
+	defer mockServer.Close()
+
+	api := NewHistoryAPI()
+	ctx := context.WithValue(context.Background(), middleware.APIKeyContextKey("apiKey"), "invalid-api-key")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := api.FetchHistory(ctx, "35", "139", mockServer.URL, start, start.Add(time.Hour))
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+)
+
+// Observations and rollups are stored as fixed-size little-endian records that start with their time in
+// Unix nanoseconds, so a sorted block of them can be searched by time without decoding it.
+const (
+	observationSize = 8 + 2*8 + 2*4 + 8*8 // Time, coordinates, timezone offset and condition, 8 quantities
+	rollupSize      = 8 + 1 + 2*4 + 7*3*8 // Start, interval, timezone offset and count, 7 statistics
+)
+
+// Interval codes of rollup records.
+const (
+	intervalHourly byte = 1
+	intervalDaily  byte = 2
+)
+
+var le = binary.LittleEndian
+
+func appendFloat(b []byte, value float64) []byte {
+	return le.AppendUint64(b, math.Float64bits(value))
+}
+
+func floatAt(b []byte, offset int) float64 {
+	return math.Float64frombits(le.Uint64(b[offset:]))
+}
+
+// recordTime returns the time a record starts with, in Unix nanoseconds.
+func recordTime(b []byte) int64 {
+	return int64(le.Uint64(b))
+}
+
+func appendObservation(b []byte, o history.Observation) []byte {
+	b = le.AppendUint64(b, uint64(o.Time.UnixNano()))
+	b = appendFloat(b, o.Lat)
+	b = appendFloat(b, o.Lon)
+	b = le.AppendUint32(b, uint32(int32(o.TimezoneOffset)))
+	b = le.AppendUint32(b, uint32(int32(o.ConditionID)))
+	for _, value := range []float64{o.Temperature, o.FeelsLike, o.Humidity, o.Pressure, o.WindSpeed, o.WindDeg, o.Clouds, o.Precipitation} {
+		b = appendFloat(b, value)
+	}
+	return b
+}
+
+func decodeObservation(b []byte) history.Observation {
+	return history.Observation{
+		Time:           time.Unix(0, recordTime(b)).UTC(),
+		Lat:            floatAt(b, 8),
+		Lon:            floatAt(b, 16),
+		TimezoneOffset: int(int32(le.Uint32(b[24:]))),
+		ConditionID:    int(int32(le.Uint32(b[28:]))),
+		Temperature:    floatAt(b, 32),
+		FeelsLike:      floatAt(b, 40),
+		Humidity:       floatAt(b, 48),
+		Pressure:       floatAt(b, 56),
+		WindSpeed:      floatAt(b, 64),
+		WindDeg:        floatAt(b, 72),
+		Clouds:         floatAt(b, 80),
+		Precipitation:  floatAt(b, 88),
+	}
+}
+
+// appendRollup encodes a rollup without its cell, which the segment index holds.
+func appendRollup(b []byte, r history.Rollup) []byte {
+	b = le.AppendUint64(b, uint64(r.Start.UnixNano()))
+	interval := intervalHourly
+	if r.Interval == history.IntervalDaily {
+		interval = intervalDaily
+	}
+	b = append(b, interval)
+	b = le.AppendUint32(b, uint32(int32(r.TimezoneOffset)))
+	b = le.AppendUint32(b, uint32(r.Count))
+	for _, stats := range []history.Stats{r.Temperature, r.FeelsLike, r.Humidity, r.Pressure, r.WindSpeed, r.Clouds, r.Precipitation} {
+		b = appendFloat(b, stats.Min)
+		b = appendFloat(b, stats.Max)
+		b = appendFloat(b, stats.Mean)
+	}
+	return b
+}
+
+func decodeRollup(cell history.Cell, b []byte) history.Rollup {
+	r := history.Rollup{Cell: cell, Interval: history.IntervalHourly, TimezoneOffset: int(int32(le.Uint32(b[9:])))}
+	r.Start = time.Unix(0, recordTime(b)).UTC()
+	if b[8] == intervalDaily {
+		r.Interval = history.IntervalDaily
+		r.Start = r.Start.In(time.FixedZone("", r.TimezoneOffset))
+	}
+	r.Count = int(le.Uint32(b[13:]))
+	stats := make([]history.Stats, 7)
+	for i := range stats {
+		offset := 17 + i*24
+		stats[i] = history.Stats{Min: floatAt(b, offset), Max: floatAt(b, offset+8), Mean: floatAt(b, offset+16)}
+	}
+	r.Temperature, r.FeelsLike, r.Humidity, r.Pressure, r.WindSpeed, r.Clouds, r.Precipitation =
+		stats[0], stats[1], stats[2], stats[3], stats[4], stats[5], stats[6]
+	return r
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+)
+
+func TestObservationEncoding(t *testing.T) {
+	o := history.Observation{
+		Time: time.Date(2024, 1, 1, 12, 30, 15, 500, time.UTC), Lat: 35.6895, Lon: -139.6917, TimezoneOffset: -18000,
+		Temperature: 280.15, FeelsLike: 278.5, Humidity: 81, Pressure: 1013, WindSpeed: 4.1, WindDeg: 350,
+		Clouds: 75, Precipitation: 0.4, ConditionID: 500,
+	}
+
+	b := appendObservation(nil, o)
+	if len(b) != observationSize {
+		t.Fatalf("Encoded observation is %d bytes; want %d", len(b), observationSize)
+	}
+	if got := decodeObservation(b); got != o {
+		t.Errorf("decodeObservation() = %+v; want %+v", got, o)
+	}
+}
+
+func TestRollupEncoding(t *testing.T) {
+	cell := history.Cell{Lat: 3569, Lon: 13969}
+	tokyo := time.FixedZone("", 32400)
+	r := history.Rollup{
+		Cell: cell, Interval: history.IntervalDaily, TimezoneOffset: 32400,
+		Bucket: history.Bucket{
+			Start: time.Date(2024, 1, 1, 0, 0, 0, 0, tokyo), Count: 24,
+			Temperature:   history.Stats{Min: 270, Max: 280, Mean: 275.5},
+			Precipitation: history.Stats{Max: 1.2, Mean: 0.1},
+		},
+	}
+
+	b := appendRollup(nil, r)
+	if len(b) != rollupSize {
+		t.Fatalf("Encoded rollup is %d bytes; want %d", len(b), rollupSize)
+	}
+	got := decodeRollup(cell, b)
+	if got.Start.Format(time.RFC3339) != "2024-01-01T00:00:00+09:00" {
+		t.Errorf("Decoded start = %v; want Tokyo midnight", got.Start)
+	}
+	got.Start, r.Start = time.Time{}, time.Time{}
+	if got != r {
+		t.Errorf("decodeRollup() = %+v; want %+v", got, r)
+	}
+}
@@ -0,0 +1,283 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+)
+
+// A segment is an immutable file of observations and rollups, sorted by cell and time:
+//
+//	header   segmentMagic
+//	data     observation records of each cell, then rollup records of each cell
+//	index    one indexEntrySize entry per cell: cell, offset and count of its observations and rollups
+//	footer   first and last WAL ID it holds, index offset, cell count, CRC-32C of everything before it,
+//	         segmentMagic
+//
+// Segments are written to a temporary file and renamed into place once synced, so a segment file is either
+// complete or absent; a checksum mismatch means the disk corrupted it.
+const (
+	segmentMagic      = "WXSEG001"
+	indexEntrySize    = 2*4 + 2*(8+4)
+	segmentFooterSize = 3*8 + 2*4 + len(segmentMagic)
+)
+
+// ErrCorruptSegment is returned by Open when a segment file fails its checksum or is malformed.
+var ErrCorruptSegment = errors.New("corrupt segment")
+
+// segment is an open segment file.
+type segment struct {
+	id                uint64
+	walFirst, walLast uint64 // Range of WAL IDs whose observations it holds
+	path              string
+	file              *os.File
+	index             map[history.Cell]indexEntry
+}
+
+// indexEntry locates the records of a cell in a segment.
+type indexEntry struct {
+	observationsAt int64
+	observations   int
+	rollupsAt      int64
+	rollups        int
+}
+
+// cellData is the content of a cell: time-ordered observations and rollups.
+type cellData struct {
+	observations []history.Observation
+	rollups      []history.Rollup
+}
+
+// writeSegment writes the data of each cell to a new segment file and opens it.
+func writeSegment(dir string, id, walFirst, walLast uint64, cells map[history.Cell]*cellData) (*segment, error) {
+	order := make([]history.Cell, 0, len(cells))
+	for cell := range cells {
+		order = append(order, cell)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].Lat != order[j].Lat {
+			return order[i].Lat < order[j].Lat
+		}
+		return order[i].Lon < order[j].Lon
+	})
+
+	path := segmentPath(dir, id)
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error creating segment: %w", err)
+	}
+	defer os.Remove(tmp) // No-op once renamed
+
+	checksum := crc32.New(castagnoli)
+	w := bufio.NewWriter(io.MultiWriter(file, checksum))
+	offset := int64(0)
+	write := func(b []byte) {
+		w.Write(b) // Errors are sticky and returned by Flush
+		offset += int64(len(b))
+	}
+
+	write([]byte(segmentMagic))
+	index := make([]indexEntry, len(order))
+	var record []byte
+	for i, cell := range order {
+		index[i].observationsAt, index[i].observations = offset, len(cells[cell].observations)
+		for _, o := range cells[cell].observations {
+			record = appendObservation(record[:0], o)
+			write(record)
+		}
+	}
+	for i, cell := range order {
+		index[i].rollupsAt, index[i].rollups = offset, len(cells[cell].rollups)
+		for _, r := range cells[cell].rollups {
+			record = appendRollup(record[:0], r)
+			write(record)
+		}
+	}
+
+	indexAt := offset
+	for i, cell := range order {
+		entry := le.AppendUint32(record[:0], uint32(cell.Lat))
+		entry = le.AppendUint32(entry, uint32(cell.Lon))
+		entry = le.AppendUint64(entry, uint64(index[i].observationsAt))
+		entry = le.AppendUint32(entry, uint32(index[i].observations))
+		entry = le.AppendUint64(entry, uint64(index[i].rollupsAt))
+		entry = le.AppendUint32(entry, uint32(index[i].rollups))
+		write(entry)
+	}
+	footer := le.AppendUint64(record[:0], walFirst)
+	footer = le.AppendUint64(footer, walLast)
+	footer = le.AppendUint64(footer, uint64(indexAt))
+	footer = le.AppendUint32(footer, uint32(len(order)))
+	write(footer)
+
+	err = w.Flush()
+	if err == nil {
+		_, err = file.Write(append(le.AppendUint32(nil, checksum.Sum32()), segmentMagic...))
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing segment: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("error renaming segment: %w", err)
+	}
+	if err := syncDir(dir); err != nil {
+		return nil, err
+	}
+	return openSegment(dir, id)
+}
+
+// openSegment opens a segment file, verifying its checksum and loading its index.
+func openSegment(dir string, id uint64) (*segment, error) {
+	path := segmentPath(dir, id)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening segment: %w", err)
+	}
+	s := &segment{id: id, path: path, file: file}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error opening segment %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *segment) load() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if size < int64(len(segmentMagic)+segmentFooterSize) {
+		return fmt.Errorf("%w: file too short", ErrCorruptSegment)
+	}
+
+	footer := make([]byte, segmentFooterSize)
+	if _, err := s.file.ReadAt(footer, size-int64(segmentFooterSize)); err != nil {
+		return err
+	}
+	if string(footer[segmentFooterSize-len(segmentMagic):]) != segmentMagic {
+		return fmt.Errorf("%w: bad footer", ErrCorruptSegment)
+	}
+	checksum := crc32.New(castagnoli)
+	if _, err := io.Copy(checksum, io.NewSectionReader(s.file, 0, size-4-int64(len(segmentMagic)))); err != nil {
+		return err
+	}
+	if checksum.Sum32() != le.Uint32(footer[28:]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorruptSegment)
+	}
+
+	header := make([]byte, len(segmentMagic))
+	if _, err := s.file.ReadAt(header, 0); err != nil {
+		return err
+	}
+	if !bytes.Equal(header, []byte(segmentMagic)) {
+		return fmt.Errorf("%w: bad header", ErrCorruptSegment)
+	}
+
+	s.walFirst, s.walLast = le.Uint64(footer), le.Uint64(footer[8:])
+	indexAt, cells := int64(le.Uint64(footer[16:])), int64(le.Uint32(footer[24:]))
+	if indexAt+cells*indexEntrySize != size-int64(segmentFooterSize) {
+		return fmt.Errorf("%w: bad index", ErrCorruptSegment)
+	}
+	index := make([]byte, cells*indexEntrySize)
+	if _, err := s.file.ReadAt(index, indexAt); err != nil {
+		return err
+	}
+
+	s.index = make(map[history.Cell]indexEntry, cells)
+	for b := index; len(b) > 0; b = b[indexEntrySize:] {
+		cell := history.Cell{Lat: int32(le.Uint32(b)), Lon: int32(le.Uint32(b[4:]))}
+		entry := indexEntry{
+			observationsAt: int64(le.Uint64(b[8:])),
+			observations:   int(le.Uint32(b[16:])),
+			rollupsAt:      int64(le.Uint64(b[20:])),
+			rollups:        int(le.Uint32(b[28:])),
+		}
+		if entry.observationsAt+int64(entry.observations)*observationSize > indexAt ||
+			entry.rollupsAt+int64(entry.rollups)*rollupSize > indexAt {
+			return fmt.Errorf("%w: index entry out of bounds", ErrCorruptSegment)
+		}
+		s.index[cell] = entry
+	}
+	return nil
+}
+
+// observations returns the observations of a cell from from (inclusive) to to (exclusive).
+func (s *segment) observations(cell history.Cell, from, to time.Time) ([]history.Observation, error) {
+	entry := s.index[cell]
+	block, err := s.block(entry.observationsAt, entry.observations, observationSize, from, to)
+	var observations []history.Observation
+	for ; len(block) > 0; block = block[observationSize:] {
+		observations = append(observations, decodeObservation(block))
+	}
+	return observations, err
+}
+
+// rollups returns the rollups of a cell that start from from (inclusive) to to (exclusive).
+func (s *segment) rollups(cell history.Cell, from, to time.Time) ([]history.Rollup, error) {
+	entry := s.index[cell]
+	block, err := s.block(entry.rollupsAt, entry.rollups, rollupSize, from, to)
+	var rollups []history.Rollup
+	for ; len(block) > 0; block = block[rollupSize:] {
+		rollups = append(rollups, decodeRollup(cell, block))
+	}
+	return rollups, err
+}
+
+// block reads the records of a sorted block of count records of the given size at offset that start from
+// from (inclusive) to to (exclusive), locating them by binary search on their times.
+func (s *segment) block(offset int64, count, size int, from, to time.Time) ([]byte, error) {
+	var readErr error
+	search := func(t time.Time) int {
+		at := make([]byte, 8)
+		return sort.Search(count, func(i int) bool {
+			if _, err := s.file.ReadAt(at, offset+int64(i*size)); err != nil {
+				readErr = err
+				return true
+			}
+			return recordTime(at) >= t.UnixNano()
+		})
+	}
+	first, last := search(from), search(to)
+	if readErr != nil {
+		return nil, fmt.Errorf("error reading segment %s: %w", s.path, readErr)
+	}
+	if first >= last {
+		return nil, nil
+	}
+
+	block := make([]byte, (last-first)*size)
+	if _, err := s.file.ReadAt(block, offset+int64(first*size)); err != nil {
+		return nil, fmt.Errorf("error reading segment %s: %w", s.path, err)
+	}
+	return block, nil
+}
+
+// cells returns the cells that the segment has data for.
+func (s *segment) cells() []history.Cell {
+	cells := make([]history.Cell, 0, len(s.index))
+	for cell := range s.index {
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+func (s *segment) close() error {
+	return s.file.Close()
+}
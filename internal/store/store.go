@@ -0,0 +1,582 @@
+// Package store is an embedded, append-only storage engine for weather observations. It implements
+// history.RollupStore on disk with a write-ahead log, immutable segment files and background compaction,
+// which applies retention and rolls old observations up into hourly and then daily rollups.
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+	"github.com/golang2go/demo-app/weather-service-api/internal/logging"
+)
+
+var logger = logging.Logger("store")
+
+// DefaultFlushSize is the number of observations buffered in memory before they are written to a segment.
+const DefaultFlushSize = 4096
+
+// Options controls retention, rollups and compaction of a DB.
+type Options struct {
+	Retention       time.Duration // Observations and rollups older than this are dropped; 0 keeps them forever
+	RawRetention    time.Duration // Observations older than this are rolled up into hourly rollups; 0 keeps them
+	HourlyRetention time.Duration // Hourly rollups older than this are rolled up into daily ones; 0 keeps them
+	FlushSize       int           // Observations buffered before they are written to a segment; 0 for DefaultFlushSize
+	CompactEvery    time.Duration // Interval of background compaction; 0 disables it
+}
+
+// DB is a history.RollupStore that keeps observations in a directory.
+//
+// Appended observations are written to the write-ahead log and kept in memory until FlushSize of them are
+// written to a new segment. Compaction merges all segments into one, applying retention and rollups on the
+// way. On Open, segments replaced by a compacted one and WAL files already in a segment are removed, and
+// the remaining WAL records are replayed.
+type DB struct {
+	dir     string
+	options Options
+	now     func() time.Time // Clock, replaceable in tests
+
+	compacting sync.Mutex // Serializes compactions
+
+	mu       sync.RWMutex
+	closed   bool
+	nextID   uint64     // Next segment or WAL ID
+	segments []*segment // Oldest first; later segments override earlier ones
+	wal      *wal
+	walFirst uint64 // First WAL ID whose observations are in memory
+	memory   memtable
+
+	stop chan struct{} // Closed to stop background compaction
+	done sync.WaitGroup
+}
+
+// Open opens the DB in dir, creating the directory if needed, and recovers it from a crash.
+func Open(dir string, options Options) (*DB, error) {
+	if options.FlushSize <= 0 {
+		options.FlushSize = DefaultFlushSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating store directory: %w", err)
+	}
+
+	db := &DB{dir: dir, options: options, now: time.Now, nextID: 1, memory: newMemtable(), stop: make(chan struct{})}
+	if err := db.recover(); err != nil {
+		db.closeFiles()
+		return nil, err
+	}
+
+	if options.CompactEvery > 0 {
+		db.done.Add(1)
+		go db.compactPeriodically()
+	}
+	return db, nil
+}
+
+// recover opens the segments and replays the WAL files that are not in a segment yet.
+func (db *DB) recover() error {
+	files, err := listFiles(db.dir)
+	if err != nil {
+		return err
+	}
+	for _, tmp := range files.tmp {
+		os.Remove(tmp) // Left by a crash while writing a segment
+	}
+
+	for _, id := range files.segments {
+		s, err := openSegment(db.dir, id)
+		if err != nil {
+			return err
+		}
+		db.segments = append(db.segments, s)
+		db.nextID = max(db.nextID, id+1)
+	}
+	db.removeSuperseded()
+
+	var flushed uint64
+	for _, s := range db.segments {
+		flushed = max(flushed, s.walLast)
+	}
+	var replayed []uint64
+	var valid int64
+	for _, id := range files.wals {
+		db.nextID = max(db.nextID, id+1)
+		if id <= flushed {
+			os.Remove(walPath(db.dir, id)) // Left by a crash before a flush removed it
+			continue
+		}
+		valid, err = replayWAL(walPath(db.dir, id), func(payload []byte) error {
+			if len(payload)%observationSize != 0 {
+				return fmt.Errorf("error replaying write-ahead log %d: record of %d bytes", id, len(payload))
+			}
+			for ; len(payload) > 0; payload = payload[observationSize:] {
+				db.memory.insert(decodeObservation(payload))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		replayed = append(replayed, id)
+	}
+
+	if len(replayed) == 0 {
+		db.wal, err = createWAL(db.dir, db.allocateID())
+		if err == nil {
+			db.walFirst = db.wal.id
+		}
+		return err
+	}
+	db.walFirst = replayed[0]
+	db.wal, err = openWAL(db.dir, replayed[len(replayed)-1], valid)
+	return err
+}
+
+// removeSuperseded removes the segments whose WAL range a later, compacted segment holds, which a crash
+// during compaction leaves behind.
+func (db *DB) removeSuperseded() {
+	kept := db.segments[:0]
+	for i, s := range db.segments {
+		superseded := false
+		for _, later := range db.segments[i+1:] {
+			if later.walFirst <= s.walFirst && s.walLast <= later.walLast {
+				superseded = true
+				break
+			}
+		}
+		if superseded {
+			s.close()
+			os.Remove(s.path)
+			continue
+		}
+		kept = append(kept, s)
+	}
+	db.segments = kept
+}
+
+// allocateID returns a new segment or WAL ID. The caller must hold db.mu or have exclusive access.
+func (db *DB) allocateID() uint64 {
+	id := db.nextID
+	db.nextID++
+	return id
+}
+
+// Append records observations durably, replacing any at the same cell and time.
+func (db *DB) Append(ctx context.Context, observations ...history.Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+	payload := make([]byte, 0, len(observations)*observationSize)
+	for _, o := range observations {
+		payload = appendObservation(payload, o)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return os.ErrClosed
+	}
+	if err := db.wal.append(payload); err != nil {
+		return err
+	}
+	for _, o := range observations {
+		db.memory.insert(o)
+	}
+	if db.memory.count >= db.options.FlushSize {
+		return db.flush()
+	}
+	return nil
+}
+
+// Range returns the observations of a cell from from (inclusive) to to (exclusive), oldest first.
+// Observations past the retention period are left out even before compaction drops them.
+func (db *DB) Range(ctx context.Context, cell history.Cell, from, to time.Time) ([]history.Observation, error) {
+	if db.options.Retention > 0 {
+		from = latest(from, db.now().Add(-db.options.Retention))
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return nil, os.ErrClosed
+	}
+	var observations []history.Observation
+	for _, s := range db.segments {
+		found, err := s.observations(cell, from, to)
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, found...)
+	}
+	observations = append(observations, db.memory.observations(cell, from, to)...)
+	return newestByTime(observations), nil
+}
+
+// Rollups returns the rollups of a cell that start from from (inclusive) to to (exclusive), oldest first.
+func (db *DB) Rollups(ctx context.Context, cell history.Cell, from, to time.Time) ([]history.Rollup, error) {
+	var cutoff time.Time
+	if db.options.Retention > 0 {
+		cutoff = db.now().Add(-db.options.Retention)
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return nil, os.ErrClosed
+	}
+	var rollups []history.Rollup
+	for _, s := range db.segments {
+		found, err := s.rollups(cell, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range found {
+			if r.End().After(cutoff) {
+				rollups = append(rollups, r)
+			}
+		}
+	}
+	sort.SliceStable(rollups, func(i, j int) bool { return rollups[i].Start.Before(rollups[j].Start) })
+	return rollups, nil
+}
+
+// Flush writes the observations buffered in memory to a segment.
+func (db *DB) Flush() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return os.ErrClosed
+	}
+	return db.flush()
+}
+
+// flush writes the memtable to a segment and starts a new WAL file. The caller must hold db.mu.
+func (db *DB) flush() error {
+	if db.memory.count == 0 {
+		return nil
+	}
+
+	// The new WAL file comes first, so that a failed flush leaves the current one in use. After a crash in
+	// between, both are replayed.
+	next, err := createWAL(db.dir, db.allocateID())
+	if err != nil {
+		return err
+	}
+	cells := make(map[history.Cell]*cellData, len(db.memory.cells))
+	for cell, observations := range db.memory.cells {
+		cells[cell] = &cellData{observations: observations}
+	}
+	s, err := writeSegment(db.dir, db.allocateID(), db.walFirst, db.wal.id, cells)
+	if err != nil {
+		next.close()
+		os.Remove(walPath(db.dir, next.id))
+		return err
+	}
+	db.segments = append(db.segments, s)
+
+	flushed, first := db.wal, db.walFirst
+	db.wal, db.walFirst = next, next.id
+	db.memory = newMemtable()
+	flushed.close()
+	for id := first; id <= flushed.id; id++ {
+		os.Remove(walPath(db.dir, id)) // Several after recovering from a crash during a flush
+	}
+	return nil
+}
+
+// Compact flushes the memtable and merges all segments into one, dropping observations and rollups past
+// the retention period and rolling up observations and hourly rollups past theirs.
+func (db *DB) Compact(ctx context.Context) error {
+	db.compacting.Lock()
+	defer db.compacting.Unlock()
+
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return os.ErrClosed
+	}
+	err := db.flush()
+	compacted := append([]*segment(nil), db.segments...)
+	id := db.allocateID()
+	db.mu.Unlock()
+	if err != nil || len(compacted) == 0 {
+		return err
+	}
+
+	// Segments are immutable and only compaction removes them, so they are read without holding db.mu
+	cells := make(map[history.Cell]*cellData)
+	for _, s := range compacted {
+		for _, cell := range s.cells() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			observations, err := s.observations(cell, allTime[0], allTime[1])
+			if err != nil {
+				return err
+			}
+			rollups, err := s.rollups(cell, allTime[0], allTime[1])
+			if err != nil {
+				return err
+			}
+			data := cells[cell]
+			if data == nil {
+				data = &cellData{}
+				cells[cell] = data
+			}
+			data.observations = append(data.observations, observations...)
+			data.rollups = append(data.rollups, rollups...)
+		}
+	}
+	now := db.now()
+	for cell, data := range cells {
+		db.age(cell, data, now)
+		if len(data.observations) == 0 && len(data.rollups) == 0 {
+			delete(cells, cell)
+		}
+	}
+
+	merged, err := writeSegment(db.dir, id, compacted[0].walFirst, compacted[len(compacted)-1].walLast, cells)
+	if err != nil {
+		return err
+	}
+
+	// Segments flushed meanwhile follow the compacted ones
+	db.mu.Lock()
+	db.segments = append([]*segment{merged}, db.segments[len(compacted):]...)
+	db.mu.Unlock()
+
+	for _, s := range compacted {
+		s.close()
+		os.Remove(s.path)
+	}
+	return syncDir(db.dir)
+}
+
+// age applies the retention periods to the data of a cell as of now.
+func (db *DB) age(cell history.Cell, data *cellData, now time.Time) {
+	observations := newestByTime(data.observations)
+	var hourly, daily []history.Rollup
+	for _, r := range data.rollups {
+		if r.Interval == history.IntervalDaily {
+			daily = append(daily, r)
+		} else {
+			hourly = append(hourly, r)
+		}
+	}
+
+	if db.options.RawRetention > 0 {
+		cutoff := now.Add(-db.options.RawRetention).Truncate(time.Hour)
+		i := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(cutoff) })
+		hourly = append(hourly, history.RollUp(cell, observations[:i], history.IntervalHourly)...)
+		observations = observations[i:]
+	}
+	hourly = history.Coarsen(cell, hourly, history.IntervalHourly)
+
+	if db.options.HourlyRetention > 0 {
+		cutoff := now.Add(-db.options.HourlyRetention)
+		i := sort.Search(len(hourly), func(i int) bool { return !hourly[i].Start.Before(cutoff) })
+		daily = append(daily, history.Coarsen(cell, hourly[:i], history.IntervalDaily)...)
+		hourly = hourly[i:]
+	}
+	daily = history.Coarsen(cell, daily, history.IntervalDaily)
+
+	rollups := append(daily, hourly...)
+	sort.SliceStable(rollups, func(i, j int) bool { return rollups[i].Start.Before(rollups[j].Start) })
+
+	if db.options.Retention > 0 {
+		cutoff := now.Add(-db.options.Retention)
+		i := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(cutoff) })
+		observations = observations[i:]
+		kept := rollups[:0]
+		for _, r := range rollups {
+			if r.End().After(cutoff) {
+				kept = append(kept, r)
+			}
+		}
+		rollups = kept
+	}
+	data.observations, data.rollups = observations, rollups
+}
+
+// compactPeriodically runs compaction every CompactEvery until the DB is closed.
+func (db *DB) compactPeriodically() {
+	defer db.done.Done()
+
+	ticker := time.NewTicker(db.options.CompactEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			if err := db.Compact(context.Background()); err != nil {
+				logger.Warn("failed to compact history store", "dir", db.dir, "error", err)
+			}
+		}
+	}
+}
+
+// Close stops background compaction and closes the files. Observations not yet in a segment stay in the
+// write-ahead log for the next Open.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return nil
+	}
+	db.closed = true
+	close(db.stop)
+	db.mu.Unlock()
+
+	db.done.Wait()
+	db.compacting.Lock() // Wait for a compaction started by a caller
+	defer db.compacting.Unlock()
+	return db.closeFiles()
+}
+
+func (db *DB) closeFiles() error {
+	var err error
+	if db.wal != nil {
+		err = db.wal.close()
+	}
+	for _, s := range db.segments {
+		s.close()
+	}
+	return err
+}
+
+// memtable holds the observations of the current WAL files, sorted by time in each cell.
+type memtable struct {
+	cells map[history.Cell][]history.Observation
+	count int
+}
+
+func newMemtable() memtable {
+	return memtable{cells: make(map[history.Cell][]history.Observation)}
+}
+
+// insert adds an observation, replacing any at the same cell and time.
+func (m *memtable) insert(o history.Observation) {
+	cell := o.Cell()
+	observations := m.cells[cell]
+	i := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(o.Time) })
+	if i < len(observations) && observations[i].Time.Equal(o.Time) {
+		observations[i] = o
+		return
+	}
+	observations = append(observations, history.Observation{})
+	copy(observations[i+1:], observations[i:])
+	observations[i] = o
+	m.cells[cell] = observations
+	m.count++
+}
+
+// observations returns copies of the observations of a cell from from (inclusive) to to (exclusive).
+func (m *memtable) observations(cell history.Cell, from, to time.Time) []history.Observation {
+	observations := m.cells[cell]
+	first := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(from) })
+	last := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(to) })
+	if first >= last {
+		return nil
+	}
+	return append([]history.Observation(nil), observations[first:last]...)
+}
+
+// allTime spans every time a record can have.
+var allTime = [2]time.Time{time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64)}
+
+// newestByTime sorts observations gathered from the oldest source to the newest by time, keeping the
+// newest of those at the same time.
+func newestByTime(observations []history.Observation) []history.Observation {
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].Time.Before(observations[j].Time) })
+	kept := observations[:0]
+	for _, o := range observations {
+		if n := len(kept); n > 0 && kept[n-1].Time.Equal(o.Time) {
+			kept[n-1] = o
+			continue
+		}
+		kept = append(kept, o)
+	}
+	return kept
+}
+
+// latest returns the later of two times.
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// storeFiles lists the files of a store directory by kind, with segment and WAL IDs in ascending order.
+type storeFiles struct {
+	segments, wals []uint64
+	tmp            []string
+}
+
+func listFiles(dir string) (storeFiles, error) {
+	var files storeFiles
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files, fmt.Errorf("error reading store directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			files.tmp = append(files.tmp, filepath.Join(dir, name))
+			continue
+		}
+		ext := filepath.Ext(name)
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch ext {
+		case segmentExt:
+			files.segments = append(files.segments, id)
+		case walExt:
+			files.wals = append(files.wals, id)
+		}
+	}
+	sort.Slice(files.segments, func(i, j int) bool { return files.segments[i] < files.segments[j] })
+	sort.Slice(files.wals, func(i, j int) bool { return files.wals[i] < files.wals[j] })
+	return files, nil
+}
+
+// File names are zero-padded IDs, so that they sort in the order they were created.
+const (
+	segmentExt = ".seg"
+	walExt     = ".wal"
+)
+
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016d%s", id, segmentExt))
+}
+
+func walPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016d%s", id, walExt))
+}
+
+// syncDir syncs a directory, so that files created or renamed in it survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("error syncing store directory: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("error syncing store directory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang2go/demo-app/weather-service-api/internal/history"
+)
+
+var (
+	ctx   = context.Background()
+	start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // 09:00 in Tokyo
+	tokyo = history.CellOf(35.69, 139.69)
+	paris = history.CellOf(48.86, 2.35)
+)
+
+// observationAt builds an observation in Tokyo at minutes after start.
+func observationAt(minutes int, temperature float64) history.Observation {
+	return history.Observation{Time: start.Add(time.Duration(minutes) * time.Minute), Lat: 35.69, Lon: 139.69, TimezoneOffset: 32400, Temperature: temperature}
+}
+
+// openTestDB opens a DB in dir with its clock at now.
+func openTestDB(t *testing.T, dir string, options Options, now time.Time) *DB {
+	t.Helper()
+	db, err := Open(dir, options)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	db.now = func() time.Time { return now }
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// temperatures returns the temperatures of the observations of Tokyo over the first day.
+func temperatures(t *testing.T, db *DB) []float64 {
+	t.Helper()
+	observations, err := db.Range(ctx, tokyo, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Range() failed: %v", err)
+	}
+	var temperatures []float64
+	for _, o := range observations {
+		temperatures = append(temperatures, o.Temperature)
+	}
+	return temperatures
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDB_AppendAndRange(t *testing.T) {
+	db := openTestDB(t, t.TempDir(), Options{FlushSize: 3}, start)
+
+	db.Append(ctx, observationAt(60, 272), observationAt(0, 270))
+	db.Append(ctx, observationAt(30, 271), observationAt(90, 273)) // Flushed to a segment
+	db.Append(ctx, observationAt(60, 280))                         // Replaces the flushed observation
+	db.Append(ctx, history.Observation{Time: start, Lat: 48.86, Lon: 2.35, Temperature: 260})
+
+	if got := temperatures(t, db); !equalFloats(got, []float64{270, 271, 280, 273}) {
+		t.Errorf("Range() temperatures = %v; want [270 271 280 273]", got)
+	}
+	observations, _ := db.Range(ctx, tokyo, start.Add(30*time.Minute), start.Add(90*time.Minute))
+	if len(observations) != 2 || !observations[0].Time.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Range() = %+v; want the observations at 00:30 and 01:00", observations)
+	}
+	if observations, _ := db.Range(ctx, paris, start, start.Add(time.Hour)); len(observations) != 1 || observations[0].Temperature != 260 {
+		t.Errorf("Range() for Paris = %+v; want its single observation", observations)
+	}
+}
+
+func TestDB_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t, dir, Options{FlushSize: 2}, start)
+	db.Append(ctx, observationAt(0, 270), observationAt(30, 271)) // In a segment
+	db.Append(ctx, observationAt(60, 272))                        // Only in the write-ahead log
+	db.Close()
+
+	db = openTestDB(t, dir, Options{FlushSize: 2}, start)
+	if got := temperatures(t, db); !equalFloats(got, []float64{270, 271, 272}) {
+		t.Fatalf("Range() after reopening = %v; want [270 271 272]", got)
+	}
+
+	// Appending after recovery continues the same log
+	db.Append(ctx, observationAt(90, 273))
+	db.Close()
+	db = openTestDB(t, dir, Options{FlushSize: 2}, start)
+	if got := temperatures(t, db); !equalFloats(got, []float64{270, 271, 272, 273}) {
+		t.Errorf("Range() after reopening again = %v; want [270 271 272 273]", got)
+	}
+}
+
+// walFile returns the path of the only write-ahead log file in dir.
+func walFile(t *testing.T, dir string) string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+walExt))
+	if len(matches) != 1 {
+		t.Fatalf("Found write-ahead log files %v; want one", matches)
+	}
+	return matches[0]
+}
+
+func TestDB_RecoversFromTornWrites(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(data []byte) []byte
+	}{
+		{"Truncated Header", func(data []byte) []byte { return append(data, 0x10, 0) }},
+		{"Truncated Payload", func(data []byte) []byte {
+			record := data[len(data)-walHeaderSize-observationSize:]
+			return append(data, record[:walHeaderSize+10]...)
+		}},
+		{"Checksum Mismatch", func(data []byte) []byte {
+			record := append([]byte(nil), data[len(data)-walHeaderSize-observationSize:]...)
+			record[len(record)-1] ^= 0xff
+			return append(data, record...)
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			db := openTestDB(t, dir, Options{}, start)
+			db.Append(ctx, observationAt(0, 270))
+			db.Append(ctx, observationAt(30, 271))
+			db.Close()
+
+			path := walFile(t, dir)
+			data, _ := os.ReadFile(path)
+			os.WriteFile(path, tc.damage(data), 0o644)
+
+			db = openTestDB(t, dir, Options{}, start)
+			if got := temperatures(t, db); !equalFloats(got, []float64{270, 271}) {
+				t.Fatalf("Range() after recovery = %v; want the intact records [270 271]", got)
+			}
+			if info, _ := os.Stat(path); info.Size() != int64(len(data)) {
+				t.Errorf("Write-ahead log is %d bytes after recovery; want the damaged tail of %d bytes cut off", info.Size(), len(data))
+			}
+
+			db.Append(ctx, observationAt(60, 272))
+			db.Close()
+			db = openTestDB(t, dir, Options{}, start)
+			if got := temperatures(t, db); !equalFloats(got, []float64{270, 271, 272}) {
+				t.Errorf("Range() after appending to the recovered log = %v; want [270 271 272]", got)
+			}
+		})
+	}
+}
+
+// shortWriteFile writes only part of the next record and fails.
+type shortWriteFile struct {
+	logFile
+	fail bool
+}
+
+func (f *shortWriteFile) Write(p []byte) (int, error) {
+	if !f.fail {
+		return f.logFile.Write(p)
+	}
+	f.fail = false
+	n, _ := f.logFile.Write(p[:len(p)/2])
+	return n, io.ErrShortWrite
+}
+
+func TestDB_FailedAppendLeavesNoTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t, dir, Options{}, start)
+	db.Append(ctx, observationAt(0, 270))
+
+	file := &shortWriteFile{logFile: db.wal.file, fail: true}
+	db.wal.file = file
+	if err := db.Append(ctx, observationAt(30, 271)); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("Append() with a short write = %v; want io.ErrShortWrite", err)
+	}
+	if err := db.Append(ctx, observationAt(60, 272)); err != nil {
+		t.Fatalf("Append() after a short write failed: %v", err)
+	}
+	db.Close()
+
+	// The acknowledged appends on both sides of the failed one survive recovery
+	db = openTestDB(t, dir, Options{}, start)
+	if got := temperatures(t, db); !equalFloats(got, []float64{270, 272}) {
+		t.Errorf("Range() after recovery = %v; want [270 272]", got)
+	}
+}
+
+func TestDB_CorruptRecordDiscardsTheRest(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t, dir, Options{}, start)
+	db.Append(ctx, observationAt(0, 270))
+	db.Append(ctx, observationAt(30, 271))
+	db.Append(ctx, observationAt(60, 272))
+	db.Close()
+
+	// Flip a bit in the second record; records are only trusted up to the first bad one
+	path := walFile(t, dir)
+	data, _ := os.ReadFile(path)
+	data[walHeaderSize+observationSize+walHeaderSize+40] ^= 0x01
+	os.WriteFile(path, data, 0o644)
+
+	db = openTestDB(t, dir, Options{}, start)
+	if got := temperatures(t, db); !equalFloats(got, []float64{270}) {
+		t.Errorf("Range() after recovery = %v; want [270]", got)
+	}
+}
+
+func TestDB_CorruptSegment(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t, dir, Options{}, start)
+	db.Append(ctx, observationAt(0, 270))
+	if err := db.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	db.Close()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(matches) != 1 {
+		t.Fatalf("Found segments %v; want one", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	data[len(segmentMagic)+40] ^= 0x01
+	os.WriteFile(matches[0], data, 0o644)
+
+	if _, err := Open(dir, Options{}); !errors.Is(err, ErrCorruptSegment) {
+		t.Errorf("Open() error = %v; want ErrCorruptSegment", err)
+	}
+}
+
+// hourOf builds the observations of one hour in Tokyo, one every 20 minutes from 270 K up by 1 K.
+func hourOf(hour int) []history.Observation {
+	return []history.Observation{observationAt(hour*60, 270), observationAt(hour*60+20, 271), observationAt(hour*60+40, 272)}
+}
+
+func TestDB_Compact(t *testing.T) {
+	dir := t.TempDir()
+	now := start.Add(40 * 24 * time.Hour)
+	options := Options{Retention: 35 * 24 * time.Hour, RawRetention: 7 * 24 * time.Hour, HourlyRetention: 30 * 24 * time.Hour}
+	db := openTestDB(t, dir, options, now)
+
+	var observations []history.Observation
+	for _, day := range []int{0, 6, 20, 39} { // Past the retention, rolled up into days, into hours, and recent
+		for hour := 0; hour < 2; hour++ {
+			observations = append(observations, hourOf(day*24+hour)...)
+		}
+	}
+	db.Append(ctx, observations[:6]...)
+	db.Flush()
+	db.Append(ctx, observations[6:]...)
+
+	if err := db.Compact(ctx); err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt)); len(matches) != 1 {
+		t.Errorf("Found segments %v after compaction; want one", matches)
+	}
+
+	all, _ := db.Range(ctx, tokyo, start, now)
+	if len(all) != 6 || !all[0].Time.Equal(start.Add(39*24*time.Hour)) {
+		t.Errorf("Range() = %+v; want only the 6 observations of the last week", all)
+	}
+
+	rollups, err := db.Rollups(ctx, tokyo, start, now)
+	if err != nil {
+		t.Fatalf("Rollups() failed: %v", err)
+	}
+	if len(rollups) != 3 {
+		t.Fatalf("Rollups() = %+v; want a daily rollup for day 6 and hourly rollups for day 20", rollups)
+	}
+	daily, hourly := rollups[0], rollups[1]
+	if daily.Interval != history.IntervalDaily || daily.Start.Format(time.RFC3339) != "2024-01-07T00:00:00+09:00" || daily.Count != 6 {
+		t.Errorf("Daily rollup = %+v; want the 6 observations of January 7 in Tokyo", daily)
+	}
+	if hourly.Interval != history.IntervalHourly || !hourly.Start.Equal(start.Add(20*24*time.Hour)) || hourly.Count != 3 ||
+		hourly.Temperature != (history.Stats{Min: 270, Max: 272, Mean: 271}) {
+		t.Errorf("Hourly rollup = %+v; want the first hour of day 20", hourly)
+	}
+
+	// Rollups survive reopening, and a late observation of a rolled-up hour is combined with its rollup
+	db.Close()
+	db = openTestDB(t, dir, options, now)
+	db.Append(ctx, observationAt(20*24*60+50, 279))
+	db.Compact(ctx)
+	rollups, _ = db.Rollups(ctx, tokyo, start.Add(20*24*time.Hour), start.Add(21*24*time.Hour))
+	if len(rollups) != 2 || rollups[0].Count != 4 || rollups[0].Temperature.Max != 279 {
+		t.Errorf("Rollups() = %+v; want the late observation in the first hour of day 20", rollups)
+	}
+}
+
+func TestDB_RemovesSupersededSegments(t *testing.T) {
+	dir := t.TempDir()
+	db := openTestDB(t, dir, Options{}, start)
+	db.Append(ctx, observationAt(0, 270))
+	db.Flush()
+	db.Append(ctx, observationAt(30, 271))
+	db.Flush()
+
+	// Keep copies of the segments to simulate a crash before compaction removed them
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	copies := map[string][]byte{}
+	for _, path := range matches {
+		copies[path], _ = os.ReadFile(path)
+	}
+	if err := db.Compact(ctx); err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	db.Close()
+	for path, data := range copies {
+		os.WriteFile(path, data, 0o644)
+	}
+
+	db = openTestDB(t, dir, Options{}, start)
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt)); len(matches) != 1 {
+		t.Errorf("Found segments %v after reopening; want only the compacted one", matches)
+	}
+	if got := temperatures(t, db); !equalFloats(got, []float64{270, 271}) {
+		t.Errorf("Range() = %v; want [270 271]", got)
+	}
+}
+
+func TestDB_RetentionBeforeCompaction(t *testing.T) {
+	db := openTestDB(t, t.TempDir(), Options{Retention: time.Hour}, start.Add(90*time.Minute))
+	db.Append(ctx, observationAt(0, 270), observationAt(60, 272))
+
+	if got := temperatures(t, db); !equalFloats(got, []float64{272}) {
+		t.Errorf("Range() = %v; want only the observation within the retention period", got)
+	}
+}
+
+func TestDB_Closed(t *testing.T) {
+	db := openTestDB(t, t.TempDir(), Options{CompactEvery: time.Hour}, start)
+	db.Close()
+
+	if err := db.Append(ctx, observationAt(0, 270)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Append() after Close() = %v; want os.ErrClosed", err)
+	}
+	if _, err := db.Range(ctx, tokyo, start, start.Add(time.Hour)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Range() after Close() = %v; want os.ErrClosed", err)
+	}
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// The write-ahead log holds the observations that are not in a segment yet. Each Append is one record: an
+// 8-byte header with the payload length and its CRC-32C, then the encoded observations. A record is only
+// applied on recovery when it is complete and its checksum matches, so a batch is never half applied.
+const walHeaderSize = 8
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errTornRecord reports an incomplete or corrupt WAL record.
+var errTornRecord = errors.New("torn or corrupt record")
+
+// wal is an open write-ahead log file.
+type wal struct {
+	id     uint64
+	file   logFile
+	size   int64 // End of the last complete record
+	broken error // Set when a failed append could not be undone; later appends fail with it
+}
+
+// logFile is the file of a WAL, an *os.File outside tests.
+type logFile interface {
+	io.WriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// createWAL creates an empty WAL file.
+func createWAL(dir string, id uint64) (*wal, error) {
+	file, err := os.OpenFile(walPath(dir, id), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error creating write-ahead log: %w", err)
+	}
+	if err := syncDir(dir); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &wal{id: id, file: file}, nil
+}
+
+// openWAL opens an existing WAL file for appending after its last valid record, cutting off anything after
+// it.
+func openWAL(dir string, id uint64, valid int64) (*wal, error) {
+	file, err := os.OpenFile(walPath(dir, id), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening write-ahead log: %w", err)
+	}
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error truncating write-ahead log: %w", err)
+	}
+	return &wal{id: id, file: file, size: valid}, nil
+}
+
+// append writes a record and syncs it to disk. A failed write or sync may leave part of the record in the
+// file, which would hide every later record from recovery, so the file is cut back to its last complete
+// record. If that fails too, the WAL refuses further appends.
+func (w *wal) append(payload []byte) error {
+	if w.broken != nil {
+		return fmt.Errorf("write-ahead log is unusable after a failed write: %w", w.broken)
+	}
+
+	record := make([]byte, walHeaderSize, walHeaderSize+len(payload))
+	le.PutUint32(record, uint32(len(payload)))
+	le.PutUint32(record[4:], crc32.Checksum(payload, castagnoli))
+	record = append(record, payload...)
+
+	if _, err := w.file.Write(record); err != nil {
+		return w.undo(fmt.Errorf("error writing write-ahead log: %w", err))
+	}
+	if err := w.file.Sync(); err != nil {
+		return w.undo(fmt.Errorf("error syncing write-ahead log: %w", err))
+	}
+	w.size += int64(len(record))
+	return nil
+}
+
+// undo cuts the file back to the end of the last complete record after a failed append, and returns err.
+func (w *wal) undo(err error) error {
+	if truncateErr := w.file.Truncate(w.size); truncateErr != nil {
+		w.broken = truncateErr
+		return errors.Join(err, fmt.Errorf("error truncating write-ahead log: %w", truncateErr))
+	}
+	if syncErr := w.file.Sync(); syncErr != nil {
+		w.broken = syncErr
+		return errors.Join(err, fmt.Errorf("error syncing write-ahead log: %w", syncErr))
+	}
+	return err
+}
+
+func (w *wal) close() error {
+	return w.file.Close()
+}
+
+// replayWAL applies the valid records of a WAL file in order and returns the length of the valid prefix of
+// the file. Reading stops at the first torn or corrupt record, which a crash during a write leaves at the
+// end of the file.
+func replayWAL(path string, apply func(payload []byte) error) (valid int64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("error reading write-ahead log: %w", err)
+	}
+
+	for offset := 0; offset < len(data); {
+		payload, err := walRecord(data[offset:])
+		if err != nil {
+			logger.Warn("discarding the end of the write-ahead log", "path", path, "offset", offset, "bytes", len(data)-offset, "error", err)
+			break
+		}
+		if err := apply(payload); err != nil {
+			return valid, err
+		}
+		offset += walHeaderSize + len(payload)
+		valid = int64(offset)
+	}
+	return valid, nil
+}
+
+// walRecord returns the payload of the record at the start of data.
+func walRecord(data []byte) ([]byte, error) {
+	if len(data) < walHeaderSize {
+		return nil, errTornRecord
+	}
+	length, checksum := le.Uint32(data), le.Uint32(data[4:])
+	if uint64(length) > uint64(len(data)-walHeaderSize) {
+		return nil, errTornRecord
+	}
+	payload := data[walHeaderSize : walHeaderSize+int(length)]
+	if crc32.Checksum(payload, castagnoli) != checksum {
+		return nil, errTornRecord
+	}
+	return payload, nil
+}
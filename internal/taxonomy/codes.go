@@ -0,0 +1,79 @@
+package taxonomy
+
+// entry describes one OpenWeatherMap condition code.
+type entry struct {
+	description   string
+	intensity     Intensity
+	precipitation Precipitation
+	severity      int
+}
+
+// conditions lists every code documented at https://openweathermap.org/weather-conditions.
+var conditions = map[int]entry{
+	// Thunderstorm
+	200: {"thunderstorm with light rain", IntensityLight, PrecipitationRain, 3},
+	201: {"thunderstorm with rain", IntensityModerate, PrecipitationRain, 4},
+	202: {"thunderstorm with heavy rain", IntensityHeavy, PrecipitationRain, 5},
+	210: {"light thunderstorm", IntensityLight, "", 3},
+	211: {"thunderstorm", IntensityModerate, "", 4},
+	212: {"heavy thunderstorm", IntensityHeavy, "", 5},
+	221: {"ragged thunderstorm", IntensityModerate, "", 4},
+	230: {"thunderstorm with light drizzle", IntensityLight, PrecipitationDrizzle, 3},
+	231: {"thunderstorm with drizzle", IntensityModerate, PrecipitationDrizzle, 3},
+	232: {"thunderstorm with heavy drizzle", IntensityHeavy, PrecipitationDrizzle, 4},
+
+	// Drizzle
+	300: {"light intensity drizzle", IntensityLight, PrecipitationDrizzle, 1},
+	301: {"drizzle", IntensityModerate, PrecipitationDrizzle, 1},
+	302: {"heavy intensity drizzle", IntensityHeavy, PrecipitationDrizzle, 2},
+	310: {"light intensity drizzle rain", IntensityLight, PrecipitationDrizzle, 1},
+	311: {"drizzle rain", IntensityModerate, PrecipitationDrizzle, 1},
+	312: {"heavy intensity drizzle rain", IntensityHeavy, PrecipitationDrizzle, 2},
+	313: {"shower rain and drizzle", IntensityModerate, PrecipitationDrizzle, 2},
+	314: {"heavy shower rain and drizzle", IntensityHeavy, PrecipitationDrizzle, 2},
+	321: {"shower drizzle", IntensityModerate, PrecipitationDrizzle, 1},
+
+	// Rain
+	500: {"light rain", IntensityLight, PrecipitationRain, 1},
+	501: {"moderate rain", IntensityModerate, PrecipitationRain, 2},
+	502: {"heavy intensity rain", IntensityHeavy, PrecipitationRain, 3},
+	503: {"very heavy rain", IntensityHeavy, PrecipitationRain, 4},
+	504: {"extreme rain", IntensityHeavy, PrecipitationRain, 5},
+	511: {"freezing rain", IntensityModerate, PrecipitationFreezingRain, 4},
+	520: {"light intensity shower rain", IntensityLight, PrecipitationRain, 1},
+	521: {"shower rain", IntensityModerate, PrecipitationRain, 2},
+	522: {"heavy intensity shower rain", IntensityHeavy, PrecipitationRain, 3},
+	531: {"ragged shower rain", IntensityModerate, PrecipitationRain, 2},
+
+	// Snow
+	600: {"light snow", IntensityLight, PrecipitationSnow, 2},
+	601: {"snow", IntensityModerate, PrecipitationSnow, 3},
+	602: {"heavy snow", IntensityHeavy, PrecipitationSnow, 4},
+	611: {"sleet", IntensityModerate, PrecipitationSleet, 3},
+	612: {"light shower sleet", IntensityLight, PrecipitationSleet, 2},
+	613: {"shower sleet", IntensityModerate, PrecipitationSleet, 3},
+	615: {"light rain and snow", IntensityLight, PrecipitationRainAndSnow, 2},
+	616: {"rain and snow", IntensityModerate, PrecipitationRainAndSnow, 3},
+	620: {"light shower snow", IntensityLight, PrecipitationSnow, 2},
+	621: {"shower snow", IntensityModerate, PrecipitationSnow, 3},
+	622: {"heavy shower snow", IntensityHeavy, PrecipitationSnow, 4},
+
+	// Atmosphere
+	701: {"mist", "", "", 1},
+	711: {"smoke", "", "", 2},
+	721: {"haze", "", "", 1},
+	731: {"sand/dust whirls", "", "", 2},
+	741: {"fog", "", "", 2},
+	751: {"sand", "", "", 2},
+	761: {"dust", "", "", 2},
+	762: {"volcanic ash", "", "", 4},
+	771: {"squalls", "", "", 4},
+	781: {"tornado", "", "", 5},
+
+	// Clear and clouds
+	800: {"clear sky", "", "", 0},
+	801: {"few clouds", "", "", 0},
+	802: {"scattered clouds", "", "", 0},
+	803: {"broken clouds", "", "", 0},
+	804: {"overcast clouds", "", "", 0},
+}
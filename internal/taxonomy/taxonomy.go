@@ -0,0 +1,119 @@
+// Package taxonomy maps OpenWeatherMap weather condition codes (2xx-8xx) to structured conditions.
+package taxonomy
+
+import "strings"
+
+// Group is the family of a condition, given by the hundreds digit of its code.
+type Group string
+
+const (
+	GroupThunderstorm Group = "thunderstorm" // 2xx
+	GroupDrizzle      Group = "drizzle"      // 3xx
+	GroupRain         Group = "rain"         // 5xx
+	GroupSnow         Group = "snow"         // 6xx
+	GroupAtmosphere   Group = "atmosphere"   // 7xx: mist, fog, dust, squalls, ...
+	GroupClear        Group = "clear"        // 800
+	GroupClouds       Group = "clouds"       // 80x
+	GroupUnknown      Group = "unknown"
+)
+
+// Intensity is how strong a precipitating or stormy condition is. It is empty for other conditions.
+type Intensity string
+
+const (
+	IntensityLight    Intensity = "light"
+	IntensityModerate Intensity = "moderate"
+	IntensityHeavy    Intensity = "heavy"
+)
+
+// Precipitation is the type of precipitation falling. It is empty when there is none.
+type Precipitation string
+
+const (
+	PrecipitationRain         Precipitation = "rain"
+	PrecipitationDrizzle      Precipitation = "drizzle"
+	PrecipitationFreezingRain Precipitation = "freezingRain"
+	PrecipitationSnow         Precipitation = "snow"
+	PrecipitationSleet        Precipitation = "sleet"
+	PrecipitationRainAndSnow  Precipitation = "rainAndSnow"
+)
+
+// Variant tells whether OpenWeatherMap chose the day or night icon for a condition.
+type Variant string
+
+const (
+	VariantDay   Variant = "day"
+	VariantNight Variant = "night"
+)
+
+// Condition is a structured weather condition. Severity ranges from 0 (no impact) to 5 (dangerous).
+type Condition struct {
+	Code          int           `json:"code"`
+	Group         Group         `json:"group"`
+	Description   string        `json:"description"`
+	Intensity     Intensity     `json:"intensity,omitempty"`
+	Precipitation Precipitation `json:"precipitation,omitempty"`
+	Thunderstorm  bool          `json:"thunderstorm"`
+	Variant       Variant       `json:"variant,omitempty"`
+	Icon          string        `json:"icon,omitempty"`
+	Severity      int           `json:"severity"`
+}
+
+// GroupOf returns the group of a condition code.
+func GroupOf(code int) Group {
+	switch {
+	case code >= 200 && code < 300:
+		return GroupThunderstorm
+	case code >= 300 && code < 400:
+		return GroupDrizzle
+	case code >= 500 && code < 600:
+		return GroupRain
+	case code >= 600 && code < 700:
+		return GroupSnow
+	case code >= 700 && code < 800:
+		return GroupAtmosphere
+	case code == 800:
+		return GroupClear
+	case code > 800 && code < 900:
+		return GroupClouds
+	default:
+		return GroupUnknown
+	}
+}
+
+// Lookup returns the structured condition for a code, without a day/night variant. ok is false for codes
+// missing from the taxonomy, which still get the group their code range implies.
+func Lookup(code int) (condition Condition, ok bool) {
+	entry, ok := conditions[code]
+	group := GroupOf(code)
+	if !ok {
+		return Condition{Code: code, Group: group, Thunderstorm: group == GroupThunderstorm}, false
+	}
+
+	return Condition{
+		Code:          code,
+		Group:         group,
+		Description:   entry.description,
+		Intensity:     entry.intensity,
+		Precipitation: entry.precipitation,
+		Thunderstorm:  group == GroupThunderstorm,
+		Severity:      entry.severity,
+	}, true
+}
+
+// Classify returns the structured condition for a condition reported by OpenWeatherMap. The reported
+// description, which may be localized, is preferred over the taxonomy's, and the icon sets the variant.
+func Classify(code int, description, icon string) Condition {
+	condition, _ := Lookup(code)
+	if description != "" {
+		condition.Description = description
+	}
+	condition.Icon = icon
+	switch {
+	case strings.HasSuffix(icon, "d"):
+		condition.Variant = VariantDay
+	case strings.HasSuffix(icon, "n"):
+		condition.Variant = VariantNight
+	}
+	return condition
+}
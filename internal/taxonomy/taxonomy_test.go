@@ -0,0 +1,77 @@
+package taxonomy
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected Condition
+	}{
+		{202, Condition{Code: 202, Group: GroupThunderstorm, Description: "thunderstorm with heavy rain", Intensity: IntensityHeavy, Precipitation: PrecipitationRain, Thunderstorm: true, Severity: 5}},
+		{210, Condition{Code: 210, Group: GroupThunderstorm, Description: "light thunderstorm", Intensity: IntensityLight, Thunderstorm: true, Severity: 3}},
+		{300, Condition{Code: 300, Group: GroupDrizzle, Description: "light intensity drizzle", Intensity: IntensityLight, Precipitation: PrecipitationDrizzle, Severity: 1}},
+		{511, Condition{Code: 511, Group: GroupRain, Description: "freezing rain", Intensity: IntensityModerate, Precipitation: PrecipitationFreezingRain, Severity: 4}},
+		{616, Condition{Code: 616, Group: GroupSnow, Description: "rain and snow", Intensity: IntensityModerate, Precipitation: PrecipitationRainAndSnow, Severity: 3}},
+		{741, Condition{Code: 741, Group: GroupAtmosphere, Description: "fog", Severity: 2}},
+		{800, Condition{Code: 800, Group: GroupClear, Description: "clear sky"}},
+		{804, Condition{Code: 804, Group: GroupClouds, Description: "overcast clouds"}},
+	}
+
+	for _, tc := range tests {
+		condition, ok := Lookup(tc.code)
+		if !ok {
+			t.Errorf("Lookup(%d) did not find the code", tc.code)
+		}
+		if condition != tc.expected {
+			t.Errorf("Lookup(%d) = %+v; want %+v", tc.code, condition, tc.expected)
+		}
+	}
+}
+
+func TestLookupUnknownCode(t *testing.T) {
+	condition, ok := Lookup(299)
+	if ok {
+		t.Errorf("Lookup(299) found an undocumented code")
+	}
+	if condition.Group != GroupThunderstorm || !condition.Thunderstorm {
+		t.Errorf("Lookup(299) = %+v; want the thunderstorm group implied by the code range", condition)
+	}
+
+	if condition, _ := Lookup(950); condition.Group != GroupUnknown {
+		t.Errorf("Lookup(950) group = %q; want unknown", condition.Group)
+	}
+}
+
+func TestEveryCodeIsConsistent(t *testing.T) {
+	for code, entry := range conditions {
+		group := GroupOf(code)
+		if group == GroupUnknown {
+			t.Errorf("code %d has no group", code)
+		}
+		if entry.severity < 0 || entry.severity > 5 {
+			t.Errorf("code %d has severity %d outside 0-5", code, entry.severity)
+		}
+
+		precipitates := group == GroupDrizzle || group == GroupRain || group == GroupSnow
+		if precipitates && (entry.precipitation == "" || entry.intensity == "") {
+			t.Errorf("code %d in group %s lacks a precipitation type or intensity", code, group)
+		}
+		if (group == GroupAtmosphere || group == GroupClear || group == GroupClouds) && (entry.precipitation != "" || entry.intensity != "") {
+			t.Errorf("code %d in group %s has a precipitation type or intensity", code, group)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	condition := Classify(500, "Leichter Regen", "10n")
+	if condition.Description != "Leichter Regen" || condition.Variant != VariantNight || condition.Icon != "10n" {
+		t.Errorf("Classify() = %+v; want the reported description and the night variant", condition)
+	}
+
+	if condition := Classify(800, "", "01d"); condition.Description != "clear sky" || condition.Variant != VariantDay {
+		t.Errorf("Classify() = %+v; want the taxonomy description and the day variant", condition)
+	}
+	if condition := Classify(800, "", ""); condition.Variant != "" {
+		t.Errorf("Classify() variant = %q; want none without an icon", condition.Variant)
+	}
+}
@@ -0,0 +1,16 @@
+package util
+
+import "math"
+
+// compassPoints are the eight principal compass points, clockwise from north.
+var compassPoints = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// CompassPoint returns the principal compass point (N, NE, E, ... NW) nearest to a bearing in degrees.
+// Wind directions are meteorological, so the point is where the wind blows from.
+func CompassPoint(degrees float64) string {
+	sector := int(math.Round(math.Mod(degrees, 360)/45)) % len(compassPoints)
+	if sector < 0 {
+		sector += len(compassPoints)
+	}
+	return compassPoints[sector]
+}
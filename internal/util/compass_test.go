@@ -0,0 +1,31 @@
+package util
+
+import "testing"
+
+func TestCompassPoint(t *testing.T) {
+	tests := []struct {
+		degrees  float64
+		expected string
+	}{
+		{0, "N"},
+		{22.4, "N"},
+		{22.5, "NE"},
+		{90, "E"},
+		{135, "SE"},
+		{180, "S"},
+		{225, "SW"},
+		{270, "W"},
+		{315, "NW"},
+		{337.4, "NW"},
+		{337.5, "N"},
+		{360, "N"},
+		{405, "NE"},
+		{-45, "NW"},
+	}
+
+	for _, tc := range tests {
+		if point := CompassPoint(tc.degrees); point != tc.expected {
+			t.Errorf("CompassPoint(%v) = %q; want %q", tc.degrees, point, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import "math"
+
+// Derived meteorological indices. Temperatures are in °C, relative humidity in percent and wind speed in
+// m/s; formulas published for Fahrenheit and mph convert internally.
+
+// Magnus coefficients over water (Sonntag 1990), valid from -45 °C to 60 °C.
+const (
+	magnusB = 17.62
+	magnusC = 243.12 // °C
+)
+
+// DewPoint returns the dew point using the Magnus formula. Relative humidity must be above zero.
+func DewPoint(tempC, humidity float64) float64 {
+	gamma := math.Log(humidity/100) + magnusB*tempC/(magnusC+tempC)
+	return magnusC * gamma / (magnusB - gamma)
+}
+
+// HeatIndex returns the NWS heat index. Below 80 °F the NWS simple formula is used; above it, the Rothfusz
+// regression with the NWS adjustments for low humidity at 80-112 °F and high humidity at 80-87 °F.
+func HeatIndex(tempC, humidity float64) float64 {
+	t := tempC*9/5 + 32
+	rh := humidity
+
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+			0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+
+		if rh < 13 && t >= 80 && t <= 112 {
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		} else if rh > 85 && t >= 80 && t <= 87 {
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	return (hi - 32) * 5 / 9
+}
+
+// WindChill returns the NWS (2001) wind chill temperature. It is only defined at or below 50 °F with wind
+// of at least 3 mph; ok is false otherwise.
+func WindChill(tempC, windSpeed float64) (chill float64, ok bool) {
+	t := tempC*9/5 + 32
+	v := windSpeed / 0.44704
+	if t > 50 || v < 3 {
+		return 0, false
+	}
+
+	v16 := math.Pow(v, 0.16)
+	chill = 35.74 + 0.6215*t - 35.75*v16 + 0.4275*t*v16
+	return (chill - 32) * 5 / 9, true
+}
+
+// ApparentTemperature returns the Australian Bureau of Meteorology apparent temperature (Steadman 1994,
+// without solar radiation).
+func ApparentTemperature(tempC, humidity, windSpeed float64) float64 {
+	vapourPressure := humidity / 100 * 6.105 * math.Exp(17.27*tempC/(237.7+tempC)) // hPa
+	return tempC + 0.33*vapourPressure - 0.70*windSpeed - 4.00
+}
+
+// Humidex returns the Environment Canada humidex, computed from the dew point. Relative humidity must be
+// above zero.
+func Humidex(tempC, humidity float64) float64 {
+	dewPointK := DewPoint(tempC, humidity) + 273.15
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/dewPointK)) // hPa
+	return tempC + 0.5555*(vapourPressure-10)
+}
+
+// WetBulb returns the wet-bulb temperature at sea-level pressure using Stull's (2011) empirical formula.
+// It is only fitted for 5-99 % relative humidity and -20 °C to 50 °C; ok is false outside that range.
+func WetBulb(tempC, humidity float64) (wetBulb float64, ok bool) {
+	if humidity < 5 || humidity > 99 || tempC < -20 || tempC > 50 {
+		return 0, false
+	}
+
+	t, rh := tempC, humidity
+	wetBulb = t*math.Atan(0.151977*math.Sqrt(rh+8.313659)) + math.Atan(t+rh) - math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+	return wetBulb, true
+}
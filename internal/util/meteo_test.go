@@ -0,0 +1,139 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func fahrenheitToCelsius(f float64) float64 { return (f - 32) * 5 / 9 }
+func celsiusToFahrenheit(c float64) float64 { return c*9/5 + 32 }
+
+func TestDewPoint(t *testing.T) {
+	tests := []struct {
+		tempC, humidity, expected float64
+	}{
+		{20, 50, 9.3},
+		{30, 70, 23.9},
+		{0, 100, 0},
+		{-10, 80, -12.8},
+	}
+
+	for _, tc := range tests {
+		if result := DewPoint(tc.tempC, tc.humidity); math.Abs(result-tc.expected) > 0.1 {
+			t.Errorf("DewPoint(%v, %v) = %.2f; want %v", tc.tempC, tc.humidity, result, tc.expected)
+		}
+	}
+}
+
+// TestHeatIndex checks values from the NWS heat index chart, which are rounded to whole degrees Fahrenheit.
+func TestHeatIndex(t *testing.T) {
+	tests := []struct {
+		tempF, humidity, expectedF float64
+	}{
+		{80, 40, 80},
+		{86, 90, 105},
+		{90, 40, 91},
+		{90, 60, 100},
+		{90, 70, 106},
+		{90, 90, 122},
+		{96, 55, 112},
+		{100, 40, 109},
+		{100, 50, 118},
+		{104, 40, 119},
+		{110, 40, 136},
+		{70, 50, 69}, // Below 80 °F the simple formula applies
+	}
+
+	for _, tc := range tests {
+		result := celsiusToFahrenheit(HeatIndex(fahrenheitToCelsius(tc.tempF), tc.humidity))
+		if math.Abs(result-tc.expectedF) > 1 {
+			t.Errorf("HeatIndex(%v °F, %v%%) = %.1f °F; want %v °F", tc.tempF, tc.humidity, result, tc.expectedF)
+		}
+	}
+}
+
+// TestWindChill checks values from the NWS wind chill chart, rounded to whole degrees Fahrenheit.
+func TestWindChill(t *testing.T) {
+	tests := []struct {
+		tempF, windMph, expectedF float64
+	}{
+		{40, 5, 36},
+		{30, 10, 21},
+		{20, 30, 1},
+		{5, 10, -10},
+		{0, 15, -19},
+		{-10, 20, -35},
+		{-20, 60, -62},
+		{-45, 5, -63},
+	}
+
+	for _, tc := range tests {
+		chill, ok := WindChill(fahrenheitToCelsius(tc.tempF), tc.windMph*0.44704)
+		if !ok {
+			t.Fatalf("WindChill(%v °F, %v mph) is undefined", tc.tempF, tc.windMph)
+		}
+		if result := celsiusToFahrenheit(chill); math.Abs(result-tc.expectedF) > 0.6 {
+			t.Errorf("WindChill(%v °F, %v mph) = %.1f °F; want %v °F", tc.tempF, tc.windMph, result, tc.expectedF)
+		}
+	}
+
+	if _, ok := WindChill(fahrenheitToCelsius(55), 10*0.44704); ok {
+		t.Errorf("expected wind chill to be undefined above 50 °F")
+	}
+	if _, ok := WindChill(fahrenheitToCelsius(20), 2*0.44704); ok {
+		t.Errorf("expected wind chill to be undefined below 3 mph")
+	}
+}
+
+func TestApparentTemperature(t *testing.T) {
+	tests := []struct {
+		tempC, humidity, wind, expected float64
+	}{
+		{25, 50, 2, 24.8},
+		{35, 30, 0, 36.6},
+		{10, 80, 8, 3.6},
+	}
+
+	for _, tc := range tests {
+		if result := ApparentTemperature(tc.tempC, tc.humidity, tc.wind); math.Abs(result-tc.expected) > 0.1 {
+			t.Errorf("ApparentTemperature(%v, %v, %v) = %.2f; want %v", tc.tempC, tc.humidity, tc.wind, result, tc.expected)
+		}
+	}
+}
+
+// TestHumidex checks values from the Environment Canada humidex table, given by temperature and dew point.
+func TestHumidex(t *testing.T) {
+	tests := []struct {
+		tempC, dewPointC, expected float64
+	}{
+		{30, 15, 34},
+		{30, 25, 42},
+		{35, 20, 42},
+		{25, 20, 33},
+	}
+
+	for _, tc := range tests {
+		humidity := relativeHumidity(tc.tempC, tc.dewPointC)
+		if result := Humidex(tc.tempC, humidity); math.Abs(result-tc.expected) > 0.6 {
+			t.Errorf("Humidex(%v °C, dew point %v °C) = %.1f; want %v", tc.tempC, tc.dewPointC, result, tc.expected)
+		}
+	}
+}
+
+// relativeHumidity inverts the Magnus formula used by DewPoint.
+func relativeHumidity(tempC, dewPointC float64) float64 {
+	return 100 * math.Exp(magnusB*dewPointC/(magnusC+dewPointC)-magnusB*tempC/(magnusC+tempC))
+}
+
+func TestWetBulb(t *testing.T) {
+	// Stull (2011) gives 13.7 °C for 20 °C and 50 % relative humidity
+	if result, ok := WetBulb(20, 50); !ok || math.Abs(result-13.7) > 0.05 {
+		t.Errorf("WetBulb(20, 50) = %.2f, %v; want 13.7", result, ok)
+	}
+	if result, ok := WetBulb(30, 99); !ok || math.Abs(result-29.9) > 0.3 {
+		t.Errorf("WetBulb(30, 99) = %.2f, %v; want about 29.9", result, ok)
+	}
+	if _, ok := WetBulb(20, 2); ok {
+		t.Errorf("expected the wet-bulb temperature to be undefined below 5%% humidity")
+	}
+}
@@ -0,0 +1,204 @@
+package util
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedPlaceholder replaces any sensitive value removed by a Redactor.
+const RedactedPlaceholder = "REDACTED"
+
+var (
+	// DefaultSensitiveParams are query parameters that are always redacted (appid carries the OpenWeatherMap key).
+	DefaultSensitiveParams = []string{"appid"}
+	// DefaultSensitiveHeaders are headers that are always redacted (X-API-Key carries the OpenWeatherMap key).
+	DefaultSensitiveHeaders = []string{"X-API-Key", "Authorization"}
+)
+
+// Redactor strips sensitive query parameters and headers from URLs, error strings, log and span attributes.
+type Redactor struct {
+	keys        map[string]struct{} // Lower-cased parameter and header names
+	paramRegex  *regexp.Regexp      // Matches name=value pairs of sensitive parameters
+	headerRegex *regexp.Regexp      // Matches "Name: value" pairs of sensitive headers
+}
+
+// NewRedactor creates a Redactor for the given parameters and headers in addition to the defaults.
+func NewRedactor(params, headers []string) *Redactor {
+	r := &Redactor{keys: make(map[string]struct{})}
+
+	params = append(append([]string{}, DefaultSensitiveParams...), params...)
+	headers = append(append([]string{}, DefaultSensitiveHeaders...), headers...)
+
+	r.paramRegex = regexp.MustCompile(`(?i)\b(` + r.alternation(params) + `)=[^&\s"'#]*`)
+	r.headerRegex = regexp.MustCompile(`(?i)\b(` + r.alternation(headers) + `)(\s*[:=]\s*"?)(?:(?:Bearer|Basic)\s+)?[^\s",;&]+`)
+
+	return r
+}
+
+// alternation registers the names as sensitive keys and returns them as a quoted regexp alternation.
+func (r *Redactor) alternation(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r.keys[strings.ToLower(name)] = struct{}{}
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return strings.Join(quoted, "|")
+}
+
+// IsSensitive reports whether key names a sensitive parameter, header or attribute.
+func (r *Redactor) IsSensitive(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// String redacts sensitive name=value and header pairs found anywhere in s.
+func (r *Redactor) String(s string) string {
+	s = r.paramRegex.ReplaceAllString(s, "${1}="+RedactedPlaceholder)
+	return r.headerRegex.ReplaceAllString(s, "${1}${2}"+RedactedPlaceholder)
+}
+
+// URL redacts sensitive query parameters and any userinfo password in rawURL.
+func (r *Redactor) URL(rawURL string) string {
+	if parsedURL, err := url.Parse(rawURL); err == nil && parsedURL.User != nil {
+		rawURL = parsedURL.Redacted()
+	}
+	return r.String(rawURL)
+}
+
+// Error returns err with sensitive values removed from its message while keeping it matchable with errors.Is
+// and errors.As. Every error in the chain is redacted as it is unwrapped, and *url.Error values are copied
+// with their URL scrubbed, so unwrapping cannot reveal sensitive values either. err itself is not modified.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		scrubbed := *urlErr
+		scrubbed.URL = r.URL(urlErr.URL)
+		scrubbed.Err = r.Error(urlErr.Err)
+		return &scrubbed
+	}
+
+	if !r.leaks(err) {
+		return err
+	}
+	return &redactedError{msg: r.String(err.Error()), err: err, redactor: r}
+}
+
+// leaks reports whether the message of err or of any error it wraps contains sensitive values.
+func (r *Redactor) leaks(err error) bool {
+	if err == nil {
+		return false
+	}
+	if msg := err.Error(); r.String(msg) != msg {
+		return true
+	}
+	if urlErr, ok := err.(*url.Error); ok && r.URL(urlErr.URL) != urlErr.URL {
+		return true
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return r.leaks(wrapper.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range wrapper.Unwrap() {
+			if r.leaks(wrapped) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Header returns a copy of h with the values of sensitive headers replaced.
+func (r *Redactor) Header(h http.Header) http.Header {
+	clone := h.Clone()
+	for name, values := range clone {
+		if r.IsSensitive(name) {
+			for i := range values {
+				values[i] = RedactedPlaceholder
+			}
+		}
+	}
+	return clone
+}
+
+// Attr returns the redacted value for a log or span attribute.
+func (r *Redactor) Attr(key, value string) string {
+	if r.IsSensitive(key) {
+		return RedactedPlaceholder
+	}
+	return r.String(value)
+}
+
+// ReplaceAttr redacts attributes for use as slog.HandlerOptions.ReplaceAttr.
+func (r *Redactor) ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if r.IsSensitive(a.Key) {
+		return slog.String(a.Key, RedactedPlaceholder)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.String(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.Any(a.Key, r.Error(err))
+		}
+	}
+	return a
+}
+
+// Writer wraps w so that everything written through it is redacted, e.g. as the output of the standard logger.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactingWriter{redactor: r, out: w}
+}
+
+type redactedError struct {
+	msg      string
+	err      error
+	redactor *Redactor
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+// Unwrap returns the redacted errors that the original error wraps, never the original itself, whose
+// message still holds the sensitive values.
+func (e *redactedError) Unwrap() []error {
+	var wrapped []error
+	switch wrapper := e.err.(type) {
+	case interface{ Unwrap() error }:
+		wrapped = []error{wrapper.Unwrap()}
+	case interface{ Unwrap() []error }:
+		wrapped = wrapper.Unwrap()
+	}
+
+	redacted := make([]error, 0, len(wrapped))
+	for _, err := range wrapped {
+		if err != nil {
+			redacted = append(redacted, e.redactor.Error(err))
+		}
+	}
+	return redacted
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	out      io.Writer
+}
+
+// Write redacts p before passing it on, reporting the original length so callers see a complete write.
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,170 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const secretKey = "s3cr3t-owm-key"
+
+func TestRedactorString(t *testing.T) {
+	redactor := NewRedactor([]string{"token"}, []string{"X-Custom-Secret"})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Query Parameter", "https://api.openweathermap.org/data/2.5/weather?appid=" + secretKey + "&lat=35",
+			"https://api.openweathermap.org/data/2.5/weather?appid=REDACTED&lat=35"},
+		{"Mixed Case Parameter", "/weather?APPID=" + secretKey, "/weather?APPID=REDACTED"},
+		{"Configured Parameter", "/weather?lat=35&token=" + secretKey + "&lon=139", "/weather?lat=35&token=REDACTED&lon=139"},
+		{"Default Header", "X-API-Key: " + secretKey, "X-API-Key: REDACTED"},
+		{"Bearer Header", "Authorization: Bearer " + secretKey, "Authorization: REDACTED"},
+		{"Configured Header", "x-custom-secret=" + secretKey + " other", "x-custom-secret=REDACTED other"},
+		{"Similar Parameter Untouched", "/weather?myappid=1", "/weather?myappid=1"},
+		{"Nothing Sensitive", "lat=35 lon=139", "lat=35 lon=139"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := redactor.String(tc.input); result != tc.expected {
+				t.Errorf("String(%q) = %q; want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRedactorURLUserinfo(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+
+	result := redactor.URL("https://user:" + secretKey + "@example.com/weather?appid=" + secretKey)
+	if strings.Contains(result, secretKey) {
+		t.Errorf("URL() leaked the secret: %s", result)
+	}
+}
+
+func TestRedactorError(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+	sentinel := errors.New("service unavailable")
+
+	urlErr := &url.Error{Op: "Get", URL: "http://127.0.0.1:1/weather?appid=" + secretKey, Err: errors.New("connection refused")}
+	err := redactor.Error(fmt.Errorf("%w: %w", sentinel, urlErr))
+
+	if strings.Contains(err.Error(), secretKey) {
+		t.Errorf("Error() leaked the secret: %v", err)
+	}
+	if !errors.Is(err, sentinel) {
+		t.Errorf("Error() broke the error chain for errors.Is")
+	}
+	var scrubbed *url.Error
+	if !errors.As(err, &scrubbed) || strings.Contains(scrubbed.Error(), secretKey) {
+		t.Errorf("Error() did not scrub the wrapped *url.Error: %v", scrubbed)
+	}
+	if !strings.Contains(urlErr.URL, secretKey) {
+		t.Errorf("Error() modified the caller's *url.Error: %v", urlErr)
+	}
+	if redactor.Error(nil) != nil {
+		t.Errorf("Error(nil) should return nil")
+	}
+}
+
+func TestRedactorErrorChain(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+	sentinel := errors.New("unauthorized")
+
+	// The key is in a wrapped error only, as with a wrapper that does not repeat its cause
+	inner := fmt.Errorf("GET /weather?appid=%s: %w", secretKey, sentinel)
+	err := redactor.Error(&opaqueError{err: fmt.Errorf("request failed: %w", inner)})
+	if !errors.Is(err, sentinel) {
+		t.Errorf("Error() broke the error chain for errors.Is")
+	}
+
+	// Nothing reachable by unwrapping may carry the key
+	for queue := []error{err}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		if strings.Contains(current.Error(), secretKey) {
+			t.Errorf("Unwrapping reached an error that leaks the secret: %v", current)
+		}
+		switch wrapper := current.(type) {
+		case interface{ Unwrap() error }:
+			if wrapped := wrapper.Unwrap(); wrapped != nil {
+				queue = append(queue, wrapped)
+			}
+		case interface{ Unwrap() []error }:
+			queue = append(queue, wrapper.Unwrap()...)
+		}
+	}
+
+	clean := errors.New("connection refused")
+	if redactor.Error(clean) != clean {
+		t.Errorf("Error() should return errors without sensitive values unchanged")
+	}
+}
+
+// opaqueError hides the message of the error it wraps.
+type opaqueError struct{ err error }
+
+func (e *opaqueError) Error() string { return "upstream error" }
+func (e *opaqueError) Unwrap() error { return e.err }
+
+func TestRedactorHeader(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+
+	header := http.Header{}
+	header.Set("X-API-Key", secretKey)
+	header.Set("Accept", "application/json")
+
+	redacted := redactor.Header(header)
+	if redacted.Get("X-API-Key") != RedactedPlaceholder {
+		t.Errorf("Header() did not redact X-API-Key, got %q", redacted.Get("X-API-Key"))
+	}
+	if redacted.Get("Accept") != "application/json" {
+		t.Errorf("Header() changed a non-sensitive header")
+	}
+	if header.Get("X-API-Key") != secretKey {
+		t.Errorf("Header() modified the original header")
+	}
+}
+
+func TestRedactorAttr(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+
+	if value := redactor.Attr("appid", secretKey); value != RedactedPlaceholder {
+		t.Errorf("Attr() for a sensitive key = %q; want %q", value, RedactedPlaceholder)
+	}
+	if value := redactor.Attr("http.url", "/weather?appid="+secretKey); strings.Contains(value, secretKey) {
+		t.Errorf("Attr() leaked the secret in a URL value: %q", value)
+	}
+}
+
+func TestRedactorLogOutputNeverContainsKey(t *testing.T) {
+	redactor := NewRedactor(nil, nil)
+	var buf bytes.Buffer
+
+	// Standard logger
+	logger := log.New(redactor.Writer(&buf), "", 0)
+	logger.Printf("Get \"https://api.openweathermap.org/data/2.5/weather?appid=%s&lat=1\": dial tcp: timeout", secretKey)
+
+	// Structured logger
+	slogger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: redactor.ReplaceAttr}))
+	slogger.Info("upstream request",
+		"url", "https://api.openweathermap.org/data/2.5/weather?appid="+secretKey,
+		"appid", secretKey,
+		"X-API-Key", secretKey,
+		"error", &url.Error{Op: "Get", URL: "/weather?appid=" + secretKey, Err: errors.New("EOF")})
+
+	if strings.Contains(buf.String(), secretKey) {
+		t.Fatalf("log output contains the API key:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), RedactedPlaceholder) {
+		t.Errorf("log output does not contain the redaction placeholder:\n%s", buf.String())
+	}
+}
@@ -1,6 +1,8 @@
 package util
 
 // ConvertTempToFahrenheit converts temperature from Celsius / Kelvin to Fahrenheit.
+//
+// Deprecated: Use units.ConvertTemperature, which supports more units and rejects unknown ones.
 func ConvertTempToFahrenheit(temp float64, unit string) float64 {
 	switch unit {
 	case "metric": // Celsius to Fahrenheit
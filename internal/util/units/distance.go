@@ -0,0 +1,57 @@
+package units
+
+// DistanceUnit identifies a unit of length, used for visibility.
+type DistanceUnit string
+
+const (
+	Meters        DistanceUnit = "m"
+	Kilometers    DistanceUnit = "km"
+	Feet          DistanceUnit = "ft"
+	Miles         DistanceUnit = "mi"
+	NauticalMiles DistanceUnit = "nmi"
+)
+
+// DistanceUnits lists every supported distance unit.
+var DistanceUnits = []DistanceUnit{Meters, Kilometers, Feet, Miles, NauticalMiles}
+
+var distanceUnits = map[DistanceUnit]linearUnit{
+	Meters:        {"m", 1},
+	Kilometers:    {"km", 1000},
+	Feet:          {"ft", 0.3048},
+	Miles:         {"mi", 1609.344},
+	NauticalMiles: {"nmi", 1852},
+}
+
+// ParseDistanceUnit parses a case-insensitive unit identifier such as "km" or "MI".
+func ParseDistanceUnit(s string) (DistanceUnit, error) {
+	return parseUnit(s, DistanceUnits, "distance")
+}
+
+// Symbol returns the display symbol of the unit, e.g. "km".
+func (u DistanceUnit) Symbol() string {
+	if unit, ok := distanceUnits[u]; ok {
+		return unit.symbol
+	}
+	return string(u)
+}
+
+// Distance is a length in a given unit.
+type Distance struct {
+	Value float64
+	Unit  DistanceUnit
+}
+
+// To converts the distance to another unit.
+func (d Distance) To(unit DistanceUnit) (Distance, error) {
+	value, err := convertLinear(d.Value, d.Unit, unit, distanceUnits, "distance")
+	if err != nil {
+		return Distance{}, err
+	}
+	return Distance{Value: value, Unit: unit}, nil
+}
+
+// ConvertDistance converts a distance value between units.
+func ConvertDistance(value float64, from, to DistanceUnit) (float64, error) {
+	converted, err := Distance{Value: value, Unit: from}.To(to)
+	return converted.Value, err
+}
@@ -0,0 +1,53 @@
+package units
+
+// PrecipitationUnit identifies a unit of precipitation depth.
+type PrecipitationUnit string
+
+const (
+	Millimeters PrecipitationUnit = "mm"
+	Centimeters PrecipitationUnit = "cm"
+	Inches      PrecipitationUnit = "in"
+)
+
+// PrecipitationUnits lists every supported precipitation unit.
+var PrecipitationUnits = []PrecipitationUnit{Millimeters, Centimeters, Inches}
+
+var precipitationUnits = map[PrecipitationUnit]linearUnit{
+	Millimeters: {"mm", 1},
+	Centimeters: {"cm", 10},
+	Inches:      {"in", 25.4},
+}
+
+// ParsePrecipitationUnit parses a case-insensitive unit identifier such as "mm" or "IN".
+func ParsePrecipitationUnit(s string) (PrecipitationUnit, error) {
+	return parseUnit(s, PrecipitationUnits, "precipitation")
+}
+
+// Symbol returns the display symbol of the unit, e.g. "mm".
+func (u PrecipitationUnit) Symbol() string {
+	if unit, ok := precipitationUnits[u]; ok {
+		return unit.symbol
+	}
+	return string(u)
+}
+
+// Precipitation is a precipitation depth in a given unit.
+type Precipitation struct {
+	Value float64
+	Unit  PrecipitationUnit
+}
+
+// To converts the precipitation depth to another unit.
+func (p Precipitation) To(unit PrecipitationUnit) (Precipitation, error) {
+	value, err := convertLinear(p.Value, p.Unit, unit, precipitationUnits, "precipitation")
+	if err != nil {
+		return Precipitation{}, err
+	}
+	return Precipitation{Value: value, Unit: unit}, nil
+}
+
+// ConvertPrecipitation converts a precipitation depth between units.
+func ConvertPrecipitation(value float64, from, to PrecipitationUnit) (float64, error) {
+	converted, err := Precipitation{Value: value, Unit: from}.To(to)
+	return converted.Value, err
+}
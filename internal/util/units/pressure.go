@@ -0,0 +1,56 @@
+package units
+
+// PressureUnit identifies a unit of atmospheric pressure.
+type PressureUnit string
+
+const (
+	Hectopascal          PressureUnit = "hPa"
+	Kilopascal           PressureUnit = "kPa"
+	InchesOfMercury      PressureUnit = "inHg"
+	MillimetersOfMercury PressureUnit = "mmHg"
+)
+
+// PressureUnits lists every supported pressure unit.
+var PressureUnits = []PressureUnit{Hectopascal, Kilopascal, InchesOfMercury, MillimetersOfMercury}
+
+// Mercury columns use the conventional values at 0 °C: 1 inHg = 3386.38866667 Pa and 1 mmHg = 133.322387415 Pa.
+var pressureUnits = map[PressureUnit]linearUnit{
+	Hectopascal:          {"hPa", 1},
+	Kilopascal:           {"kPa", 10},
+	InchesOfMercury:      {"inHg", 33.8638866667},
+	MillimetersOfMercury: {"mmHg", 1.33322387415},
+}
+
+// ParsePressureUnit parses a case-insensitive unit identifier such as "hpa" or "inHg".
+func ParsePressureUnit(s string) (PressureUnit, error) {
+	return parseUnit(s, PressureUnits, "pressure")
+}
+
+// Symbol returns the display symbol of the unit, e.g. "inHg".
+func (u PressureUnit) Symbol() string {
+	if unit, ok := pressureUnits[u]; ok {
+		return unit.symbol
+	}
+	return string(u)
+}
+
+// Pressure is a pressure value in a given unit.
+type Pressure struct {
+	Value float64
+	Unit  PressureUnit
+}
+
+// To converts the pressure to another unit.
+func (p Pressure) To(unit PressureUnit) (Pressure, error) {
+	value, err := convertLinear(p.Value, p.Unit, unit, pressureUnits, "pressure")
+	if err != nil {
+		return Pressure{}, err
+	}
+	return Pressure{Value: value, Unit: unit}, nil
+}
+
+// ConvertPressure converts a pressure value between units.
+func ConvertPressure(value float64, from, to PressureUnit) (float64, error) {
+	converted, err := Pressure{Value: value, Unit: from}.To(to)
+	return converted.Value, err
+}
@@ -0,0 +1,106 @@
+package units
+
+import (
+	"fmt"
+	"math"
+)
+
+// SpeedUnit identifies a unit of speed, such as for wind.
+type SpeedUnit string
+
+const (
+	MetersPerSecond   SpeedUnit = "ms"
+	KilometersPerHour SpeedUnit = "kmh"
+	MilesPerHour      SpeedUnit = "mph"
+	Knots             SpeedUnit = "kn"
+	Beaufort          SpeedUnit = "bft" // Continuous Beaufort number, v = 0.836 * B^(3/2) m/s
+)
+
+// SpeedUnits lists every supported speed unit.
+var SpeedUnits = []SpeedUnit{MetersPerSecond, KilometersPerHour, MilesPerHour, Knots, Beaufort}
+
+var speedUnits = map[SpeedUnit]linearUnit{
+	MetersPerSecond:   {"m/s", 1},
+	KilometersPerHour: {"km/h", 1 / 3.6},
+	MilesPerHour:      {"mph", 0.44704},
+	Knots:             {"kn", 1852.0 / 3600},
+}
+
+// beaufortCoefficient relates wind speed in m/s to the Beaufort number (WMO empirical formula).
+const beaufortCoefficient = 0.836
+
+// beaufortLimits are the upper wind speeds in m/s of forces 0 through 11; anything faster is force 12.
+var beaufortLimits = []float64{0.5, 1.5, 3.3, 5.5, 7.9, 10.7, 13.8, 17.1, 20.7, 24.4, 28.4, 32.6}
+
+// ParseSpeedUnit parses a case-insensitive unit identifier such as "kmh" or "KN".
+func ParseSpeedUnit(s string) (SpeedUnit, error) {
+	return parseUnit(s, SpeedUnits, "speed")
+}
+
+// Symbol returns the display symbol of the unit, e.g. "km/h".
+func (u SpeedUnit) Symbol() string {
+	if u == Beaufort {
+		return "Bft"
+	}
+	if unit, ok := speedUnits[u]; ok {
+		return unit.symbol
+	}
+	return string(u)
+}
+
+// Speed is a speed value in a given unit.
+type Speed struct {
+	Value float64
+	Unit  SpeedUnit
+}
+
+// To converts the speed to another unit. Negative speeds cannot be expressed on the Beaufort scale.
+func (s Speed) To(unit SpeedUnit) (Speed, error) {
+	value, unitFrom := s.Value, s.Unit
+	if unitFrom == Beaufort {
+		if value < 0 {
+			return Speed{}, fmt.Errorf("%w: Beaufort number %v", ErrOutOfRange, value)
+		}
+		value, unitFrom = beaufortCoefficient*math.Pow(value, 1.5), MetersPerSecond
+	}
+
+	if unit == Beaufort {
+		metersPerSecond, err := convertLinear(value, unitFrom, MetersPerSecond, speedUnits, "speed")
+		if err != nil {
+			return Speed{}, err
+		}
+		if metersPerSecond < 0 {
+			return Speed{}, fmt.Errorf("%w: negative speed on the Beaufort scale", ErrOutOfRange)
+		}
+		return Speed{Value: math.Pow(metersPerSecond/beaufortCoefficient, 2.0/3), Unit: Beaufort}, nil
+	}
+
+	converted, err := convertLinear(value, unitFrom, unit, speedUnits, "speed")
+	if err != nil {
+		return Speed{}, err
+	}
+	return Speed{Value: converted, Unit: unit}, nil
+}
+
+// BeaufortForce returns the Beaufort force (0-12) of the speed using the WMO speed bands.
+func (s Speed) BeaufortForce() (int, error) {
+	metersPerSecond, err := s.To(MetersPerSecond)
+	if err != nil {
+		return 0, err
+	}
+
+	// Bands are published to one decimal, so round before comparing against their upper limits
+	speed := Round(metersPerSecond.Value, 1)
+	for force, limit := range beaufortLimits {
+		if speed < limit || (force > 0 && speed <= limit) {
+			return force, nil
+		}
+	}
+	return 12, nil
+}
+
+// ConvertSpeed converts a speed value between units.
+func ConvertSpeed(value float64, from, to SpeedUnit) (float64, error) {
+	converted, err := Speed{Value: value, Unit: from}.To(to)
+	return converted.Value, err
+}
@@ -0,0 +1,71 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvertSpeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to SpeedUnit
+		expected float64
+	}{
+		{"m/s to km/h", 10, MetersPerSecond, KilometersPerHour, 36},
+		{"mph to m/s", 1, MilesPerHour, MetersPerSecond, 0.44704},
+		{"knots to km/h", 1, Knots, KilometersPerHour, 1.852},
+		{"Beaufort 4 to m/s", 4, Beaufort, MetersPerSecond, 6.688},
+		{"m/s to Beaufort", 0.836, MetersPerSecond, Beaufort, 1},
+		{"calm to Beaufort", 0, KilometersPerHour, Beaufort, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ConvertSpeed(tc.value, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("ConvertSpeed returned an unexpected error: %v", err)
+			}
+			if math.Abs(result-tc.expected) > 1e-3 {
+				t.Errorf("ConvertSpeed(%v, %s, %s) = %v; want %v", tc.value, tc.from, tc.to, result, tc.expected)
+			}
+		})
+	}
+
+	if _, err := ConvertSpeed(-1, MetersPerSecond, Beaufort); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange for a negative speed, got %v", err)
+	}
+	if _, err := ConvertSpeed(-1, Beaufort, MetersPerSecond); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange for a negative Beaufort number, got %v", err)
+	}
+}
+
+func TestBeaufortForce(t *testing.T) {
+	tests := []struct {
+		speed    Speed
+		expected int
+	}{
+		{Speed{0, MetersPerSecond}, 0},
+		{Speed{0.4, MetersPerSecond}, 0},
+		{Speed{0.5, MetersPerSecond}, 1},
+		{Speed{1.5, MetersPerSecond}, 1},
+		{Speed{1.6, MetersPerSecond}, 2},
+		{Speed{10.7, MetersPerSecond}, 5},
+		{Speed{10.8, MetersPerSecond}, 6},
+		{Speed{32.6, MetersPerSecond}, 11},
+		{Speed{32.7, MetersPerSecond}, 12},
+		{Speed{60, Knots}, 11},
+		{Speed{100, KilometersPerHour}, 10},
+	}
+
+	for _, tc := range tests {
+		force, err := tc.speed.BeaufortForce()
+		if err != nil {
+			t.Fatalf("BeaufortForce returned an unexpected error: %v", err)
+		}
+		if force != tc.expected {
+			t.Errorf("%+v.BeaufortForce() = %d; want %d", tc.speed, force, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unit systems understood by OpenWeatherMap and accepted in the units query parameter.
+const (
+	Standard = "standard"
+	Metric   = "metric"
+	Imperial = "imperial"
+)
+
+// CanonicalSystem is the OpenWeatherMap unit system used for every upstream request, so responses
+// can be shared regardless of the units a client asks for. Values are converted on output.
+const CanonicalSystem = Standard
+
+// Set selects the unit for each kind of value in a response.
+type Set struct {
+	Temperature   TemperatureUnit
+	Speed         SpeedUnit
+	Pressure      PressureUnit
+	Distance      DistanceUnit
+	Precipitation PrecipitationUnit
+}
+
+// ForSystem returns the output units of a named unit system: standard, metric or imperial.
+func ForSystem(system string) (Set, error) {
+	switch strings.ToLower(system) {
+	case Standard:
+		return Set{Kelvin, MetersPerSecond, Hectopascal, Meters, Millimeters}, nil
+	case Metric:
+		return Set{Celsius, MetersPerSecond, Hectopascal, Meters, Millimeters}, nil
+	case Imperial:
+		return Set{Fahrenheit, MilesPerHour, InchesOfMercury, Miles, Inches}, nil
+	default:
+		return Set{}, fmt.Errorf("%w: unit system %q", ErrUnknownUnit, system)
+	}
+}
+
+// Upstream returns the units OpenWeatherMap reports values in for one of its unit systems.
+// Pressure, visibility and precipitation are always hPa, meters and millimeters.
+func Upstream(system string) (Set, error) {
+	set := Set{Kelvin, MetersPerSecond, Hectopascal, Meters, Millimeters}
+	switch system {
+	case Standard:
+	case Metric:
+		set.Temperature = Celsius
+	case Imperial:
+		set.Temperature = Fahrenheit
+		set.Speed = MilesPerHour
+	default:
+		return Set{}, fmt.Errorf("%w: unit system %q", ErrUnknownUnit, system)
+	}
+	return set, nil
+}
+
+// WithOverrides replaces the units for which a non-empty override is given, e.g. temp=C or wind=kmh.
+func (s Set) WithOverrides(temperature, speed, pressure, distance, precipitation string) (Set, error) {
+	var err error
+	if temperature != "" {
+		if s.Temperature, err = ParseTemperatureUnit(temperature); err != nil {
+			return s, err
+		}
+	}
+	if speed != "" {
+		if s.Speed, err = ParseSpeedUnit(speed); err != nil {
+			return s, err
+		}
+	}
+	if pressure != "" {
+		if s.Pressure, err = ParsePressureUnit(pressure); err != nil {
+			return s, err
+		}
+	}
+	if distance != "" {
+		if s.Distance, err = ParseDistanceUnit(distance); err != nil {
+			return s, err
+		}
+	}
+	if precipitation != "" {
+		if s.Precipitation, err = ParsePrecipitationUnit(precipitation); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
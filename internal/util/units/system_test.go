@@ -0,0 +1,42 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForSystemWithOverrides(t *testing.T) {
+	set, err := ForSystem("Metric")
+	if err != nil {
+		t.Fatalf("ForSystem returned an unexpected error: %v", err)
+	}
+
+	set, err = set.WithOverrides("f", "KMH", "hpa", "", "in")
+	if err != nil {
+		t.Fatalf("WithOverrides returned an unexpected error: %v", err)
+	}
+
+	expected := Set{Fahrenheit, KilometersPerHour, Hectopascal, Meters, Inches}
+	if set != expected {
+		t.Errorf("WithOverrides() = %+v; want %+v", set, expected)
+	}
+
+	if _, err := ForSystem("nautical"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected ErrUnknownUnit for an unknown unit system, got %v", err)
+	}
+	if _, err := set.WithOverrides("", "", "bar", "", ""); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected ErrUnknownUnit for an unknown pressure override, got %v", err)
+	}
+}
+
+func TestUpstream(t *testing.T) {
+	if set, err := Upstream(Imperial); err != nil || set.Temperature != Fahrenheit || set.Speed != MilesPerHour || set.Pressure != Hectopascal {
+		t.Errorf("Upstream(imperial) = %+v, %v", set, err)
+	}
+	if set, err := Upstream(CanonicalSystem); err != nil || set.Temperature != Kelvin || set.Speed != MetersPerSecond {
+		t.Errorf("Upstream(standard) = %+v, %v", set, err)
+	}
+	if _, err := Upstream("kelvin"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected ErrUnknownUnit for an unknown unit system, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package units
+
+import "fmt"
+
+// TemperatureUnit identifies a temperature scale.
+type TemperatureUnit string
+
+const (
+	Kelvin     TemperatureUnit = "K"
+	Celsius    TemperatureUnit = "C"
+	Fahrenheit TemperatureUnit = "F"
+	Rankine    TemperatureUnit = "R"
+)
+
+// TemperatureUnits lists every supported temperature unit.
+var TemperatureUnits = []TemperatureUnit{Kelvin, Celsius, Fahrenheit, Rankine}
+
+var temperatureSymbols = map[TemperatureUnit]string{Kelvin: "K", Celsius: "°C", Fahrenheit: "°F", Rankine: "°R"}
+
+// ParseTemperatureUnit parses a case-insensitive unit identifier such as "c" or "F".
+func ParseTemperatureUnit(s string) (TemperatureUnit, error) {
+	return parseUnit(s, TemperatureUnits, "temperature")
+}
+
+// Symbol returns the display symbol of the unit, e.g. "°C".
+func (u TemperatureUnit) Symbol() string {
+	if symbol, ok := temperatureSymbols[u]; ok {
+		return symbol
+	}
+	return string(u)
+}
+
+// Temperature is a temperature value in a given unit.
+type Temperature struct {
+	Value float64
+	Unit  TemperatureUnit
+}
+
+// To converts the temperature to another unit.
+func (t Temperature) To(unit TemperatureUnit) (Temperature, error) {
+	kelvin, err := t.kelvin()
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	var value float64
+	switch unit {
+	case Kelvin:
+		value = kelvin
+	case Celsius:
+		value = kelvin - 273.15
+	case Fahrenheit:
+		value = kelvin*9/5 - 459.67
+	case Rankine:
+		value = kelvin * 9 / 5
+	default:
+		return Temperature{}, fmt.Errorf("%w: temperature %q", ErrUnknownUnit, unit)
+	}
+	return Temperature{Value: value, Unit: unit}, nil
+}
+
+// kelvin returns the temperature in kelvin.
+func (t Temperature) kelvin() (float64, error) {
+	switch t.Unit {
+	case Kelvin:
+		return t.Value, nil
+	case Celsius:
+		return t.Value + 273.15, nil
+	case Fahrenheit:
+		return (t.Value + 459.67) * 5 / 9, nil
+	case Rankine:
+		return t.Value * 5 / 9, nil
+	default:
+		return 0, fmt.Errorf("%w: temperature %q", ErrUnknownUnit, t.Unit)
+	}
+}
+
+// ConvertTemperature converts a temperature value between units.
+func ConvertTemperature(value float64, from, to TemperatureUnit) (float64, error) {
+	converted, err := Temperature{Value: value, Unit: from}.To(to)
+	return converted.Value, err
+}
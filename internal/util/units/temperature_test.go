@@ -0,0 +1,44 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvertTemperature(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to TemperatureUnit
+		expected float64
+	}{
+		{"Kelvin to Celsius", 273.15, Kelvin, Celsius, 0},
+		{"Kelvin to Fahrenheit", 273.15, Kelvin, Fahrenheit, 32},
+		{"Celsius to Fahrenheit", 100, Celsius, Fahrenheit, 212},
+		{"Fahrenheit to Kelvin", -459.67, Fahrenheit, Kelvin, 0},
+		{"Fahrenheit to Rankine", 32, Fahrenheit, Rankine, 491.67},
+		{"Rankine to Celsius", 671.67, Rankine, Celsius, 100},
+		{"Same Unit", 21.5, Celsius, Celsius, 21.5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ConvertTemperature(tc.value, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("ConvertTemperature returned an unexpected error: %v", err)
+			}
+			if math.Abs(result-tc.expected) > 1e-9 {
+				t.Errorf("ConvertTemperature(%v, %s, %s) = %v; want %v", tc.value, tc.from, tc.to, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTemperatureSymbol(t *testing.T) {
+	if symbol := Celsius.Symbol(); symbol != "°C" {
+		t.Errorf("Celsius.Symbol() = %q; want °C", symbol)
+	}
+	if symbol := Kelvin.Symbol(); symbol != "K" {
+		t.Errorf("Kelvin.Symbol() = %q; want K", symbol)
+	}
+}
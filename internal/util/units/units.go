@@ -0,0 +1,50 @@
+// Package units provides typed physical quantities and conversions between the units the weather
+// service accepts and returns. Unknown units are always reported as errors rather than guessed.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrUnknownUnit = errors.New("unknown unit")
+	ErrOutOfRange  = errors.New("value out of range for unit")
+)
+
+// linearUnit describes a unit that differs from its quantity's base unit only by a factor.
+type linearUnit struct {
+	symbol string
+	factor float64 // Base units per one of this unit
+}
+
+// parseUnit finds the unit whose identifier matches s case-insensitively.
+func parseUnit[U ~string](s string, known []U, quantity string) (U, error) {
+	for _, unit := range known {
+		if strings.EqualFold(string(unit), s) {
+			return unit, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s %q", ErrUnknownUnit, quantity, s)
+}
+
+// convertLinear converts value between two linear units of the same quantity.
+func convertLinear[U ~string](value float64, from, to U, table map[U]linearUnit, quantity string) (float64, error) {
+	fromUnit, ok := table[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s %q", ErrUnknownUnit, quantity, from)
+	}
+	toUnit, ok := table[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s %q", ErrUnknownUnit, quantity, to)
+	}
+	return value * fromUnit.factor / toUnit.factor, nil
+}
+
+// Round rounds value to the given number of decimal places, hiding floating point noise from conversions.
+func Round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
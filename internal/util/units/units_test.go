@@ -0,0 +1,155 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// approxEqual compares with a tolerance relative to the magnitude of the values.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// pick selects an element of units using an arbitrary generated index.
+func pick[U any](units []U, i uint8) U {
+	return units[int(i)%len(units)]
+}
+
+// finite maps an arbitrary generated float into a realistic, finite range.
+func finite(v float64, limit float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return math.Mod(v, limit)
+}
+
+func TestRoundTripProperties(t *testing.T) {
+	properties := map[string]any{
+		"temperature": func(v float64, from, to uint8) bool {
+			original := Temperature{Value: finite(v, 1e4), Unit: pick(TemperatureUnits, from)}
+			there, err := original.To(pick(TemperatureUnits, to))
+			if err != nil {
+				return false
+			}
+			back, err := there.To(original.Unit)
+			return err == nil && back.Unit == original.Unit && approxEqual(back.Value, original.Value)
+		},
+		"speed": func(v float64, from, to uint8) bool {
+			original := Speed{Value: math.Abs(finite(v, 500)), Unit: pick(SpeedUnits, from)}
+			there, err := original.To(pick(SpeedUnits, to))
+			if err != nil {
+				return false
+			}
+			back, err := there.To(original.Unit)
+			return err == nil && back.Unit == original.Unit && approxEqual(back.Value, original.Value)
+		},
+		"pressure": func(v float64, from, to uint8) bool {
+			original := Pressure{Value: finite(v, 1e5), Unit: pick(PressureUnits, from)}
+			there, err := original.To(pick(PressureUnits, to))
+			if err != nil {
+				return false
+			}
+			back, err := there.To(original.Unit)
+			return err == nil && back.Unit == original.Unit && approxEqual(back.Value, original.Value)
+		},
+		"distance": func(v float64, from, to uint8) bool {
+			original := Distance{Value: finite(v, 1e7), Unit: pick(DistanceUnits, from)}
+			there, err := original.To(pick(DistanceUnits, to))
+			if err != nil {
+				return false
+			}
+			back, err := there.To(original.Unit)
+			return err == nil && back.Unit == original.Unit && approxEqual(back.Value, original.Value)
+		},
+		"precipitation": func(v float64, from, to uint8) bool {
+			original := Precipitation{Value: finite(v, 1e4), Unit: pick(PrecipitationUnits, from)}
+			there, err := original.To(pick(PrecipitationUnits, to))
+			if err != nil {
+				return false
+			}
+			back, err := there.To(original.Unit)
+			return err == nil && back.Unit == original.Unit && approxEqual(back.Value, original.Value)
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestLinearConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		convert  func() (float64, error)
+		expected float64
+	}{
+		{"hPa to inHg", func() (float64, error) { return ConvertPressure(1013.25, Hectopascal, InchesOfMercury) }, 29.9213},
+		{"hPa to kPa", func() (float64, error) { return ConvertPressure(1013.25, Hectopascal, Kilopascal) }, 101.325},
+		{"hPa to mmHg", func() (float64, error) { return ConvertPressure(1013.25, Hectopascal, MillimetersOfMercury) }, 760},
+		{"m to mi", func() (float64, error) { return ConvertDistance(1609.344, Meters, Miles) }, 1},
+		{"m to km", func() (float64, error) { return ConvertDistance(10000, Meters, Kilometers) }, 10},
+		{"nmi to ft", func() (float64, error) { return ConvertDistance(1, NauticalMiles, Feet) }, 6076.115},
+		{"in to mm", func() (float64, error) { return ConvertPrecipitation(1, Inches, Millimeters) }, 25.4},
+		{"mm to cm", func() (float64, error) { return ConvertPrecipitation(12, Millimeters, Centimeters) }, 1.2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.convert()
+			if err != nil {
+				t.Fatalf("conversion returned an unexpected error: %v", err)
+			}
+			if math.Abs(result-tc.expected) > 1e-3 {
+				t.Errorf("conversion = %v; want %v", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUnknownUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func() error
+	}{
+		{"temperature from", func() error { _, err := ConvertTemperature(1, "X", Celsius); return err }},
+		{"temperature to", func() error { _, err := ConvertTemperature(1, Celsius, "X"); return err }},
+		{"speed", func() error { _, err := ConvertSpeed(1, MetersPerSecond, "furlongs"); return err }},
+		{"speed to Beaufort", func() error { _, err := ConvertSpeed(1, "furlongs", Beaufort); return err }},
+		{"pressure", func() error { _, err := ConvertPressure(1, "bar", Hectopascal); return err }},
+		{"distance", func() error { _, err := ConvertDistance(1, Meters, "yd"); return err }},
+		{"precipitation", func() error { _, err := ConvertPrecipitation(1, Millimeters, ""); return err }},
+		{"parse", func() error { _, err := ParsePressureUnit("bar"); return err }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.convert(); !errors.Is(err, ErrUnknownUnit) {
+				t.Errorf("expected ErrUnknownUnit, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseUnitIsCaseInsensitive(t *testing.T) {
+	if unit, err := ParsePressureUnit("INHG"); err != nil || unit != InchesOfMercury {
+		t.Errorf("ParsePressureUnit(INHG) = %q, %v; want %q", unit, err, InchesOfMercury)
+	}
+	if unit, err := ParseTemperatureUnit("r"); err != nil || unit != Rankine {
+		t.Errorf("ParseTemperatureUnit(r) = %q, %v; want %q", unit, err, Rankine)
+	}
+}
+
+func TestRound(t *testing.T) {
+	if result := Round(29.921253, 2); result != 29.92 {
+		t.Errorf("Round(29.921253, 2) = %v; want 29.92", result)
+	}
+	if result := Round(0.1+0.2, 2); result != 0.3 {
+		t.Errorf("Round(0.1+0.2, 2) = %v; want 0.3", result)
+	}
+}
@@ -3,9 +3,14 @@ package util
 import (
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
-func BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement string) (string, error) {
+// BuildOpenWeatherMapURL builds an OpenWeatherMap request URL. lang selects the language of condition
+// descriptions and is omitted when empty, so OpenWeatherMap answers in English. unitOfMeasurement is also
+// omitted when empty, for APIs such as air pollution that have no unit system.
+func BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement, lang string) (string, error) {
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return "", fmt.Errorf("error parsing base URL: %v", err)
@@ -15,8 +20,53 @@ func BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement string)
 	query.Set("lat", lat)
 	query.Set("lon", lon)
 	query.Set("appid", apiKey)
-	query.Set("units", unitOfMeasurement)
+	if unitOfMeasurement != "" {
+		query.Set("units", unitOfMeasurement)
+	}
+	if lang != "" {
+		query.Set("lang", lang)
+	}
+
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String(), nil
+}
+
+// BuildOneCallURL builds an OpenWeatherMap One Call request URL. exclude names the response blocks to leave
+// out, such as "minutely" or "alerts", and is omitted when empty.
+func BuildOneCallURL(baseURL, apiKey, lat, lon, unitOfMeasurement, lang string, exclude []string) (string, error) {
+	builtURL, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement, lang)
+	if err != nil || len(exclude) == 0 {
+		return builtURL, err
+	}
 
+	parsedURL, err := url.Parse(builtURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing built URL: %v", err)
+	}
+	query := parsedURL.Query()
+	query.Set("exclude", strings.Join(exclude, ","))
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String(), nil
+}
+
+// BuildHistoryURL builds an OpenWeatherMap hourly history request URL for the period from start to end, both
+// Unix times.
+func BuildHistoryURL(baseURL, apiKey, lat, lon string, start, end int64) (string, error) {
+	builtURL, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	parsedURL, err := url.Parse(builtURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing built URL: %v", err)
+	}
+	query := parsedURL.Query()
+	query.Set("type", "hour")
+	query.Set("start", strconv.FormatInt(start, 10))
+	query.Set("end", strconv.FormatInt(end, 10))
 	parsedURL.RawQuery = query.Encode()
 
 	return parsedURL.String(), nil
@@ -13,7 +13,7 @@ func TestBuildOpenWeatherMapURL(t *testing.T) {
 
 	expectedURL := "https://api.openweathermap.org/data/2.5/weather?appid=testapikey&lat=35.6895&lon=139.6917&units=metric"
 
-	generatedURL, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement)
+	generatedURL, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement, "")
 	if err != nil {
 		t.Fatalf("BuildOpenWeatherMapURL returned an unexpected error: %v", err)
 	}
@@ -30,8 +30,70 @@ func TestBuildOpenWeatherMapURLWithError(t *testing.T) {
 	lon := "139.6917"
 	unitOfMeasurement := "metric"
 
-	_, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement)
+	_, err := BuildOpenWeatherMapURL(baseURL, apiKey, lat, lon, unitOfMeasurement, "")
 	if err == nil {
 		t.Fatal("Expected error for invalid baseURL, but got nil")
 	}
 }
+
+func TestBuildOpenWeatherMapURLWithoutUnits(t *testing.T) {
+	expectedURL := "https://api.openweathermap.org/data/2.5/air_pollution?appid=testapikey&lat=35.6895&lon=139.6917"
+
+	generatedURL, err := BuildOpenWeatherMapURL("https://api.openweathermap.org/data/2.5/air_pollution", "testapikey", "35.6895", "139.6917", "", "")
+	if err != nil {
+		t.Fatalf("BuildOpenWeatherMapURL returned an unexpected error: %v", err)
+	}
+
+	if generatedURL != expectedURL {
+		t.Errorf("Expected URL to be %v, got %v", expectedURL, generatedURL)
+	}
+}
+
+func TestBuildOpenWeatherMapURLWithLanguage(t *testing.T) {
+	baseURL := "https://api.openweathermap.org/data/2.5/weather"
+
+	expectedURL := "https://api.openweathermap.org/data/2.5/weather?appid=testapikey&lang=de&lat=35.6895&lon=139.6917&units=standard"
+
+	generatedURL, err := BuildOpenWeatherMapURL(baseURL, "testapikey", "35.6895", "139.6917", "standard", "de")
+	if err != nil {
+		t.Fatalf("BuildOpenWeatherMapURL returned an unexpected error: %v", err)
+	}
+
+	if generatedURL != expectedURL {
+		t.Errorf("Expected URL to be %v, got %v", expectedURL, generatedURL)
+	}
+}
+
+func TestBuildOneCallURL(t *testing.T) {
+	baseURL := "https://api.openweathermap.org/data/3.0/onecall"
+
+	expectedURL := "https://api.openweathermap.org/data/3.0/onecall?appid=testapikey&exclude=minutely%2Chourly&lat=35.6895&lon=139.6917&units=standard"
+
+	generatedURL, err := BuildOneCallURL(baseURL, "testapikey", "35.6895", "139.6917", "standard", "", []string{"minutely", "hourly"})
+	if err != nil {
+		t.Fatalf("BuildOneCallURL returned an unexpected error: %v", err)
+	}
+
+	if generatedURL != expectedURL {
+		t.Errorf("Expected URL to be %v, got %v", expectedURL, generatedURL)
+	}
+
+	// Without exclusions every block is returned
+	generatedURL, _ = BuildOneCallURL(baseURL, "testapikey", "35.6895", "139.6917", "standard", "", nil)
+	if generatedURL != "https://api.openweathermap.org/data/3.0/onecall?appid=testapikey&lat=35.6895&lon=139.6917&units=standard" {
+		t.Errorf("Expected no exclude parameter, got %v", generatedURL)
+	}
+}
+
+func TestBuildHistoryURL(t *testing.T) {
+	expectedURL := "https://history.openweathermap.org/data/2.5/history/city?appid=testapikey&end=1704153600&lat=35.6895&lon=139.6917&start=1704067200&type=hour"
+
+	generatedURL, err := BuildHistoryURL("https://history.openweathermap.org/data/2.5/history/city", "testapikey", "35.6895", "139.6917", 1704067200, 1704153600)
+	if err != nil {
+		t.Fatalf("BuildHistoryURL returned an unexpected error: %v", err)
+	}
+
+	if generatedURL != expectedURL {
+		t.Errorf("Expected URL to be %v, got %v", expectedURL, generatedURL)
+	}
+}
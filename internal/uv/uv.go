@@ -0,0 +1,90 @@
+// Package uv classifies the UV index as the WHO does and estimates safe sun exposure by skin type.
+package uv
+
+import "math"
+
+// erythemalIrradiance is the erythemally weighted irradiance of one UV index unit, in W/m².
+const erythemalIrradiance = 0.025
+
+// WHO UV index categories
+const (
+	Low      = "Low"
+	Moderate = "Moderate"
+	High     = "High"
+	VeryHigh = "Very High"
+	Extreme  = "Extreme"
+)
+
+// categories are the WHO categories with their highest rounded UV index and sun protection advice. Above the
+// last of them, the UV index is Extreme.
+var categories = []struct {
+	upTo         int
+	name, advice string
+}{
+	{2, Low, "No protection needed. You can safely stay outside."},
+	{5, Moderate, "Protection needed. Seek shade during midday hours, cover up and wear sunscreen."},
+	{7, High, "Protection needed. Seek shade during midday hours, cover up and wear sunscreen."},
+	{10, VeryHigh, "Extra protection needed. Avoid being outside during midday hours; shade, clothing, a hat and sunscreen are a must."},
+}
+
+// extremeAdvice is the WHO sun protection advice for an Extreme UV index.
+const extremeAdvice = "Extra protection needed. Avoid being outside during midday hours and make sure you seek shade; a shirt, sunscreen and a hat are a must. Unprotected skin can burn within minutes."
+
+// Category returns the WHO category of a UV index, which the WHO reports rounded to a whole number.
+func Category(uvi float64) string {
+	name, _ := classify(uvi)
+	return name
+}
+
+// Advice returns the WHO sun protection advice for a UV index.
+func Advice(uvi float64) string {
+	_, advice := classify(uvi)
+	return advice
+}
+
+func classify(uvi float64) (string, string) {
+	rounded := int(math.Round(uvi))
+	for _, category := range categories {
+		if rounded <= category.upTo {
+			return category.name, category.advice
+		}
+	}
+	return Extreme, extremeAdvice
+}
+
+// ClearSky estimates the UV index under a clear sky from the solar elevation in degrees, with the empirical
+// fit UVI = 12.5 sin(elevation)^2.42. It ignores ozone, altitude and surface reflection, so it is only a
+// rough substitute for measured or forecast values.
+func ClearSky(elevation float64) float64 {
+	if elevation <= 0 {
+		return 0
+	}
+	return 12.5 * math.Pow(math.Sin(elevation*math.Pi/180), 2.42)
+}
+
+// SkinType is a Fitzpatrick skin type with its typical minimal erythemal dose (MED), the UV dose that
+// reddens unprotected skin.
+type SkinType struct {
+	Type        string  `json:"skinType"` // Roman numeral, I to VI
+	Description string  `json:"description"`
+	MED         float64 `json:"med"` // J/m², erythemally weighted
+}
+
+// SkinTypes lists the Fitzpatrick skin types.
+var SkinTypes = []SkinType{
+	{"I", "Always burns, never tans", 200},
+	{"II", "Usually burns, tans minimally", 250},
+	{"III", "Sometimes burns mildly, tans gradually", 300},
+	{"IV", "Burns minimally, always tans well", 450},
+	{"V", "Very rarely burns, tans profusely", 600},
+	{"VI", "Never burns, deeply pigmented", 1000},
+}
+
+// SafeMinutes returns the whole minutes of unprotected exposure at a constant UV index before the dose reaches
+// med. It returns false when there is no meaningful UV.
+func SafeMinutes(uvi, med float64) (int, bool) {
+	if uvi <= 0 {
+		return 0, false
+	}
+	return int(med / (uvi * erythemalIrradiance * 60)), true
+}
@@ -0,0 +1,76 @@
+package uv
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		uvi      float64
+		expected string
+	}{
+		{0, Low},
+		{2.4, Low},
+		{2.5, Moderate}, // The WHO rounds the index first
+		{5, Moderate},
+		{7.4, High},
+		{8, VeryHigh},
+		{10.4, VeryHigh},
+		{11, Extreme},
+		{16, Extreme},
+	}
+
+	for _, tc := range tests {
+		if got := Category(tc.uvi); got != tc.expected {
+			t.Errorf("Category(%v) = %q; want %q", tc.uvi, got, tc.expected)
+		}
+	}
+
+	if Advice(1) == Advice(11) {
+		t.Errorf("Expected low and extreme UV to give different advice")
+	}
+	if Advice(9) == Advice(11) {
+		t.Errorf("Expected very high and extreme UV to give different advice")
+	}
+}
+
+func TestClearSky(t *testing.T) {
+	tests := []struct {
+		elevation float64
+		expected  float64
+	}{
+		{-5, 0},
+		{0, 0},
+		{30, 2.34},
+		{60, 8.83},
+		{90, 12.5},
+	}
+
+	for _, tc := range tests {
+		if got := ClearSky(tc.elevation); math.Abs(got-tc.expected) > 0.01 {
+			t.Errorf("ClearSky(%v) = %.3f; want %v", tc.elevation, got, tc.expected)
+		}
+	}
+}
+
+func TestSafeMinutes(t *testing.T) {
+	tests := []struct {
+		uvi, med float64
+		expected int
+		ok       bool
+	}{
+		{5, 200, 26, true}, // 200 J/m² / (0.125 W/m² × 60 s)
+		{10, 200, 13, true},
+		{10, 1000, 66, true},
+		{1, 300, 200, true},
+		{0, 200, 0, false},
+	}
+
+	for _, tc := range tests {
+		minutes, ok := SafeMinutes(tc.uvi, tc.med)
+		if minutes != tc.expected || ok != tc.ok {
+			t.Errorf("SafeMinutes(%v, %v) = %d, %v; want %d, %v", tc.uvi, tc.med, minutes, ok, tc.expected, tc.ok)
+		}
+	}
+}